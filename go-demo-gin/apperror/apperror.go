@@ -0,0 +1,109 @@
+// Package apperror: lỗi nghiệp vụ trả về từ service, độc lập với transport (HTTP, gRPC, CLI).
+// Mỗi lỗi có Kind (loại lỗi), Code (i18n message, dùng làm mã lỗi ổn định) và nguyên nhân gốc.
+package apperror
+
+import (
+	"errors"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindTooManyRequests
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindValidation:
+		return "validation"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindTooManyRequests:
+		return "too_many_requests"
+	default:
+		return "internal"
+	}
+}
+
+type Error struct {
+	Kind   Kind
+	Code   *i18n.Message            // nil => transport tự chọn mã mặc định theo Kind
+	Fields map[string]*i18n.Message // lỗi theo field (KindValidation)
+	Err    error                    // nguyên nhân gốc, chỉ dùng để log
+}
+
+func (e *Error) Error() string {
+	msg := e.Kind.String()
+	if e.Code != nil {
+		msg = e.Code.ID
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func New(kind Kind, code *i18n.Message, cause error) *Error {
+	return &Error{Kind: kind, Code: code, Err: cause}
+}
+
+func NotFound(code *i18n.Message, cause error) *Error {
+	return New(KindNotFound, code, cause)
+}
+
+func Conflict(code *i18n.Message, cause error) *Error {
+	return New(KindConflict, code, cause)
+}
+
+func Validation(code *i18n.Message, fields map[string]*i18n.Message) *Error {
+	return &Error{Kind: KindValidation, Code: code, Fields: fields}
+}
+
+func Unauthorized(code *i18n.Message, cause error) *Error {
+	return New(KindUnauthorized, code, cause)
+}
+
+func Forbidden(code *i18n.Message, cause error) *Error {
+	return New(KindForbidden, code, cause)
+}
+
+func TooManyRequests(code *i18n.Message, cause error) *Error {
+	return New(KindTooManyRequests, code, cause)
+}
+
+func Internal(code *i18n.Message, cause error) *Error {
+	return New(KindInternal, code, cause)
+}
+
+// As tìm *Error trong chuỗi wrap của err
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+// KindOf trả về loại lỗi; lỗi không phải *Error được coi là KindInternal
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
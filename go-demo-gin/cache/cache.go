@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache: backend lưu trữ dạng key/value có TTL (bộ nhớ trong process hoặc Redis)
+type Cache interface {
+	// Get trả về ok = false nếu key không tồn tại hoặc đã hết hạn
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	c.Get(ctx, "a") // "a" mới dùng => "b" bị xoá khi thêm "c"
+	c.Set(ctx, "c", []byte("3"), time.Minute)
+
+	_, ok, _ := c.Get(ctx, "b")
+	assert.False(t, ok)
+	v, ok, _ := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(v))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_ExpiresAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "k", []byte("v"), time.Minute)
+	_, ok, _ := c.Get(ctx, "k")
+	assert.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok, _ = c.Get(ctx, "k")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestRedis_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	c := NewRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "cache:")
+
+	_, ok, err := c.Get(ctx, "k")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Set(ctx, "k", []byte("v"), time.Minute))
+	assert.True(t, mr.Exists("cache:k"))
+	v, ok, err := c.Get(ctx, "k")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "v", string(v))
+
+	mr.FastForward(2 * time.Minute)
+	_, ok, _ = c.Get(ctx, "k")
+	assert.False(t, ok)
+
+	c.Set(ctx, "k", []byte("v"), time.Minute)
+	assert.NoError(t, c.Delete(ctx, "k"))
+	_, ok, _ = c.Get(ctx, "k")
+	assert.False(t, ok)
+}
+
+func TestHitRatio(t *testing.T) {
+	RecordHit("test")
+	RecordHit("test")
+	RecordHit("test")
+	RecordMiss("test")
+	assert.Equal(t, 0.75, HitRatio("test"))
+	assert.Equal(t, float64(0), HitRatio("unknown"))
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"expvar"
+	"strings"
+)
+
+// Số lần hit/miss theo tên cache, xem qua expvar ("cache" và "cache_hit_ratio")
+var counters = expvar.NewMap("cache")
+
+func init() {
+	expvar.Publish("cache_hit_ratio", expvar.Func(hitRatios))
+}
+
+func RecordHit(name string)  { counters.Add(name+".hits", 1) }
+func RecordMiss(name string) { counters.Add(name+".misses", 1) }
+
+// HitRatio = hits / (hits + misses); 0 nếu chưa có lượt truy cập nào
+func HitRatio(name string) float64 {
+	hits, misses := counterValue(name+".hits"), counterValue(name+".misses")
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+func hitRatios() any {
+	out := make(map[string]float64)
+	counters.Do(func(kv expvar.KeyValue) {
+		if name, ok := strings.CutSuffix(kv.Key, ".hits"); ok {
+			out[name] = HitRatio(name)
+		}
+	})
+	return out
+}
+
+func counterValue(key string) int64 {
+	if v, ok := counters.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	apiKeyRequest "go-demo-gin/requests/apikey"
+	apiKeyResponse "go-demo-gin/responses/apikey"
+	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ apiKeyResponse.APIKeyDetail
+	_ errorResponse.Problem
+)
+
+type APIKeyService interface {
+	CreateKey(ctx context.Context, in *apiKeyRequest.APIKeyCreate) (*apiKeyResponse.APIKeyDetail, error)
+	GetKeyList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error)
+	RevokeKey(ctx context.Context, id string) error
+}
+
+type APIKeyController struct {
+	v   *utils.Validator
+	svc APIKeyService
+}
+
+func NewAPIKeyController(v *utils.Validator, svc APIKeyService) *APIKeyController {
+	return &APIKeyController{v: v, svc: svc}
+}
+
+// APIKeysCreate creates a personal API key for the current user
+//
+// @Summary      Create API key
+// @Description  Create a named API key with scopes and expiry for machine-to-machine access. The full key is only returned once; send it as "Authorization: ApiKey <key>" or "X-API-Key: <key>".
+// @Tags         🔑API keys
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      apiKeyRequest.APIKeyCreate  true  "API key to create"
+// @Success      201      {object}  apiKeyResponse.APIKeyDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      401      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/api-keys [post]
+func (h *APIKeyController) APIKeysCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create api key controller", nil)
+
+	// Get data off request body
+	var create apiKeyRequest.APIKeyCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create api key
+	detail, err := h.svc.CreateKey(ctx, &create)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create api key failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// APIKeysIndex lists API keys of the current user
+//
+// @Summary      List API keys
+// @Description  Get list of the current user's API keys (the key itself is never returned again)
+// @Tags         🔑API keys
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]apiKeyResponse.APIKeyDetail}
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/api-keys [get]
+func (h *APIKeyController) APIKeysIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of api keys controller", nil)
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get api key list
+	result, err := h.svc.GetKeyList(ctx, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of api keys failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// APIKeysRevoke revokes an API key
+//
+// @Summary      Revoke API key
+// @Description  Revoke an API key of the current user (admins can revoke any key). Revoking twice is a no-op.
+// @Tags         🔑API keys
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "API key ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/api-keys/{id} [delete]
+func (h *APIKeyController) APIKeysRevoke(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke api key controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Revoke api key
+	if err := h.svc.RevokeKey(ctx, id); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Revoke api key failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
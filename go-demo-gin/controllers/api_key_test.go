@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/repo"
+	apiKeyResponse "go-demo-gin/responses/apikey"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type apiKeyEnv struct {
+	r   *gin.Engine
+	db  *gorm.DB
+	jwt string // token của admin
+}
+
+// Router có route quản lý key và 2 route users giả lập để kiểm tra xác thực bằng key + scope
+func setupAPIKeyRouter(t *testing.T) apiKeyEnv {
+	t.Helper()
+	t.Setenv("SECRET", "test-secret")
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, db.AutoMigrate(&models.APIKey{}))
+
+	ur := repo.NewGormUserRepo(db)
+	svc := services.NewAPIKeyService(repo.NewGormAPIKeyRepo(db), ur)
+	h := NewAPIKeyController(utils.NewValidator(db), svc)
+	auth := middlewares.Authentication(ur, svc, nil)
+	all := []models.Role{models.RoleAdmin, models.RoleStaff, models.RoleCustomer}
+
+	keys := r.Group("/api/v1/api-keys", auth(all...), middlewares.RequireScope(models.ScopeSession))
+	keys.POST("", h.APIKeysCreate)
+	keys.GET("", h.APIKeysIndex)
+	keys.DELETE("/:id", h.APIKeysRevoke)
+	ok := func(c *gin.Context) { c.String(http.StatusOK, utils.InformationFrom(c.Request.Context()).Username) }
+	r.GET("/api/v1/users", auth(all...), middlewares.RequireScope(models.ScopeUsersRead), ok)
+	r.DELETE("/api/v1/users/:id", auth(models.RoleAdmin), middlewares.RequireScope(models.ScopeUsersWrite), ok)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin", "id": 1}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	return apiKeyEnv{r: r, db: db, jwt: token}
+}
+
+func (e apiKeyEnv) do(method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+func (e apiKeyEnv) createKey(t *testing.T, body string) apiKeyResponse.APIKeyDetail {
+	t.Helper()
+	w := e.do(http.MethodPost, "/api/v1/api-keys", body, "Authorization", "Bearer "+e.jwt)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var detail apiKeyResponse.APIKeyDetail
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	return detail
+}
+
+func TestAPIKeys_CreateListRevoke(t *testing.T) {
+	e := setupAPIKeyRouter(t)
+
+	w := e.do(http.MethodPost, "/api/v1/api-keys", `{"scopes": ["users:delete"]}`, "Authorization", "Bearer "+e.jwt)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), utils.NAME_REQUIRE.ID)
+	assert.Contains(t, w.Body.String(), utils.INVALID_SCOPE.ID)
+
+	detail := e.createKey(t, `{"name": "CI", "scopes": ["users:read"]}`)
+	require.NotEmpty(t, detail.Key)
+	assert.Contains(t, detail.Key, detail.Prefix+"_")
+	assert.Equal(t, []string{models.ScopeUsersRead}, detail.Scopes)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 90), detail.ExpiresAt, time.Minute)
+
+	// Key chỉ lưu dạng hash
+	var stored models.APIKey
+	require.NoError(t, e.db.First(&stored, detail.ID).Error)
+	assert.NotContains(t, stored.Hash, detail.Key)
+	assert.NotEqual(t, detail.Key, stored.Hash)
+
+	// List không trả lại key
+	w = e.do(http.MethodGet, "/api/v1/api-keys", "", "Authorization", "Bearer "+e.jwt)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var page struct {
+		pkg.Pagination
+		Result []apiKeyResponse.APIKeyDetail `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.Result, 1)
+	assert.Empty(t, page.Result[0].Key)
+
+	// Key không quản lý được key
+	w = e.do(http.MethodGet, "/api/v1/api-keys", "", "X-API-Key", detail.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.INSUFFICIENT_SCOPE.ID, problemCode(t, w))
+
+	w = e.do(http.MethodDelete, "/api/v1/api-keys/"+strconv.FormatUint(uint64(detail.ID), 10), "", "Authorization", "Bearer "+e.jwt)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = e.do(http.MethodDelete, "/api/v1/api-keys/"+strconv.FormatUint(uint64(detail.ID), 10), "", "Authorization", "Bearer "+e.jwt)
+	assert.Equal(t, http.StatusNoContent, w.Code) // thu hồi lại => không lỗi
+	w = e.do(http.MethodDelete, "/api/v1/api-keys/999", "", "Authorization", "Bearer "+e.jwt)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Key đã thu hồi bị từ chối
+	w = e.do(http.MethodGet, "/api/v1/users", "", "Authorization", "ApiKey "+detail.Key)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.INVALID_API_KEY.ID, problemCode(t, w))
+}
+
+func TestAPIKeys_AuthenticateAndScopes(t *testing.T) {
+	e := setupAPIKeyRouter(t)
+	detail := e.createKey(t, `{"name": "Reporting", "scopes": ["users:read"], "expires_in_days": 1}`)
+
+	// Cả 2 cách gửi key đều xác thực thành user sở hữu
+	for _, h := range [][]string{{"Authorization", "ApiKey " + detail.Key}, {"X-API-Key", detail.Key}} {
+		w := e.do(http.MethodGet, "/api/v1/users", "", h...)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "admin", w.Body.String())
+	}
+	var stored models.APIKey
+	require.NoError(t, e.db.First(&stored, detail.ID).Error)
+	require.NotNil(t, stored.LastUsedAt)
+
+	// Thiếu scope users:write
+	w := e.do(http.MethodDelete, "/api/v1/users/2", "", "X-API-Key", detail.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.INSUFFICIENT_SCOPE.ID, problemCode(t, w))
+	// JWT không bị giới hạn bởi scope
+	w = e.do(http.MethodDelete, "/api/v1/users/2", "", "Authorization", "Bearer "+e.jwt)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Sai phần bí mật
+	w = e.do(http.MethodGet, "/api/v1/users", "", "X-API-Key", detail.Key[:len(detail.Key)-2]+"xx")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.INVALID_API_KEY.ID, problemCode(t, w))
+
+	// Hết hạn
+	require.NoError(t, e.db.Model(&models.APIKey{}).Where("id = ?", detail.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	w = e.do(http.MethodGet, "/api/v1/users", "", "X-API-Key", detail.Key)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.API_KEY_EXPIRED.ID, problemCode(t, w))
+
+	// Owner bị vô hiệu hoá => key cũng bị từ chối
+	other := e.createKey(t, `{"name": "Other", "scopes": ["users:read"]}`)
+	require.NoError(t, e.db.Model(&models.User{}).Where("username = ?", "admin").Update("status", models.UserDisabled).Error)
+	w = e.do(http.MethodGet, "/api/v1/users", "", "X-API-Key", other.Key)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
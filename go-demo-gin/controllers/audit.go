@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	auditRequest "go-demo-gin/requests/audit"
+	auditResponse "go-demo-gin/responses/audit"
+	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ auditResponse.AuditEvent
+	_ errorResponse.Problem
+)
+
+type AuditService interface {
+	GetAuditList(ctx context.Context, pag *pkg.Pagination, f *auditRequest.AuditFilter) (*pkg.Pagination, error)
+}
+
+type AuditController struct {
+	svc AuditService
+}
+
+func NewAuditController(svc AuditService) *AuditController {
+	return &AuditController{svc: svc}
+}
+
+// AuditIndex lists audit events
+//
+// @Summary      List audit events
+// @Description  Get list of audit events (who changed which user fields and when)
+// @Tags         🕵🏻Audit
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        actor_id		query     int     false  "Filter by actor ID"
+// @Param        impersonator_id	query     int     false  "Filter by impersonating admin ID"
+// @Param        action			query     string  false  "Filter by action (user.create, user.update, user.role_change, user.delete, user.impersonate)"
+// @Param        target_type	query     string  false  "Filter by target type"
+// @Param        target_id		query     int     false  "Filter by target ID"
+// @Param        from			query     string  false  "Created at or after (RFC3339)"
+// @Param        to				query     string  false  "Created before (RFC3339)"
+// @Param        limit			query     string  false  "Number of results per page"				default(10)
+// @Param        page			query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort			query     string  false  "Sorting criteria: id, created_at, actor_id, impersonator_id, action, target_type or target_id, optionally followed by asc/desc"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]auditResponse.AuditEvent}
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/audit [get]
+func (h *AuditController) AuditIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of audit events controller", nil)
+
+	// Get filter in query string
+	var filter auditRequest.AuditFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get audit list
+	result, err := h.svc.GetAuditList(ctx, &pag, &filter)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of audit events failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
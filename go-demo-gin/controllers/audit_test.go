@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/initializers"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	auditResponse "go-demo-gin/responses/audit"
+	"go-demo-gin/services"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Router chỉ có route audit (sau tenant resolver), dữ liệu audit được seed sẵn; tenant mặc định và acme (id 2)
+func setupAuditRouter(t *testing.T, seed ...models.AuditEvent) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, initializers.LoadI18n())
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, repo.RegisterTenantScope(db))
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.AuditEvent{}))
+	require.NoError(t, db.Create(&models.Tenant{ID: models.DefaultTenantID, Slug: "default", Name: "Default"}).Error)
+	require.NoError(t, db.Create(&models.Tenant{Slug: "acme", Name: "Acme"}).Error)
+	for i := range seed {
+		require.NoError(t, db.Create(&seed[i]).Error)
+	}
+
+	h := NewAuditController(services.NewAuditService(db, repo.NewGormAuditRepo(db)))
+	r := gin.New()
+	r.Use(middlewares.ErrorHandler(), middlewares.I18n())
+	r.Use(middlewares.ResolveTenant(services.NewTenantService(repo.NewGormTenantRepo(db), cache.NewLRU(10)), ""))
+	r.GET("/api/v1/audit", h.AuditIndex)
+	return r
+}
+
+// Gọi GET /api/v1/audit (tenant chọn bằng X-Tenant, rỗng => tenant mặc định) và trả về danh sách target_id theo thứ tự id tăng dần
+func auditTargets(t *testing.T, r *gin.Engine, query url.Values, tenant ...string) []uint {
+	t.Helper()
+	query.Set("sort", "id asc")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit?"+query.Encode(), nil)
+	if len(tenant) > 0 {
+		req.Header.Set(middlewares.TenantHeader, tenant[0])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var body struct {
+		Result []auditResponse.AuditEvent `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	ids := make([]uint, 0, len(body.Result))
+	for _, e := range body.Result {
+		ids = append(ids, e.TargetID)
+	}
+	return ids
+}
+
+func TestAuditIndex_Filters(t *testing.T) {
+	admin, staff := uint(1), uint(2)
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	r := setupAuditRouter(t,
+		models.AuditEvent{CreatedAt: day, ActorID: &admin, Action: models.AuditUserCreate, TargetType: "user", TargetID: 10, Changes: "{}"},
+		models.AuditEvent{CreatedAt: day.Add(24 * time.Hour), ActorID: &admin, Action: models.AuditUserUpdate, TargetType: "user", TargetID: 11, Changes: "{}"},
+		models.AuditEvent{CreatedAt: day.Add(48 * time.Hour), ActorID: &staff, Action: models.AuditUserUpdate, TargetType: "user", TargetID: 12, Changes: "{}"},
+		models.AuditEvent{CreatedAt: day.Add(72 * time.Hour), ActorID: &staff, Action: models.AuditUserDelete, TargetType: "user", TargetID: 10, Changes: "{}"},
+	)
+
+	assert.Equal(t, []uint{10, 11, 12, 10}, auditTargets(t, r, url.Values{}))
+	assert.Equal(t, []uint{12, 10}, auditTargets(t, r, url.Values{"actor_id": {"2"}}))
+	assert.Equal(t, []uint{11, 12}, auditTargets(t, r, url.Values{"action": {"user.update"}}))
+	assert.Equal(t, []uint{10, 10}, auditTargets(t, r, url.Values{"target_type": {"user"}, "target_id": {"10"}}))
+
+	// from là cận dưới (bao gồm), to là cận trên (không bao gồm)
+	assert.Equal(t, []uint{11, 12}, auditTargets(t, r, url.Values{
+		"from": {day.Add(24 * time.Hour).Format(time.RFC3339)},
+		"to":   {day.Add(72 * time.Hour).Format(time.RFC3339)},
+	}))
+	// Kết hợp nhiều bộ lọc
+	assert.Equal(t, []uint{12}, auditTargets(t, r, url.Values{"actor_id": {"2"}, "action": {"user.update"}}))
+}
+
+func TestAuditIndex_TenantIsolated(t *testing.T) {
+	r := setupAuditRouter(t,
+		models.AuditEvent{TenantID: models.DefaultTenantID, Action: models.AuditUserCreate, TargetType: "user", TargetID: 10, Changes: "{}"},
+		models.AuditEvent{TenantID: 2, Action: models.AuditUserCreate, TargetType: "user", TargetID: 20, Changes: "{}"},
+		models.AuditEvent{TenantID: 2, Action: models.AuditUserDelete, TargetType: "user", TargetID: 20, Changes: "{}"},
+	)
+
+	assert.Equal(t, []uint{10}, auditTargets(t, r, url.Values{}))
+	assert.Equal(t, []uint{20, 20}, auditTargets(t, r, url.Values{}, "acme"))
+	// Bộ lọc không vượt qua được tenant
+	assert.Empty(t, auditTargets(t, r, url.Values{"target_id": {"20"}}))
+}
+
+func TestAuditIndex_InvalidFilter(t *testing.T) {
+	r := setupAuditRouter(t)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?from=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// sort chỉ nhận cột cho phép (chặn SQL tuỳ ý trong ORDER BY)
+	for _, sort := range []string{"ip desc", "id desc; DROP TABLE audit_events", "(SELECT 1)", "created_at sideways"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?"+url.Values{"sort": {sort}}.Encode(), nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, sort)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/audit?sort=created_at+DESC", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -5,13 +5,14 @@ import (
 	authenRequest "go-demo-gin/requests/authen"
 	errorResponse "go-demo-gin/responses/error"
 	"go-demo-gin/utils"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	_ errorResponse.HTTPError
+	_ errorResponse.Problem
 )
 
 type TokenResponse struct {
@@ -23,7 +24,7 @@ type AuthController struct {
 }
 
 type AuthService interface {
-	Authenticate(ctx context.Context, in *authenRequest.LoginForm) (*string, int, string)
+	Authenticate(ctx context.Context, in *authenRequest.LoginForm) (*string, error)
 }
 
 func NewAuthController(svc AuthService) *AuthController {
@@ -39,8 +40,9 @@ func NewAuthController(svc AuthService) *AuthController {
 // @Produce      json
 // @Param        request  body      authenRequest.LoginForm  true  "Login form"
 // @Success      200      {object}  TokenResponse
-// @Failure      400      {object}  errorResponse.HTTPError
-// @Failure      500      {string}  httputil.HTTPError
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      401      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
 // @Router       /api/v1/authen/login [post]
 func (h *AuthController) Login(c *gin.Context) {
 	// Logging
@@ -57,18 +59,18 @@ func (h *AuthController) Login(c *gin.Context) {
 	}
 
 	// Check user infor & generate jwt token
-	token, status, err := h.svc.Authenticate(ctx, &authen)
-	if token == nil || err != "" {
-		utils.HandleServiceError(c, status, err)
+	token, err := h.svc.Authenticate(ctx, &authen)
+	if err != nil {
+		utils.HandleServiceError(c, err)
 		// Logging
-		utils.LogCtx(ctx, logrus.ErrorLevel, "Authtication failed: "+err, nil)
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Authtication failed: "+err.Error(), nil)
 		return
 	}
 
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Authtication successful for user: "+authen.Username, nil)
 	// Send it back
-	c.JSON(status, TokenResponse{
+	c.JSON(http.StatusOK, TokenResponse{
 		Token: token,
 	})
 }
@@ -39,9 +39,9 @@ func TestLogin_BindingError_Returns400(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
-	// Tuỳ utils.HandleBindError trả về cấu trúc JSON gì,
-	// ở đây chỉ check có chữ "error" hoặc thông điệp parse JSON
-	assert.Contains(t, strings.ToLower(w.Body.String()), "error")
+	// JSON hỏng => problem+json với mã INVALID_REQUEST_BODY
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/problem+json")
+	assert.Contains(t, w.Body.String(), `"code":"INVALID_REQUEST_BODY"`)
 }
 
 func TestLogin_ServiceError_Returns400(t *testing.T) {
@@ -62,7 +62,7 @@ func TestLogin_ServiceError_Returns400(t *testing.T) {
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
-	// Tuỳ utils.HandleBindError trả về cấu trúc JSON gì,
-	// ở đây chỉ check có chữ "error" hoặc thông điệp parse JSON
+	// Thiếu username/password => lỗi theo field
+	assert.Contains(t, w.Body.String(), `"code":"VALIDATION_FAILED"`)
 	assert.Contains(t, strings.ToLower(w.Body.String()), "error")
 }
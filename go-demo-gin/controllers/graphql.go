@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"go-demo-gin/graph"
+	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/graphql-go/graphql"
+	"github.com/sirupsen/logrus"
+)
+
+var _ errorResponse.Problem
+
+type GraphQLController struct {
+	schema graphql.Schema
+	limits graph.Limits
+}
+
+func NewGraphQLController(schema graphql.Schema, limits graph.Limits) *GraphQLController {
+	return &GraphQLController{schema: schema, limits: limits}
+}
+
+// GraphQL executes a GraphQL query or mutation
+//
+// @Summary      GraphQL endpoint
+// @Description  Query users (`users`, `user`, `me`) and run user mutations with field selection. Queries use the users:read rate limit and scope, mutations the users:write ones. Errors are returned in the `errors` array with `extensions.code`.
+// @Tags         🕸️GraphQL
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      graph.Request  true  "GraphQL request"
+// @Success      200      {object}  object         "GraphQL result (data, errors)"
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      401      {object}  errorResponse.Problem
+// @Router       /graphql [post]
+func (h *GraphQLController) GraphQL(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the graphql controller", nil)
+
+	// Get data off request body
+	var req graph.Request
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Lỗi resolver/validation nằm trong "errors" của kết quả (chuẩn GraphQL), status vẫn là 200
+	result := graph.Execute(ctx, h.schema, req, h.limits)
+	c.JSON(http.StatusOK, result)
+}
+
+// ByOperation chạy middleware theo loại operation của request: mutation => mutation, còn lại => query.
+// Dùng để áp rate limit của thao tác ghi cho mutation (body được cache để handler GraphQL đọc lại).
+func (h *GraphQLController) ByOperation(query, mutation gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req graph.Request
+		if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+			utils.HandleBindError(c, err)
+			c.Abort()
+			return
+		}
+		if graph.IsMutation(req) {
+			mutation(c)
+			return
+		}
+		query(c)
+	}
+}
+
+// Playground trả về trang GraphiQL (chỉ đăng ký route khi không chạy ở release mode)
+func (h *GraphQLController) Playground(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(playgroundHTML))
+}
+
+const playgroundHTML = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8" />
+  <title>GraphQL Playground</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css" />
+</head>
+<body style="margin: 0;">
+  <div id="graphiql" style="height: 100vh;"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    // Nhập token ở tab Headers: {"Authorization": "Bearer <token>"}
+    const fetcher = GraphiQL.createFetcher({ url: window.location.pathname });
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(
+      React.createElement(GraphiQL, { fetcher: fetcher, defaultEditorToolsVisibility: 'headers' })
+    );
+  </script>
+</body>
+</html>`
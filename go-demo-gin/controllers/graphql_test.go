@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-demo-gin/graph"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// POST /graphql với rate limit theo loại operation; người gọi là admin dùng API key chỉ có scope users:write
+func setupGraphQLRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+	v := utils.NewValidator(db)
+	schema, err := graph.NewSchema(v, services.NewUserService(db, repo.NewGormUserRepo(db), repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db)))
+	require.NoError(t, err)
+	gc := NewGraphQLController(schema, graph.Limits{MaxDepth: 5, MaxComplexity: 1000})
+
+	var admin models.User
+	require.NoError(t, db.First(&admin, "username = ?", "admin").Error)
+	asKey := func(c *gin.Context) {
+		ctx := utils.WithInformation(c.Request.Context(), &admin)
+		ctx = utils.WithAPIKey(ctx, &models.APIKey{Scopes: models.ScopeUsersWrite})
+		c.Request = c.Request.WithContext(ctx)
+	}
+	Limit := middlewares.RateLimiter(middlewares.NewMemoryRateLimitStore())
+	read := middlewares.RatePolicy{Name: "read", Default: middlewares.RateLimit{Limit: 100, Period: time.Minute}}
+	write := middlewares.RatePolicy{Name: "write", Default: middlewares.RateLimit{Limit: 1, Period: time.Minute}}
+	r.POST("/graphql", asKey, gc.ByOperation(Limit(read), Limit(write)), gc.GraphQL)
+	return r
+}
+
+func postGraphQL(r *gin.Engine, query string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(graph.Request{Query: query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGraphQL_RateLimitAndScopePerOperation(t *testing.T) {
+	r := setupGraphQLRouter(t)
+	create := `mutation { createUser(input: {username: "gql1", password: "secret.123", role: "staff", birthday: "2000-01-02"}) { username } }`
+
+	// Key chỉ có users:write vẫn chạy được mutation (scope kiểm tra theo field), nhưng không đọc được
+	w := postGraphQL(r, create)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"username":"gql1"`)
+	w = postGraphQL(r, `{ users { nodes { id } } }`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), utils.INSUFFICIENT_SCOPE.ID)
+
+	// Mutation tính vào policy ghi (1/phút), query không bị ảnh hưởng
+	w = postGraphQL(r, `mutation { deleteUser(id: "1") }`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
+	w = postGraphQL(r, `{ me { username } }`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
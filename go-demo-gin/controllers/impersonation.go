@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"context"
+	errorResponse "go-demo-gin/responses/error"
+	impersonationResponse "go-demo-gin/responses/impersonation"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ impersonationResponse.ImpersonationDetail
+	_ errorResponse.Problem
+)
+
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, id string) (*impersonationResponse.ImpersonationDetail, error)
+}
+
+type ImpersonationController struct {
+	svc ImpersonationService
+}
+
+func NewImpersonationController(svc ImpersonationService) *ImpersonationController {
+	return &ImpersonationController{svc: svc}
+}
+
+// Impersonate logs in as another user
+//
+// @Summary      Impersonate user
+// @Description  Issue a short-lived token acting as another (non-admin, active) user for support. The token carries the admin identity in the "act" claim, cannot be refreshed and is bound to the admin's session. Password change, account deletion and API key creation are rejected while impersonating; every request is tagged with the admin in logs and the audit trail.
+// @Tags         🔐Authtication
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  impersonationResponse.ImpersonationDetail
+// @Failure      401  {object}  errorResponse.Problem
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/authen/impersonate/{id} [post]
+func (h *ImpersonationController) Impersonate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the impersonate controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Issue impersonation token
+	out, err := h.svc.Impersonate(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Impersonate failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
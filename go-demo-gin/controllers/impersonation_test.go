@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	impersonationResponse "go-demo-gin/responses/impersonation"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type impersonationEnv struct {
+	sessionEnv
+}
+
+// Router có login, impersonate, sửa/xoá user và /me/sessions; admin (ID 1), alice (ID 2, customer), bob (ID 3, admin)
+func setupImpersonationRouter(t *testing.T) impersonationEnv {
+	t.Helper()
+	t.Setenv("SECRET", "test-secret")
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, db.AutoMigrate(&models.Session{}))
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", 1).Update("password", string(hash)).Error)
+	require.NoError(t, db.Create(&models.User{Username: "alice", Password: string(hash), Role: models.RoleCustomer}).Error)
+	require.NoError(t, db.Create(&models.User{Username: "bob", Password: string(hash), Role: models.RoleAdmin}).Error)
+
+	ur := repo.NewGormUserRepo(db)
+	ar := repo.NewGormAuditRepo(db)
+	sessions := services.NewSessionService(repo.NewGormSessionRepo(db), cache.NewLRU(100))
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: []byte("test-secret"), AccessTTL: time.Hour}, ur, sessions)
+	RequireRoles := middlewares.Authentication(ur, nil, sessions)
+
+	r.POST("/api/v1/authen/login", NewAuthController(auth).Login)
+	ic := NewImpersonationController(services.NewImpersonationService(auth, ur, ar, time.Minute))
+	r.POST("/api/v1/authen/impersonate/:id", RequireRoles(models.RoleAdmin), ic.Impersonate)
+	uc := NewUserController(utils.NewValidator(db), services.NewUserService(db, ur, ar, repo.NewGormOutboxRepo(db)))
+	r.PUT("/api/v1/users/:id", RequireRoles(models.RoleAdmin, models.RoleCustomer), uc.UsersUpdate)
+	r.DELETE("/api/v1/users/:id", RequireRoles(models.RoleAdmin, models.RoleCustomer), uc.UsersDelete)
+	sc := NewSessionController(sessions)
+	r.GET("/api/v1/me/sessions", RequireRoles(models.RoleAdmin, models.RoleCustomer), sc.SessionsIndex)
+	r.DELETE("/api/v1/me/sessions/:id", RequireRoles(models.RoleAdmin, models.RoleCustomer), sc.SessionsRevoke)
+	return impersonationEnv{sessionEnv{r: r, db: db, auth: auth}}
+}
+
+func (e impersonationEnv) impersonate(t *testing.T, token, id string) impersonationResponse.ImpersonationDetail {
+	t.Helper()
+	w := e.do(http.MethodPost, "/api/v1/authen/impersonate/"+id, token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res impersonationResponse.ImpersonationDetail
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func (e impersonationEnv) sendJSON(method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+func TestImpersonation_ActsAsTargetWithRestrictions(t *testing.T) {
+	e := setupImpersonationRouter(t)
+	admin := e.login(t, "admin", "Firefox/130")
+
+	res := e.impersonate(t, admin, "2")
+	assert.Equal(t, uint(2), res.UserID)
+	assert.Equal(t, "alice", res.Username)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), res.ExpiresAt, 5*time.Second)
+	token := res.Token
+
+	// Thao tác thường => audit ghi actor là alice, kèm admin thực sự
+	w := e.sendJSON(http.MethodPut, "/api/v1/users/2", token, `{"full_name": "Alice", "role": "customer", "birthday": "2000-01-02"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var ev models.AuditEvent
+	require.NoError(t, e.db.Where("action = ?", models.AuditUserUpdate).First(&ev).Error)
+	require.NotNil(t, ev.ActorID)
+	assert.Equal(t, uint(2), *ev.ActorID)
+	require.NotNil(t, ev.ImpersonatorID)
+	assert.Equal(t, uint(1), *ev.ImpersonatorID)
+	assert.Equal(t, "admin", ev.ImpersonatorName)
+
+	// Bắt đầu impersonate cũng được ghi audit
+	var started models.AuditEvent
+	require.NoError(t, e.db.Where("action = ?", models.AuditUserImpersonate).First(&started).Error)
+	assert.Equal(t, uint(1), *started.ActorID)
+	assert.Equal(t, uint(2), started.TargetID)
+
+	// Đổi mật khẩu, xoá tài khoản => bị chặn
+	w = e.sendJSON(http.MethodPut, "/api/v1/users/2", token, `{"password": "N3w.pass-word", "role": "customer", "birthday": "2000-01-02"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, utils.IMPERSONATION_FORBIDDEN.ID, problemCode(t, w))
+	w = e.do(http.MethodDelete, "/api/v1/users/2", token)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, utils.IMPERSONATION_FORBIDDEN.ID, problemCode(t, w))
+	findUser(t, e.db, 2)
+
+	// Token impersonation không refresh được
+	_, err := e.auth.Refresh(context.Background(), token)
+	assert.Error(t, err)
+}
+
+func TestImpersonation_Rejected(t *testing.T) {
+	e := setupImpersonationRouter(t)
+	admin := e.login(t, "admin", "Firefox/130")
+
+	// Chính mình, admin khác => không được
+	for _, id := range []string{"1", "3"} {
+		w := e.do(http.MethodPost, "/api/v1/authen/impersonate/"+id, admin)
+		assert.Equal(t, http.StatusForbidden, w.Code, id)
+		assert.Equal(t, utils.CANNOT_IMPERSONATE.ID, problemCode(t, w))
+	}
+	w := e.do(http.MethodPost, "/api/v1/authen/impersonate/99", admin)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Token impersonation gắn với session của admin: admin đăng xuất => token bị từ chối
+	token := e.impersonate(t, admin, "2").Token
+	assert.Equal(t, http.StatusOK, e.do(http.MethodGet, "/api/v1/me/sessions", token).Code)
+	list := e.list(t, admin)
+	require.Len(t, list, 1)
+	w = e.do(http.MethodDelete, "/api/v1/me/sessions/"+strconv.FormatUint(uint64(list[0].ID), 10), admin)
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = e.do(http.MethodGet, "/api/v1/me/sessions", token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.SESSION_REVOKED.ID, problemCode(t, w))
+
+	// Admin bị hạ quyền => token impersonation cũ mất hiệu lực
+	other := e.login(t, "admin", "MobileApp/2.1")
+	token = e.impersonate(t, other, "2").Token
+	require.NoError(t, e.db.Model(&models.User{}).Where("id = ?", 1).Update("role", models.RoleStaff).Error)
+	w = e.do(http.MethodGet, "/api/v1/me/sessions", token)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
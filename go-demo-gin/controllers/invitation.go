@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	organizationRequest "go-demo-gin/requests/organization"
+	errorResponse "go-demo-gin/responses/error"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ organizationResponse.InvitationDetail
+	_ errorResponse.Problem
+)
+
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, in *organizationRequest.InvitationCreate, orgID string) (*organizationResponse.InvitationDetail, error)
+	GetInvitationList(ctx context.Context, orgID string, pag *pkg.Pagination) (*pkg.Pagination, error)
+	RevokeInvitation(ctx context.Context, id string) error
+	AcceptInvitation(ctx context.Context, in *organizationRequest.InvitationAccept) (*organizationResponse.OrganizationDetail, error)
+}
+
+type InvitationController struct {
+	v   *utils.Validator
+	svc InvitationService
+}
+
+func NewInvitationController(v *utils.Validator, svc InvitationService) *InvitationController {
+	return &InvitationController{v: v, svc: svc}
+}
+
+// InvitationsCreate invites a user by email
+//
+// @Summary      Invite to organization
+// @Description  Email an invitation to join the organization, or one of its teams when team_id is set. Organization owners/managers invite to anything; team owners/managers only to their own team. The link holds a one-time token for POST /api/v1/invitations/accept.
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                                   true  "Organization ID"
+// @Param        request  body      organizationRequest.InvitationCreate  true  "Invitation"
+// @Success      201      {object}  organizationResponse.InvitationDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/invitations [post]
+func (h *InvitationController) InvitationsCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create invitation controller", nil)
+
+	// Get id from url
+	orgID := c.Param("id")
+
+	// Get data off request body
+	var create organizationRequest.InvitationCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create invitation
+	detail, err := h.svc.CreateInvitation(ctx, &create, orgID)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create invitation failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// InvitationsIndex lists pending invitations
+//
+// @Summary      List invitations
+// @Description  Get pending invitations of an organization (owners and managers)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id			path      int     true   "Organization ID"
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]organizationResponse.InvitationDetail}
+// @Failure      403   {object}  errorResponse.Problem
+// @Failure      404   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/invitations [get]
+func (h *InvitationController) InvitationsIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of invitations controller", nil)
+
+	// Get id from url
+	orgID := c.Param("id")
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get invitation list
+	result, err := h.svc.GetInvitationList(ctx, orgID, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of invitations failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// InvitationsRevoke revokes an invitation
+//
+// @Summary      Revoke invitation
+// @Description  Revoke a pending invitation (whoever may send it); revoking twice is a no-op
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Invitation ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/invitations/{id} [delete]
+func (h *InvitationController) InvitationsRevoke(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke invitation controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Revoke invitation
+	if err := h.svc.RevokeInvitation(ctx, id); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Revoke invitation failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InvitationsAccept accepts an invitation
+//
+// @Summary      Accept invitation
+// @Description  Join the organization (and team) with the token from the invitation email. The current user's email must match the invited address and be verified; existing memberships keep their role.
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      organizationRequest.InvitationAccept  true  "Invitation token"
+// @Success      200      {object}  organizationResponse.OrganizationDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/invitations/accept [post]
+func (h *InvitationController) InvitationsAccept(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the accept invitation controller", nil)
+
+	// Get data off request body
+	var accept organizationRequest.InvitationAccept
+	if err := c.ShouldBindJSON(&accept); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, accept); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Accept invitation
+	detail, err := h.svc.AcceptInvitation(ctx, &accept)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Accept invitation failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
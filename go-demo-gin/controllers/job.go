@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"context"
+	errorResponse "go-demo-gin/responses/error"
+	jobResponse "go-demo-gin/responses/job"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var _ errorResponse.Problem
+
+type JobService interface {
+	GetJobById(ctx context.Context, id string) (*jobResponse.JobDetail, error)
+}
+
+type JobController struct {
+	svc JobService
+}
+
+func NewJobController(svc JobService) *JobController {
+	return &JobController{svc: svc}
+}
+
+// JobsShow get background job status
+//
+// @Summary      Get job status
+// @Description  Get status, attempts and result of a background job. Admins see jobs of their own tenant, non-admin users can only see jobs they created.
+// @Tags         ⚙️Jobs
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Job ID"
+// @Success      200  {object}  jobResponse.JobDetail
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/jobs/{id} [get]
+func (h *JobController) JobsShow(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get job by id controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get job detail
+	detail, err := h.svc.GetJobById(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get job by id failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ errorResponse.Problem
+)
+
+type OIDCService interface {
+	AuthURL(ctx context.Context, provider string) (string, error)
+	Callback(ctx context.Context, provider, code, state string) (*string, error)
+}
+
+type OIDCController struct {
+	svc OIDCService
+}
+
+func NewOIDCController(svc OIDCService) *OIDCController {
+	return &OIDCController{svc: svc}
+}
+
+// OIDCLogin redirects to the identity provider
+//
+// @Summary      Login with identity provider
+// @Description  Start OIDC authorization code + PKCE login: redirect the browser to the provider's login page
+// @Tags         🔐Authtication
+// @Param        provider  path  string  true  "Provider name"
+// @Success      302  "Redirect to the identity provider"
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/authen/oidc/{provider}/login [get]
+func (h *OIDCController) OIDCLogin(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the oidc login controller", nil)
+
+	url, err := h.svc.AuthURL(ctx, c.Param("provider"))
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "OIDC login failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// OIDCCallback completes login with the identity provider
+//
+// @Summary      Identity provider callback
+// @Description  Exchange the authorization code, verify the ID token and return a JWT. Unknown identities are linked by verified email or provisioned with the provider's default role.
+// @Tags         🔐Authtication
+// @Produce      json
+// @Param        provider  path   string  true   "Provider name"
+// @Param        code      query  string  false  "Authorization code"
+// @Param        state     query  string  true   "State from the login redirect"
+// @Success      200  {object}  TokenResponse
+// @Failure      401  {object}  errorResponse.Problem
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/authen/oidc/{provider}/callback [get]
+func (h *OIDCController) OIDCCallback(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the oidc callback controller", nil)
+
+	// IdP báo lỗi (user từ chối, cấu hình sai...) thay vì trả code
+	if idpErr := c.Query("error"); idpErr != "" {
+		utils.HandleServiceError(c, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, nil))
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "OIDC provider returned error: "+idpErr, logrus.Fields{"description": c.Query("error_description")})
+		return
+	}
+
+	token, err := h.svc.Callback(ctx, c.Param("provider"), c.Query("code"), c.Query("state"))
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "OIDC callback failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{
+		Token: token,
+	})
+}
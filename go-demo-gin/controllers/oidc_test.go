@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	mockClientID     = "demo-client"
+	mockClientSecret = "demo-secret"
+	mockRedirect     = "http://app.test/api/v1/authen/oidc/acme/callback"
+)
+
+// IdP giả lập trên httptest: discovery/JWKS từ oidctest, tự làm /auth (trả code) và /token (kiểm tra PKCE, ký ID token)
+type mockIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	claims map[string]any // claims cho lần đăng nhập tiếp theo
+	nonce  string         // != "" => ghi đè nonce (giả lập token bị tráo)
+	grants map[string]mockGrant
+}
+
+type mockGrant struct {
+	nonce, challenge string
+	claims           map[string]any
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &mockIdP{key: key, grants: map[string]mockGrant{}}
+	discovery := &oidctest.Server{PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: "k1", Algorithm: oidc.RS256}}}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", discovery)
+	mux.HandleFunc("/auth", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	discovery.SetIssuer(idp.URL)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (m *mockIdP) login(claims map[string]any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claims = claims
+}
+
+func (m *mockIdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != mockClientID || q.Get("code_challenge_method") != "S256" || q.Get("nonce") == "" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+	m.mu.Lock()
+	code := strconv.Itoa(len(m.grants) + 1)
+	m.grants[code] = mockGrant{nonce: q.Get("nonce"), challenge: q.Get("code_challenge"), claims: m.claims}
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockIdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+	m.mu.Lock()
+	g, ok := m.grants[r.PostFormValue("code")]
+	delete(m.grants, r.PostFormValue("code"))
+	nonce := m.nonce
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if !ok || id != mockClientID || secret != mockClientSecret || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+	if nonce == "" {
+		nonce = g.nonce
+	}
+
+	claims := map[string]any{"iss": m.URL, "aud": mockClientID, "exp": time.Now().Add(time.Hour).Unix(), "iat": time.Now().Unix(), "nonce": nonce}
+	for k, v := range g.claims {
+		claims[k] = v
+	}
+	raw, _ := json.Marshal(claims)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": "at",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     oidctest.SignIDToken(m.key, "k1", oidc.RS256, string(raw)),
+	})
+}
+
+type oidcEnv struct {
+	r    *gin.Engine
+	db   *gorm.DB
+	idp  *mockIdP
+	auth *services.AuthService
+}
+
+func setupOIDCRouter(t *testing.T, mutate func(*services.OIDCProviderConfig)) oidcEnv {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, db.AutoMigrate(&models.UserIdentity{}))
+	idp := newMockIdP(t)
+
+	provider := services.OIDCProviderConfig{
+		Name: "acme", Issuer: idp.URL, ClientID: mockClientID, ClientSecret: mockClientSecret,
+		RedirectURL: mockRedirect, DefaultRole: models.RoleStaff,
+	}
+	if mutate != nil {
+		mutate(&provider)
+	}
+	ur := repo.NewGormUserRepo(db)
+	users := services.NewUserService(db, ur, repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db))
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: []byte("test-secret"), AccessTTL: time.Hour}, ur, nil)
+	svc := services.NewOIDCService(db, services.OIDCConfig{Providers: []services.OIDCProviderConfig{provider}},
+		users, auth, repo.NewGormIdentityRepo(db), cache.NewLRU(100))
+
+	h := NewOIDCController(svc)
+	r.GET("/api/v1/authen/oidc/:provider/login", h.OIDCLogin)
+	r.GET("/api/v1/authen/oidc/:provider/callback", h.OIDCCallback)
+	return oidcEnv{r: r, db: db, idp: idp, auth: auth}
+}
+
+func (e oidcEnv) get(t *testing.T, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+// Chạy toàn bộ luồng: login => IdP => trả về URL callback (path + query) mà trình duyệt sẽ gọi
+func (e oidcEnv) authorize(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	e.idp.login(claims)
+	w := e.get(t, "/api/v1/authen/oidc/acme/login")
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+	noFollow := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := noFollow.Get(w.Header().Get("Location"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return callback.RequestURI()
+}
+
+// Callback thành công => username của JWT được cấp
+func (e oidcEnv) loginAs(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	w := e.get(t, e.authorize(t, claims))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res TokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	username, _, _, err := e.auth.VerifyToken(context.Background(), *res.Token)
+	require.NoError(t, err)
+	return username
+}
+
+func TestOIDC_ProvisionsUserJustInTime(t *testing.T) {
+	e := setupOIDCRouter(t, nil)
+	claims := map[string]any{"sub": "idp-123", "email": "Jane.Doe@corp.example", "email_verified": true, "name": "Jane Doe"}
+
+	assert.Equal(t, "jane.doe", e.loginAs(t, claims))
+	var u models.User
+	require.NoError(t, e.db.First(&u, "username = ?", "jane.doe").Error)
+	assert.Equal(t, models.RoleStaff, u.Role)
+	assert.Equal(t, models.UserActive, u.Status)
+	assert.Equal(t, "Jane Doe", u.Name.String)
+	assert.NotNil(t, u.EmailVerifiedAt)
+
+	// Lần sau dùng lại liên kết, không tạo user mới
+	assert.Equal(t, "jane.doe", e.loginAs(t, claims))
+	var users, identities int64
+	e.db.Model(&models.User{}).Count(&users)
+	e.db.Model(&models.UserIdentity{}).Where("user_id = ? AND provider = ? AND subject = ?", u.ID, "acme", "idp-123").Count(&identities)
+	assert.Equal(t, int64(2), users) // admin + jane.doe
+	assert.Equal(t, int64(1), identities)
+
+	// Trùng username với user khác => thêm số
+	assert.Equal(t, "jane.doe2", e.loginAs(t, map[string]any{"sub": "idp-456", "preferred_username": "Jane.Doe"}))
+}
+
+func TestOIDC_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	e := setupOIDCRouter(t, func(p *services.OIDCProviderConfig) { p.LinkByEmail = true })
+	email := "bob@corp.example"
+	require.NoError(t, e.db.Create(&models.User{Username: "bob", Password: "x", Role: models.RoleCustomer, Status: models.UserActive, Email: &email}).Error)
+
+	// Email chưa được IdP xác thực => không tự gộp
+	w := e.get(t, e.authorize(t, map[string]any{"sub": "b-1", "email": email, "email_verified": false}))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	assert.Equal(t, "bob", e.loginAs(t, map[string]any{"sub": "b-1", "email": email, "email_verified": true}))
+	var bob models.User
+	require.NoError(t, e.db.First(&bob, "username = ?", "bob").Error)
+	assert.Equal(t, models.RoleCustomer, bob.Role) // giữ nguyên role của user có sẵn
+}
+
+func TestOIDC_RejectsInvalidCallbacks(t *testing.T) {
+	e := setupOIDCRouter(t, func(p *services.OIDCProviderConfig) { p.DefaultRole = "" })
+
+	w := e.get(t, "/api/v1/authen/oidc/other/login")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, utils.OIDC_PROVIDER_NOT_FOUND.ID, problemCode(t, w))
+
+	w = e.get(t, "/api/v1/authen/oidc/acme/callback?code=1&state=forged")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.OIDC_INVALID_STATE.ID, problemCode(t, w))
+
+	w = e.get(t, "/api/v1/authen/oidc/acme/callback?error=access_denied")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.OIDC_LOGIN_FAILED.ID, problemCode(t, w))
+
+	// Không bật JIT => danh tính chưa liên kết bị từ chối; state đã dùng thì không dùng lại được
+	callback := e.authorize(t, map[string]any{"sub": "nobody"})
+	w = e.get(t, callback)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.OIDC_NOT_PROVISIONED.ID, problemCode(t, w))
+	w = e.get(t, callback)
+	assert.Equal(t, utils.OIDC_INVALID_STATE.ID, problemCode(t, w))
+
+	// ID token mang nonce khác với nonce của phiên đăng nhập
+	e.idp.mu.Lock()
+	e.idp.nonce = "tampered"
+	e.idp.mu.Unlock()
+	w = e.get(t, e.authorize(t, map[string]any{"sub": "nobody"}))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.OIDC_LOGIN_FAILED.ID, problemCode(t, w))
+}
@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	organizationRequest "go-demo-gin/requests/organization"
+	errorResponse "go-demo-gin/responses/error"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ organizationResponse.OrganizationDetail
+	_ errorResponse.Problem
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, in *organizationRequest.OrganizationCreate) (*organizationResponse.OrganizationDetail, error)
+	GetOrganizationList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error)
+	GetOrganizationById(ctx context.Context, id string) (*organizationResponse.OrganizationDetail, error)
+	UpdateOrganization(ctx context.Context, in *organizationRequest.OrganizationUpdate, id string) (*organizationResponse.OrganizationDetail, error)
+	DeleteOrganization(ctx context.Context, id string) error
+	GetMemberList(ctx context.Context, id string, pag *pkg.Pagination) (*pkg.Pagination, error)
+	UpdateMemberRole(ctx context.Context, in *organizationRequest.MemberUpdate, id, userID string) (*organizationResponse.MemberDetail, error)
+	RemoveMember(ctx context.Context, id, userID string) error
+}
+
+type OrganizationController struct {
+	v   *utils.Validator
+	svc OrganizationService
+}
+
+func NewOrganizationController(v *utils.Validator, svc OrganizationService) *OrganizationController {
+	return &OrganizationController{v: v, svc: svc}
+}
+
+// OrganizationsCreate creates an organization
+//
+// @Summary      Create organization
+// @Description  Create an organization; the current user becomes its owner
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      organizationRequest.OrganizationCreate  true  "Organization to create"
+// @Success      201      {object}  organizationResponse.OrganizationDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      401      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/orgs [post]
+func (h *OrganizationController) OrganizationsCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create organization controller", nil)
+
+	// Get data off request body
+	var create organizationRequest.OrganizationCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create organization
+	detail, err := h.svc.CreateOrganization(ctx, &create)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create organization failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// OrganizationsIndex lists organizations
+//
+// @Summary      List organizations
+// @Description  Get organizations the current user belongs to (admins see every organization of the tenant)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]organizationResponse.OrganizationDetail}
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/orgs [get]
+func (h *OrganizationController) OrganizationsIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of organizations controller", nil)
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get organization list
+	result, err := h.svc.GetOrganizationList(ctx, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of organizations failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// OrganizationsShow get organization detail
+//
+// @Summary      Get organization detail
+// @Description  Get organization by ID (members only)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  organizationResponse.OrganizationDetail
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id} [get]
+func (h *OrganizationController) OrganizationsShow(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get organization by id controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get organization detail
+	detail, err := h.svc.GetOrganizationById(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get organization by id failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// OrganizationsUpdate updates an organization
+//
+// @Summary      Update organization
+// @Description  Rename an organization (owners and managers)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                                     true  "Organization ID"
+// @Param        request  body      organizationRequest.OrganizationUpdate  true  "Updated organization data"
+// @Success      200      {object}  organizationResponse.OrganizationDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id} [put]
+func (h *OrganizationController) OrganizationsUpdate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update organization controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get data off request body
+	var update organizationRequest.OrganizationUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, update); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Update organization
+	detail, err := h.svc.UpdateOrganization(ctx, &update, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Update organization failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// OrganizationsDelete deletes an organization
+//
+// @Summary      Delete organization
+// @Description  Delete an organization with its teams, memberships and invitations (owners only)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Organization ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id} [delete]
+func (h *OrganizationController) OrganizationsDelete(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete organization controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Delete organization
+	if err := h.svc.DeleteOrganization(ctx, id); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Delete organization failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// OrganizationMembersIndex lists organization members
+//
+// @Summary      List organization members
+// @Description  Get members of an organization with their membership roles (members only)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id			path      int     true   "Organization ID"
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]organizationResponse.MemberDetail}
+// @Failure      404   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/members [get]
+func (h *OrganizationController) OrganizationMembersIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of organization members controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get member list
+	result, err := h.svc.GetMemberList(ctx, id, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of organization members failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// OrganizationMembersUpdate changes a member's role
+//
+// @Summary      Change organization member role
+// @Description  Change the membership role of an organization member (owners and managers; only owners grant or revoke the owner role, the last owner cannot be demoted)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                               true  "Organization ID"
+// @Param        userId   path      int                               true  "User ID"
+// @Param        request  body      organizationRequest.MemberUpdate  true  "New role"
+// @Success      200      {object}  organizationResponse.MemberDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/members/{userId} [put]
+func (h *OrganizationController) OrganizationMembersUpdate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update organization member controller", nil)
+
+	// Get ids from url
+	id, userID := c.Param("id"), c.Param("userId")
+
+	// Get data off request body
+	var update organizationRequest.MemberUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, update); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Update member role
+	detail, err := h.svc.UpdateMemberRole(ctx, &update, id, userID)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Update organization member failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// OrganizationMembersDelete removes a member
+//
+// @Summary      Remove organization member
+// @Description  Remove a member from the organization and all of its teams (owners and managers), or leave the organization yourself
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int  true  "Organization ID"
+// @Param        userId   path      int  true  "User ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      409  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/members/{userId} [delete]
+func (h *OrganizationController) OrganizationMembersDelete(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the remove organization member controller", nil)
+
+	// Get ids from url
+	id, userID := c.Param("id"), c.Param("userId")
+
+	// Remove member
+	if err := h.svc.RemoveMember(ctx, id, userID); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Remove organization member failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
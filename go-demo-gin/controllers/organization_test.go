@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/mailer"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type orgEnv struct {
+	r     *gin.Engine
+	db    *gorm.DB
+	mail  *mailer.Memory
+	users map[string]*models.User
+}
+
+// Router organization/team/invitation; người gọi chọn bằng header X-Test-User (username).
+// Users: olivia, tina, sam, zed (customer, email <tên>@example.com đã xác thực)
+func setupOrgRouter(t *testing.T) orgEnv {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, db.AutoMigrate(&models.Organization{}, &models.Team{}, &models.OrganizationMember{}, &models.TeamMember{}, &models.Invitation{}))
+	users := map[string]*models.User{}
+	verifiedAt := time.Now()
+	for _, name := range []string{"olivia", "tina", "sam", "zed"} {
+		email := name + "@example.com"
+		u := &models.User{Username: name, Password: "x", Role: models.RoleCustomer, Email: &email, EmailVerifiedAt: &verifiedAt}
+		require.NoError(t, db.Create(u).Error)
+		users[name] = u
+	}
+	asUser := func(c *gin.Context) {
+		u, ok := users[c.GetHeader("X-Test-User")]
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Request = c.Request.WithContext(utils.WithInformation(c.Request.Context(), u))
+	}
+
+	orgRepo := repo.NewGormOrganizationRepo(db)
+	teamRepo := repo.NewGormTeamRepo(db)
+	authz := services.NewMembershipAuthorizer(orgRepo, teamRepo)
+	mail := mailer.NewMemory()
+	v := utils.NewValidator(db)
+	oc := NewOrganizationController(v, services.NewOrganizationService(db, orgRepo, authz))
+	tc := NewTeamController(v, services.NewTeamService(db, teamRepo, orgRepo, authz))
+	ic := NewInvitationController(v, services.NewInvitationService(db, services.DefaultInvitationConfig(), repo.NewGormInvitationRepo(db), orgRepo, teamRepo, repo.NewGormUserRepo(db), authz, mail))
+
+	g := r.Group("/api/v1", asUser)
+	g.POST("/orgs", oc.OrganizationsCreate)
+	g.GET("/orgs/:id", oc.OrganizationsShow)
+	g.GET("/orgs/:id/members", oc.OrganizationMembersIndex)
+	g.PUT("/orgs/:id/members/:userId", oc.OrganizationMembersUpdate)
+	g.DELETE("/orgs/:id/members/:userId", oc.OrganizationMembersDelete)
+	g.POST("/orgs/:id/teams", tc.TeamsCreate)
+	g.PUT("/teams/:id/members/:userId", tc.TeamMembersUpdate)
+	g.DELETE("/teams/:id/members/:userId", tc.TeamMembersDelete)
+	g.POST("/orgs/:id/invitations", ic.InvitationsCreate)
+	g.POST("/invitations/accept", ic.InvitationsAccept)
+	return orgEnv{r: r, db: db, mail: mail, users: users}
+}
+
+func (e orgEnv) send(user, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", user)
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+func (e orgEnv) id(t *testing.T, w *httptest.ResponseRecorder) uint {
+	t.Helper()
+	require.Contains(t, []int{http.StatusOK, http.StatusCreated}, w.Code, w.Body.String())
+	var res struct {
+		ID uint `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res.ID
+}
+
+func (e orgEnv) addMember(t *testing.T, orgID uint, name string, role models.MembershipRole) {
+	t.Helper()
+	require.NoError(t, e.db.Create(&models.OrganizationMember{OrganizationID: orgID, UserID: e.users[name].ID, Role: role}).Error)
+}
+
+// Token lấy từ link trong email mời
+func invitationToken(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+	i := strings.Index(msg.Body, "token=")
+	require.GreaterOrEqual(t, i, 0, msg.Body)
+	raw := msg.Body[i+len("token="):]
+	if j := strings.IndexAny(raw, " \n"); j >= 0 {
+		raw = raw[:j]
+	}
+	token, err := url.QueryUnescape(raw)
+	require.NoError(t, err)
+	return token
+}
+
+func TestOrganizations_InvitationFlow(t *testing.T) {
+	e := setupOrgRouter(t)
+
+	verifiedAt := time.Now()
+	w := e.send("olivia", http.MethodPost, "/api/v1/orgs", `{"name": "Acme"}`)
+	orgID := e.id(t, w)
+	assert.Contains(t, w.Body.String(), `"role":"owner"`)
+	orgPath := fmt.Sprintf("/api/v1/orgs/%d", orgID)
+
+	// Người ngoài không thấy organization
+	w = e.send("zed", http.MethodGet, orgPath, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = e.send("olivia", http.MethodPost, orgPath+"/invitations", `{"email": "Zed@example.com", "role": "manager"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	msg, ok := e.mail.Last("zed@example.com")
+	require.True(t, ok)
+	token := invitationToken(t, msg)
+
+	// Sai người nhận, token sai => bị từ chối
+	w = e.send("sam", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+token+`"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.INVITATION_EMAIL_MISMATCH.ID, problemCode(t, w))
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "nope"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Token mang tenant của lời mời: sửa tenant => không tìm thấy; cùng email ở tenant khác => bị từ chối
+	require.True(t, strings.HasPrefix(token, "1."), token)
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "2.`+strings.TrimPrefix(token, "1.")+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, utils.INVALID_INVITATION.ID, problemCode(t, w))
+	email := "zed@example.com"
+	other := &models.User{Username: "zed", Password: "x", Role: models.RoleCustomer, Email: &email, TenantID: 2}
+	require.NoError(t, e.db.Create(other).Error)
+	e.users["acme-zed"] = other
+	w = e.send("acme-zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+token+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, utils.INVALID_INVITATION.ID, problemCode(t, w))
+
+	// Email chưa xác thực => chưa chấp nhận được, lời mời vẫn còn hiệu lực
+	e.users["zed"].EmailVerifiedAt = nil
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+token+`"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.EMAIL_NOT_VERIFIED.ID, problemCode(t, w))
+	e.users["zed"].EmailVerifiedAt = &verifiedAt
+
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+token+`"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"manager"`)
+	// Token chỉ dùng 1 lần
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+token+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, utils.INVALID_INVITATION.ID, problemCode(t, w))
+
+	// Đã là thành viên => không mời lại; manager không mời được owner
+	w = e.send("olivia", http.MethodPost, orgPath+"/invitations", `{"email": "zed@example.com", "role": "member"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, utils.ALREADY_MEMBER.ID, problemCode(t, w))
+	w = e.send("zed", http.MethodPost, orgPath+"/invitations", `{"email": "sam@example.com", "role": "owner"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = e.send("zed", http.MethodGet, orgPath+"/members", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"username":"zed"`)
+
+	// Owner cuối cùng không rời/bị hạ quyền được; manager không đụng được owner
+	oliviaPath := fmt.Sprintf("%s/members/%d", orgPath, e.users["olivia"].ID)
+	w = e.send("olivia", http.MethodDelete, oliviaPath, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, utils.LAST_OWNER.ID, problemCode(t, w))
+	w = e.send("zed", http.MethodPut, oliviaPath, `{"role": "member"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = e.send("olivia", http.MethodPut, oliviaPath, `{"role": "boss"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTeams_ManagerEditsOnlyOwnTeam(t *testing.T) {
+	e := setupOrgRouter(t)
+	orgID := e.id(t, e.send("olivia", http.MethodPost, "/api/v1/orgs", `{"name": "Acme"}`))
+	for _, name := range []string{"tina", "sam", "zed"} {
+		e.addMember(t, orgID, name, models.MemberMember)
+	}
+	teamA := e.id(t, e.send("olivia", http.MethodPost, fmt.Sprintf("/api/v1/orgs/%d/teams", orgID), `{"name": "Backend"}`))
+	teamB := e.id(t, e.send("olivia", http.MethodPost, fmt.Sprintf("/api/v1/orgs/%d/teams", orgID), `{"name": "Frontend"}`))
+	member := func(team uint, name string) string {
+		return fmt.Sprintf("/api/v1/teams/%d/members/%d", team, e.users[name].ID)
+	}
+
+	// Thành viên thường không tạo team, không quản lý team
+	w := e.send("tina", http.MethodPost, fmt.Sprintf("/api/v1/orgs/%d/teams", orgID), `{"name": "Ops"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = e.send("tina", http.MethodPut, member(teamA, "sam"), `{"role": "member"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Owner của organization cử tina làm manager team A
+	w = e.send("olivia", http.MethodPut, member(teamA, "tina"), `{"role": "manager"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// tina quản lý thành viên team A ...
+	w = e.send("tina", http.MethodPut, member(teamA, "sam"), `{"role": "member"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = e.send("tina", http.MethodPut, member(teamA, "zed"), `{"role": "manager"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = e.send("tina", http.MethodDelete, member(teamA, "zed"), "")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	// ... nhưng không cấp quyền owner, không sửa team khác, không sửa thành viên organization
+	w = e.send("tina", http.MethodPut, member(teamA, "sam"), `{"role": "owner"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.MEMBERSHIP_FORBIDDEN.ID, problemCode(t, w))
+	w = e.send("tina", http.MethodPut, member(teamB, "sam"), `{"role": "member"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = e.send("tina", http.MethodDelete, fmt.Sprintf("/api/v1/orgs/%d/members/%d", orgID, e.users["sam"].ID), "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Người ngoài organization không vào team được
+	require.NoError(t, e.db.Where("user_id = ?", e.users["zed"].ID).Delete(&models.OrganizationMember{}).Error)
+	w = e.send("tina", http.MethodPut, member(teamA, "zed"), `{"role": "member"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, utils.NOT_ORG_MEMBER.ID, problemCode(t, w))
+
+	// Team manager mời vào team của mình; người nhận tham gia organization với quyền member
+	w = e.send("tina", http.MethodPost, fmt.Sprintf("/api/v1/orgs/%d/invitations", orgID), fmt.Sprintf(`{"email": "zed@example.com", "role": "member", "team_id": %d}`, teamB))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = e.send("tina", http.MethodPost, fmt.Sprintf("/api/v1/orgs/%d/invitations", orgID), fmt.Sprintf(`{"email": "zed@example.com", "role": "member", "team_id": %d}`, teamA))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	msg, _ := e.mail.Last("zed@example.com")
+	w = e.send("zed", http.MethodPost, "/api/v1/invitations/accept", `{"token": "`+invitationToken(t, msg)+`"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"role":"member"`)
+	var tm models.TeamMember
+	require.NoError(t, e.db.Where("team_id = ? AND user_id = ?", teamA, e.users["zed"].ID).First(&tm).Error)
+	assert.Equal(t, models.MemberMember, tm.Role)
+
+	// Xoá khỏi organization => xoá khỏi mọi team
+	w = e.send("olivia", http.MethodDelete, fmt.Sprintf("/api/v1/orgs/%d/members/%d", orgID, e.users["sam"].ID), "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	var n int64
+	e.db.Model(&models.TeamMember{}).Where("user_id = ?", e.users["sam"].ID).Count(&n)
+	assert.Zero(t, n)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bytes"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/password"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Router tạo/sửa user và login, chính sách có danh sách mật khẩu đã lộ
+func setupPasswordRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("password123\n"), 0o600))
+	policy := password.DefaultPolicy()
+	policy.History = 2
+	require.NoError(t, policy.LoadBreached(path))
+	v := utils.NewValidator(db)
+	v.SetPasswordPolicy(policy)
+
+	ur := repo.NewGormUserRepo(db)
+	h := NewUserController(v, services.NewUserService(db, ur, repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db)))
+	r.POST("/api/v1/users", h.UsersCreate)
+	r.PUT("/api/v1/users/:id", h.UsersUpdate)
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: []byte("test-secret"), AccessTTL: time.Hour}, ur, nil)
+	r.POST("/api/v1/authen/login", NewAuthController(auth).Login)
+	return r, db
+}
+
+func sendJSON(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPasswordPolicy_CreateAndUpdate(t *testing.T) {
+	r, db := setupPasswordRouter(t)
+	create := func(pass string) *httptest.ResponseRecorder {
+		return sendJSON(r, http.MethodPost, "/api/v1/users", `{"username": "alice", "password": "`+pass+`", "role": "staff", "birthday": "2000-01-02"}`)
+	}
+
+	for pass, code := range map[string]string{
+		"short":                 utils.INVALID_PASSWORD.ID,
+		"PassWord123":           utils.PASSWORD_BREACHED.ID,
+		"Alice.2024":            utils.PASSWORD_CONTAINS_USERNAME.ID,
+		strings.Repeat("x", 65): utils.INVALID_PASSWORD.ID,
+	} {
+		w := create(pass)
+		assert.Equal(t, http.StatusBadRequest, w.Code, pass)
+		assert.Contains(t, w.Body.String(), `"code":"`+code+`"`, pass)
+	}
+
+	// Chữ hoa, ký tự đặc biệt được chấp nhận; hash mới dùng Argon2id
+	w := create("Tr0ub4dor&3")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	alice := findUser(t, db, 2)
+	assert.True(t, strings.HasPrefix(alice.Password, "$argon2id$"), alice.Password)
+
+	update := func(pass string) *httptest.ResponseRecorder {
+		return sendJSON(r, http.MethodPut, "/api/v1/users/2", `{"password": "`+pass+`", "role": "staff", "birthday": "2000-01-02"}`)
+	}
+	w = update("Tr0ub4dor&3")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), utils.PASSWORD_REUSED.ID)
+	w = update("Alice-new-1")
+	assert.Contains(t, w.Body.String(), utils.PASSWORD_CONTAINS_USERNAME.ID) // username lấy từ user đang sửa
+
+	require.Equal(t, http.StatusOK, update("correct horse").Code)
+	require.Equal(t, http.StatusOK, update("battery staple").Code)
+	// History = 2: mật khẩu hiện tại + 2 mật khẩu gần nhất bị chặn
+	assert.Equal(t, http.StatusBadRequest, update("correct horse").Code)
+	var count int64
+	db.Model(&models.PasswordHistory{}).Where("user_id = ?", 2).Count(&count)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestPasswordPolicy_RehashOnLogin(t *testing.T) {
+	r, db := setupPasswordRouter(t)
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+	require.NoError(t, db.Create(&models.User{Username: "bob", Password: string(legacy), Role: models.RoleCustomer}).Error)
+	login := func(pass string) int {
+		return sendJSON(r, http.MethodPost, "/api/v1/authen/login", `{"username": "bob", "password": "`+pass+`"}`).Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("wrong.pass"))
+	assert.Equal(t, string(legacy), findUser(t, db, 2).Password)
+
+	require.Equal(t, http.StatusOK, login("secret.123"))
+	bob := findUser(t, db, 2)
+	assert.True(t, strings.HasPrefix(bob.Password, "$argon2id$"), bob.Password)
+
+	// Hash mới vẫn đăng nhập được và không bị hash lại nữa
+	require.Equal(t, http.StatusOK, login("secret.123"))
+	assert.Equal(t, bob.Password, findUser(t, db, 2).Password)
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	authenRequest "go-demo-gin/requests/authen"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ userResponse.UserDetail
+	_ errorResponse.Problem
+)
+
+type RegistrationService interface {
+	Register(ctx context.Context, in *authenRequest.RegisterForm) (*userResponse.UserDetail, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+}
+
+type RegistrationController struct {
+	v   *utils.Validator
+	svc RegistrationService
+}
+
+func NewRegistrationController(v *utils.Validator, svc RegistrationService) *RegistrationController {
+	return &RegistrationController{v: v, svc: svc}
+}
+
+// Register signs up a new customer account
+//
+// @Summary      Register
+// @Description  Create a `customer` account in `pending` status and email a verification link. The account can log in after the email is verified.
+// @Tags         🔐Authtication
+// @Accept       json
+// @Produce      json
+// @Param        request  body      authenRequest.RegisterForm  true  "Registration form"
+// @Success      201      {object}  userResponse.UserDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/authen/register [post]
+func (h *RegistrationController) Register(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the register controller", nil)
+
+	// Get data off request body
+	var form authenRequest.RegisterForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, form); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Register
+	detail, err := h.svc.Register(ctx, &form)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Register failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// VerifyEmail verifies an email address from the emailed link
+//
+// @Summary      Verify email
+// @Description  Verify the email address with the signed token from the verification email and activate the pending account
+// @Tags         🔐Authtication
+// @Produce      json
+// @Param        token  query     string  true  "Signed verification token"
+// @Success      204    "No Content"
+// @Failure      400    {object}  errorResponse.Problem
+// @Failure      500    {object}  errorResponse.Problem
+// @Router       /api/v1/authen/verify [get]
+func (h *RegistrationController) VerifyEmail(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the verify email controller", nil)
+
+	// Verify
+	if err := h.svc.VerifyEmail(ctx, c.Query("token")); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Verify email failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification sends the verification email again
+//
+// @Summary      Resend verification email
+// @Description  Send a new verification link to a pending account. Always accepted for unknown emails; too frequent requests get 429.
+// @Tags         🔐Authtication
+// @Accept       json
+// @Produce      json
+// @Param        request  body      authenRequest.ResendVerificationForm  true  "Email"
+// @Success      202      "Accepted"
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      429      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/authen/verify/resend [post]
+func (h *RegistrationController) ResendVerification(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the resend verification controller", nil)
+
+	// Get data off request body
+	var form authenRequest.ResendVerificationForm
+	if err := c.ShouldBindJSON(&form); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, form); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Resend
+	if err := h.svc.ResendVerification(ctx, form.Mail); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Resend verification failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"go-demo-gin/cache"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/mailer"
+	"go-demo-gin/repo"
+	authenRequest "go-demo-gin/requests/authen"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type registrationEnv struct {
+	r    *gin.Engine
+	db   *gorm.DB
+	mail *mailer.Memory
+	auth *services.AuthService
+}
+
+// Các route đăng ký đi sau tenant resolver (X-Tenant); tenant mặc định và acme (id 2)
+func setupRegistrationRouter(t *testing.T, cfg services.RegistrationConfig) registrationEnv {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, repo.RegisterTenantScope(db))
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}))
+	require.NoError(t, db.Create(&models.Tenant{ID: models.DefaultTenantID, Slug: "default", Name: "Default"}).Error)
+	require.NoError(t, db.Create(&models.Tenant{Slug: "acme", Name: "Acme"}).Error)
+	r.Use(middlewares.ResolveTenant(services.NewTenantService(repo.NewGormTenantRepo(db), cache.NewLRU(10)), ""))
+	ur := repo.NewGormUserRepo(db)
+	users := services.NewUserService(db, ur, repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db))
+	mail := mailer.NewMemory()
+	cfg.Key = []byte("test-secret")
+	h := NewRegistrationController(utils.NewValidator(db), services.NewRegistrationService(db, cfg, users, mail))
+	r.POST("/api/v1/authen/register", h.Register)
+	r.GET("/api/v1/authen/verify", h.VerifyEmail)
+	r.POST("/api/v1/authen/verify/resend", h.ResendVerification)
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: cfg.Key, AccessTTL: time.Hour}, ur, nil)
+	return registrationEnv{r: r, db: db, mail: mail, auth: auth}
+}
+
+// header: các cặp key, value
+func (e registrationEnv) do(t *testing.T, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+var verifyLink = regexp.MustCompile(`http\S+token=\S+`)
+
+// Lấy đường dẫn xác thực (path + query) trong email gần nhất gửi tới addr
+func (e registrationEnv) lastLink(t *testing.T, addr string) string {
+	t.Helper()
+	msg, ok := e.mail.Last(addr)
+	require.True(t, ok, "no email sent to "+addr)
+	u, err := url.Parse(verifyLink.FindString(msg.Body))
+	require.NoError(t, err)
+	return u.RequestURI()
+}
+
+func TestRegister_VerifyThenLogin(t *testing.T) {
+	e := setupRegistrationRouter(t, services.DefaultRegistrationConfig())
+	ctx := context.Background()
+
+	w := e.do(t, http.MethodPost, "/api/v1/authen/register?lang=vi",
+		`{"username": "newbie", "password": "secret.123", "email": "Newbie@Example.com"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	assert.Contains(t, w.Body.String(), `"role":"customer"`)
+
+	msg, ok := e.mail.Last("Newbie@Example.com")
+	require.True(t, ok)
+	assert.Equal(t, "Xác thực địa chỉ email", msg.Subject) // theo ngôn ngữ của request
+
+	// Chưa xác thực => chưa đăng nhập được
+	_, err := e.auth.Authenticate(ctx, &authenRequest.LoginForm{Username: "newbie", Password: "secret.123"})
+	assert.ErrorContains(t, err, utils.ACCOUNT_PENDING.ID)
+
+	link := e.lastLink(t, "Newbie@Example.com")
+	w = e.do(t, http.MethodGet, link, "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = e.do(t, http.MethodGet, link, "") // bấm lại link => không lỗi
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	var u models.User
+	require.NoError(t, e.db.First(&u, "username = ?", "newbie").Error)
+	assert.Equal(t, models.UserActive, u.Status)
+	assert.NotNil(t, u.EmailVerifiedAt)
+	_, err = e.auth.Authenticate(ctx, &authenRequest.LoginForm{Username: "newbie", Password: "secret.123"})
+	assert.NoError(t, err)
+}
+
+// Link xác thực mở từ email không có X-Tenant: tenant lấy từ token đã ký, sửa tenant => sai chữ ký
+func TestVerifyEmail_TenantFromToken(t *testing.T) {
+	e := setupRegistrationRouter(t, services.DefaultRegistrationConfig())
+	w := e.do(t, http.MethodPost, "/api/v1/authen/register",
+		`{"username": "acmer", "password": "secret.123", "email": "acmer@example.com"}`, middlewares.TenantHeader, "acme")
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	link := e.lastLink(t, "acmer@example.com")
+
+	forged := strings.Replace(link, "token=2.", "token=1.", 1)
+	require.NotEqual(t, link, forged)
+	w = e.do(t, http.MethodGet, forged, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), utils.INVALID_VERIFICATION_TOKEN.ID)
+
+	w = e.do(t, http.MethodGet, link, "")
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	var u models.User
+	require.NoError(t, e.db.First(&u, "username = ?", "acmer").Error)
+	assert.Equal(t, uint(2), u.TenantID)
+	assert.Equal(t, models.UserActive, u.Status)
+}
+
+func TestRegister_Validation(t *testing.T) {
+	e := setupRegistrationRouter(t, services.DefaultRegistrationConfig())
+	require.Equal(t, http.StatusCreated, e.do(t, http.MethodPost, "/api/v1/authen/register",
+		`{"username": "first", "password": "secret.123", "email": "taken@example.com"}`).Code)
+
+	w := e.do(t, http.MethodPost, "/api/v1/authen/register",
+		`{"username": "first", "password": "secret.123", "email": "TAKEN@example.com"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), utils.DUPLICATE_USERNAME.ID)
+	assert.Contains(t, w.Body.String(), utils.DUPLICATE_EMAIL.ID)
+
+	w = e.do(t, http.MethodPost, "/api/v1/authen/register", `{"username": "second", "password": "secret.123", "email": "nope"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), utils.INVALID_EMAIL.ID)
+}
+
+func TestVerifyEmail_InvalidAndExpired(t *testing.T) {
+	cfg := services.DefaultRegistrationConfig()
+	cfg.TokenTTL = -time.Minute // link hết hạn ngay khi gửi
+	e := setupRegistrationRouter(t, cfg)
+	require.Equal(t, http.StatusCreated, e.do(t, http.MethodPost, "/api/v1/authen/register",
+		`{"username": "late", "password": "secret.123", "email": "late@example.com"}`).Code)
+	link := e.lastLink(t, "late@example.com")
+
+	w := e.do(t, http.MethodGet, link, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), utils.VERIFICATION_EXPIRED.ID)
+
+	// Sửa hạn trong token => sai chữ ký
+	for _, token := range []string{"", "garbage", "2.9999999999.AAAA", "1.2.9999999999.AAAA"} {
+		w = e.do(t, http.MethodGet, "/api/v1/authen/verify?token="+url.QueryEscape(token), "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, token)
+		assert.Contains(t, w.Body.String(), utils.INVALID_VERIFICATION_TOKEN.ID, token)
+	}
+}
+
+func TestResendVerification_Throttled(t *testing.T) {
+	e := setupRegistrationRouter(t, services.DefaultRegistrationConfig())
+	require.Equal(t, http.StatusCreated, e.do(t, http.MethodPost, "/api/v1/authen/register",
+		`{"username": "again", "password": "secret.123", "email": "again@example.com"}`).Code)
+
+	// Vừa gửi lúc đăng ký => bị chặn
+	w := e.do(t, http.MethodPost, "/api/v1/authen/verify/resend", `{"email": "again@example.com"}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), utils.VERIFICATION_THROTTLED.ID)
+
+	// Hết khoảng chờ => gửi lại; email lạ vẫn 202 (không lộ email đã đăng ký)
+	require.NoError(t, e.db.Model(&models.User{}).Where("username = ?", "again").
+		Update("verification_sent_at", time.Now().Add(-2*time.Minute)).Error)
+	w = e.do(t, http.MethodPost, "/api/v1/authen/verify/resend", `{"email": "again@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.Len(t, e.mail.Sent(), 2)
+
+	w = e.do(t, http.MethodPost, "/api/v1/authen/verify/resend", `{"email": "nobody@example.com"}`)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Len(t, e.mail.Sent(), 2)
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+	errorResponse "go-demo-gin/responses/error"
+	sessionResponse "go-demo-gin/responses/session"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ sessionResponse.SessionDetail
+	_ errorResponse.Problem
+)
+
+type SessionService interface {
+	ListSessions(ctx context.Context) ([]sessionResponse.SessionDetail, error)
+	RevokeSession(ctx context.Context, id string) error
+}
+
+type SessionController struct {
+	svc SessionService
+}
+
+func NewSessionController(svc SessionService) *SessionController {
+	return &SessionController{svc: svc}
+}
+
+// SessionsIndex lists active sessions of the current user
+//
+// @Summary      List my sessions
+// @Description  Get active logins (device/user-agent, IP, created and last seen) of the current user. The session of this request is marked as current.
+// @Tags         🔐Authtication
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   sessionResponse.SessionDetail
+// @Failure      401  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/me/sessions [get]
+func (h *SessionController) SessionsIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of sessions controller", nil)
+
+	// Get session list
+	list, err := h.svc.ListSessions(ctx)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of sessions failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// SessionsRevoke revokes a session of the current user
+//
+// @Summary      Revoke my session
+// @Description  Log out a device: tokens of the revoked session are rejected immediately. Revoking the current session logs out this client.
+// @Tags         🔐Authtication
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Session ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/me/sessions/{id} [delete]
+func (h *SessionController) SessionsRevoke(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke session controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Revoke session
+	if err := h.svc.RevokeSession(ctx, id); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Revoke session failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	sessionResponse "go-demo-gin/responses/session"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type sessionEnv struct {
+	r    *gin.Engine
+	db   *gorm.DB
+	auth *services.AuthService
+}
+
+// Router có login và /me/sessions; IP/User-Agent lấy từ request giống AccessLogger
+func setupSessionRouter(t *testing.T) sessionEnv {
+	t.Helper()
+	t.Setenv("SECRET", "test-secret")
+	r, db := setupUserFileRouter(t)
+	require.NoError(t, db.AutoMigrate(&models.Session{}))
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+	for _, name := range []string{"alice", "bob"} {
+		require.NoError(t, db.Create(&models.User{Username: name, Password: string(hash), Role: models.RoleCustomer}).Error)
+	}
+
+	ur := repo.NewGormUserRepo(db)
+	sessions := services.NewSessionService(repo.NewGormSessionRepo(db), cache.NewLRU(100))
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: []byte("test-secret"), AccessTTL: time.Hour}, ur, sessions)
+	requestInfo := func(c *gin.Context) {
+		c.Request = c.Request.WithContext(utils.WithRequestInfo(c.Request.Context(), &utils.RequestInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}))
+	}
+	r.POST("/api/v1/authen/login", requestInfo, NewAuthController(auth).Login)
+	h := NewSessionController(sessions)
+	me := r.Group("/api/v1/me", middlewares.Authentication(ur, nil, sessions)(models.RoleCustomer))
+	me.GET("/sessions", h.SessionsIndex)
+	me.DELETE("/sessions/:id", h.SessionsRevoke)
+	return sessionEnv{r: r, db: db, auth: auth}
+}
+
+func (e sessionEnv) login(t *testing.T, username, userAgent string) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/authen/login", bytes.NewBufferString(`{"username": "`+username+`", "password": "secret.123"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res TokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return *res.Token
+}
+
+func (e sessionEnv) do(method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+func (e sessionEnv) list(t *testing.T, token string) []sessionResponse.SessionDetail {
+	t.Helper()
+	w := e.do(http.MethodGet, "/api/v1/me/sessions", token)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list []sessionResponse.SessionDetail
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	return list
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	e := setupSessionRouter(t)
+	laptop := e.login(t, "alice", "Firefox/130")
+	phone := e.login(t, "alice", "MobileApp/2.1")
+	bob := e.login(t, "bob", "curl/8")
+
+	list := e.list(t, laptop)
+	require.Len(t, list, 2)
+	byAgent := map[string]sessionResponse.SessionDetail{}
+	for _, s := range list {
+		byAgent[s.UserAgent] = s
+		assert.NotEmpty(t, s.IP)
+		assert.False(t, s.LastSeenAt.IsZero())
+	}
+	assert.True(t, byAgent["Firefox/130"].Current)
+	assert.False(t, byAgent["MobileApp/2.1"].Current)
+
+	// Không thu hồi được session của người khác
+	phoneID := strconv.FormatUint(uint64(byAgent["MobileApp/2.1"].ID), 10)
+	w := e.do(http.MethodDelete, "/api/v1/me/sessions/"+phoneID, bob)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Thu hồi điện thoại từ laptop => token của điện thoại bị từ chối ngay (dù đã được cache là hợp lệ)
+	assert.Len(t, e.list(t, phone), 2)
+	w = e.do(http.MethodDelete, "/api/v1/me/sessions/"+phoneID, laptop)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	w = e.do(http.MethodGet, "/api/v1/me/sessions", phone)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.SESSION_REVOKED.ID, problemCode(t, w))
+	_, err := e.auth.Refresh(context.Background(), phone)
+	assert.Error(t, err)
+
+	list = e.list(t, laptop)
+	require.Len(t, list, 1)
+	assert.Equal(t, "Firefox/130", list[0].UserAgent)
+}
+
+func TestSessions_RefreshKeepsSession(t *testing.T) {
+	e := setupSessionRouter(t)
+	token := e.login(t, "alice", "Firefox/130")
+	before := e.list(t, token)
+	require.Len(t, before, 1)
+
+	refreshed, err := e.auth.Refresh(context.Background(), token)
+	require.NoError(t, err)
+	after := e.list(t, *refreshed)
+	require.Len(t, after, 1)
+	assert.Equal(t, before[0].ID, after[0].ID)
+	assert.True(t, after[0].Current)
+
+	// Session hết hạn (chưa được kiểm tra lần nào => không có trong cache) => token bị từ chối
+	other := e.login(t, "alice", "MobileApp/2.1")
+	require.NoError(t, e.db.Model(&models.Session{}).Where("user_agent = ?", "MobileApp/2.1").Update("expires_at", time.Now().Add(-time.Second)).Error)
+	w := e.do(http.MethodGet, "/api/v1/me/sessions", other)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, utils.SESSION_REVOKED.ID, problemCode(t, w))
+}
@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	organizationRequest "go-demo-gin/requests/organization"
+	errorResponse "go-demo-gin/responses/error"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ organizationResponse.TeamDetail
+	_ errorResponse.Problem
+)
+
+type TeamService interface {
+	CreateTeam(ctx context.Context, in *organizationRequest.TeamCreate, orgID string) (*organizationResponse.TeamDetail, error)
+	GetTeamList(ctx context.Context, orgID string, pag *pkg.Pagination) (*pkg.Pagination, error)
+	UpdateTeam(ctx context.Context, in *organizationRequest.TeamUpdate, id string) (*organizationResponse.TeamDetail, error)
+	DeleteTeam(ctx context.Context, id string) error
+	GetTeamMemberList(ctx context.Context, id string, pag *pkg.Pagination) (*pkg.Pagination, error)
+	SaveTeamMember(ctx context.Context, in *organizationRequest.MemberUpdate, id, userID string) (*organizationResponse.MemberDetail, error)
+	RemoveTeamMember(ctx context.Context, id, userID string) error
+}
+
+type TeamController struct {
+	v   *utils.Validator
+	svc TeamService
+}
+
+func NewTeamController(v *utils.Validator, svc TeamService) *TeamController {
+	return &TeamController{v: v, svc: svc}
+}
+
+// TeamsCreate creates a team
+//
+// @Summary      Create team
+// @Description  Create a team in an organization (organization owners and managers)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "Organization ID"
+// @Param        request  body      organizationRequest.TeamCreate  true  "Team to create"
+// @Success      201      {object}  organizationResponse.TeamDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/teams [post]
+func (h *TeamController) TeamsCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create team controller", nil)
+
+	// Get id from url
+	orgID := c.Param("id")
+
+	// Get data off request body
+	var create organizationRequest.TeamCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create team
+	detail, err := h.svc.CreateTeam(ctx, &create, orgID)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create team failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// TeamsIndex lists teams of an organization
+//
+// @Summary      List teams
+// @Description  Get teams of an organization (members only)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id			path      int     true   "Organization ID"
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]organizationResponse.TeamDetail}
+// @Failure      404   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/orgs/{id}/teams [get]
+func (h *TeamController) TeamsIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of teams controller", nil)
+
+	// Get id from url
+	orgID := c.Param("id")
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get team list
+	result, err := h.svc.GetTeamList(ctx, orgID, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of teams failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// TeamsUpdate updates a team
+//
+// @Summary      Update team
+// @Description  Rename a team (organization owners and managers)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                             true  "Team ID"
+// @Param        request  body      organizationRequest.TeamUpdate  true  "Updated team data"
+// @Success      200      {object}  organizationResponse.TeamDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/teams/{id} [put]
+func (h *TeamController) TeamsUpdate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update team controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get data off request body
+	var update organizationRequest.TeamUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, update); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Update team
+	detail, err := h.svc.UpdateTeam(ctx, &update, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Update team failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// TeamsDelete deletes a team
+//
+// @Summary      Delete team
+// @Description  Delete a team with its memberships and invitations (organization owners and managers)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Team ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/teams/{id} [delete]
+func (h *TeamController) TeamsDelete(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete team controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Delete team
+	if err := h.svc.DeleteTeam(ctx, id); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Delete team failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// TeamMembersIndex lists team members
+//
+// @Summary      List team members
+// @Description  Get members of a team with their membership roles (organization members only)
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id			path      int     true   "Team ID"
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]organizationResponse.MemberDetail}
+// @Failure      404   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/teams/{id}/members [get]
+func (h *TeamController) TeamMembersIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of team members controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get member list
+	result, err := h.svc.GetTeamMemberList(ctx, id, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of team members failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// TeamMembersUpdate adds a member to a team or changes their role
+//
+// @Summary      Add or update team member
+// @Description  Add an organization member to the team or change their team role. Team owners/managers manage only their own team; only owners grant or revoke the owner role.
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                               true  "Team ID"
+// @Param        userId   path      int                               true  "User ID"
+// @Param        request  body      organizationRequest.MemberUpdate  true  "Team role"
+// @Success      200      {object}  organizationResponse.MemberDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/teams/{id}/members/{userId} [put]
+func (h *TeamController) TeamMembersUpdate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the save team member controller", nil)
+
+	// Get ids from url
+	id, userID := c.Param("id"), c.Param("userId")
+
+	// Get data off request body
+	var update organizationRequest.MemberUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, update); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Save team member
+	detail, err := h.svc.SaveTeamMember(ctx, &update, id, userID)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Save team member failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// TeamMembersDelete removes a team member
+//
+// @Summary      Remove team member
+// @Description  Remove a member from the team (team or organization owners/managers), or leave the team yourself
+// @Tags         👥Organizations
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int  true  "Team ID"
+// @Param        userId   path      int  true  "User ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  errorResponse.Problem
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/teams/{id}/members/{userId} [delete]
+func (h *TeamController) TeamMembersDelete(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the remove team member controller", nil)
+
+	// Get ids from url
+	id, userID := c.Param("id"), c.Param("userId")
+
+	// Remove team member
+	if err := h.svc.RemoveTeamMember(ctx, id, userID); err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Remove team member failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
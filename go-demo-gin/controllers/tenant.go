@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	tenantRequest "go-demo-gin/requests/tenant"
+	errorResponse "go-demo-gin/responses/error"
+	tenantResponse "go-demo-gin/responses/tenant"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ tenantResponse.TenantDetail
+	_ errorResponse.Problem
+)
+
+type TenantService interface {
+	CreateTenant(ctx context.Context, in *tenantRequest.TenantCreate) (*tenantResponse.TenantDetail, error)
+	GetTenantList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error)
+}
+
+type TenantController struct {
+	v   *utils.Validator
+	svc TenantService
+}
+
+func NewTenantController(v *utils.Validator, svc TenantService) *TenantController {
+	return &TenantController{v: v, svc: svc}
+}
+
+// TenantsCreate creates a tenant
+//
+// @Summary      Create tenant
+// @Description  Create a client company (super admin only). Its users are isolated from other tenants; clients select it with the "X-Tenant: <slug>" header or the "<slug>." subdomain.
+// @Tags         🏢Tenants
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tenantRequest.TenantCreate  true  "Tenant to create"
+// @Success      201      {object}  tenantResponse.TenantDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/tenants [post]
+func (h *TenantController) TenantsCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create tenant controller", nil)
+
+	// Get data off request body
+	var create tenantRequest.TenantCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create tenant
+	detail, err := h.svc.CreateTenant(ctx, &create)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create tenant failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// TenantsIndex lists tenants
+//
+// @Summary      List tenants
+// @Description  Get list of tenants (super admin only)
+// @Tags         🏢Tenants
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]tenantResponse.TenantDetail}
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      403   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/tenants [get]
+func (h *TenantController) TenantsIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of tenants controller", nil)
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get tenant list
+	result, err := h.svc.GetTenantList(ctx, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of tenants failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
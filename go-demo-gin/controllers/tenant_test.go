@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/initializers"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type tenantEnv struct {
+	r  *gin.Engine
+	db *gorm.DB
+}
+
+// Router có tenant resolver (subdomain của example.com), login, xem user và quản lý tenant;
+// tenant mặc định có root (super admin) và admin, tenant acme có admin trùng username
+func setupTenantRouter(t *testing.T) (tenantEnv, *models.User, *models.User) {
+	t.Helper()
+	t.Setenv("SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, initializers.LoadI18n())
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // mỗi connection :memory: là một DB riêng
+	require.NoError(t, repo.RegisterTenantScope(db))
+	require.NoError(t, db.AutoMigrate(&models.Tenant{}, &models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.PasswordHistory{}, &models.Session{}))
+	require.NoError(t, db.Create(&models.Tenant{ID: models.DefaultTenantID, Slug: "default", Name: "Default"}).Error)
+	require.NoError(t, db.Create(&models.Tenant{Slug: "acme", Name: "Acme"}).Error)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+	require.NoError(t, db.Create(&models.User{Username: "root", Password: string(hash), Role: models.RoleSuperAdmin}).Error)
+	admin := &models.User{Username: "admin", Password: string(hash), Role: models.RoleAdmin}
+	require.NoError(t, db.Create(admin).Error)
+	acmeAdmin := &models.User{Username: "admin", Password: string(hash), Role: models.RoleAdmin}
+	require.NoError(t, db.WithContext(utils.WithTenant(context.Background(), utils.TenantScope{ID: 2})).Create(acmeAdmin).Error)
+
+	userCache := cache.NewLRU(100)
+	ur := repo.NewCachedUserRepo(repo.NewGormUserRepo(db), userCache, time.Minute)
+	sessions := services.NewSessionService(repo.NewGormSessionRepo(db), userCache)
+	auth := services.NewAuthService(db, services.AuthConfig{JWTKey: []byte("test-secret"), AccessTTL: time.Hour}, ur, sessions)
+	RequireRoles := middlewares.Authentication(ur, nil, sessions)
+	v := utils.NewValidator(db)
+
+	r := gin.New()
+	r.Use(middlewares.ErrorHandler(), middlewares.I18n())
+	r.Use(middlewares.ResolveTenant(services.NewTenantService(repo.NewGormTenantRepo(db), userCache), "example.com"))
+	r.POST("/api/v1/authen/login", NewAuthController(auth).Login)
+	uc := NewUserController(v, services.NewUserService(db, ur, repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db)))
+	r.GET("/api/v1/users/:id", RequireRoles(models.RoleAdmin), uc.UsersShow)
+	tc := NewTenantController(v, services.NewTenantService(repo.NewGormTenantRepo(db), userCache))
+	r.POST("/api/v1/tenants", RequireRoles(models.RoleSuperAdmin), tc.TenantsCreate)
+	r.GET("/api/v1/tenants", RequireRoles(models.RoleSuperAdmin), tc.TenantsIndex)
+	return tenantEnv{r: r, db: db}, admin, acmeAdmin
+}
+
+// headers: "Host" được gán vào req.Host, còn lại là header thường
+func (e tenantEnv) send(method, path, token, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for k, v := range headers {
+		if k == "Host" {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	e.r.ServeHTTP(w, req)
+	return w
+}
+
+func (e tenantEnv) login(t *testing.T, username string, headers map[string]string) string {
+	t.Helper()
+	w := e.send(http.MethodPost, "/api/v1/authen/login", "", `{"username": "`+username+`", "password": "secret.123"}`, headers)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res TokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return *res.Token
+}
+
+func userPath(u *models.User) string {
+	return "/api/v1/users/" + strconv.FormatUint(uint64(u.ID), 10)
+}
+
+func TestTenants_UsersIsolatedPerTenant(t *testing.T) {
+	e, admin, acmeAdmin := setupTenantRouter(t)
+	acme := map[string]string{middlewares.TenantHeader: "acme"}
+
+	// Cùng username ở 2 tenant: tenant chọn bằng header hoặc subdomain
+	defaultToken := e.login(t, "admin", nil)
+	acmeToken := e.login(t, "admin", acme)
+	assert.NotEmpty(t, e.login(t, "admin", map[string]string{"Host": "acme.example.com"}))
+
+	w := e.send(http.MethodGet, userPath(acmeAdmin), acmeToken, "", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// User của tenant khác => như không tồn tại (kể cả khi đã có trong cache)
+	w = e.send(http.MethodGet, userPath(admin), acmeToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	w = e.send(http.MethodGet, userPath(acmeAdmin), defaultToken, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	// User thường không được chuyển sang tenant khác
+	w = e.send(http.MethodGet, userPath(acmeAdmin), defaultToken, "", acme)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, utils.TENANT_MISMATCH.ID, problemCode(t, w))
+
+	// Tenant không tồn tại
+	w = e.send(http.MethodPost, "/api/v1/authen/login", "", `{"username": "admin", "password": "secret.123"}`, map[string]string{middlewares.TenantHeader: "nope"})
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	assert.Equal(t, utils.UNKNOWN_TENANT.ID, problemCode(t, w))
+}
+
+// Token cũ không có claim "tid" luôn thuộc tenant mặc định, không bao giờ theo header/subdomain của request
+func TestTenants_LegacyTokenWithoutTenantClaim(t *testing.T) {
+	e, admin, acmeAdmin := setupTenantRouter(t)
+	acme := map[string]string{middlewares.TenantHeader: "acme"}
+	legacy := func(id uint) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "admin", "id": id}).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+		return token
+	}
+
+	w := e.send(http.MethodGet, userPath(admin), legacy(admin.ID), "", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Phát lại dưới header của acme => vẫn là admin của tenant mặc định, bị chặn chuyển tenant
+	w = e.send(http.MethodGet, userPath(acmeAdmin), legacy(admin.ID), "", acme)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Equal(t, utils.TENANT_MISMATCH.ID, problemCode(t, w))
+
+	// Claim "id" của admin acme nhưng username được tìm trong tenant mặc định => id không khớp
+	w = e.send(http.MethodGet, userPath(acmeAdmin), legacy(acmeAdmin.ID), "", acme)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+	assert.Equal(t, utils.INVALID_CLAIM.ID, problemCode(t, w))
+}
+
+func TestTenants_SuperAdmin(t *testing.T) {
+	e, _, acmeAdmin := setupTenantRouter(t)
+	root := e.login(t, "root", nil)
+
+	// Super admin làm việc trong tenant khác bằng X-Tenant
+	w := e.send(http.MethodGet, userPath(acmeAdmin), root, "", map[string]string{middlewares.TenantHeader: "acme"})
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = e.send(http.MethodGet, userPath(acmeAdmin), root, "", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	// Tạo tenant: slug hợp lệ, không trùng; admin thường không được phép
+	w = e.send(http.MethodPost, "/api/v1/tenants", root, `{"slug": "globex", "name": "Globex"}`, nil)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = e.send(http.MethodPost, "/api/v1/tenants", root, `{"slug": "globex", "name": "Globex 2"}`, nil)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	assert.Equal(t, utils.DUPLICATE_TENANT_SLUG.ID, problemCode(t, w))
+	w = e.send(http.MethodPost, "/api/v1/tenants", root, `{"slug": "-Bad_slug", "name": "Bad"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	w = e.send(http.MethodPost, "/api/v1/tenants", e.login(t, "admin", nil), `{"slug": "initech", "name": "Initech"}`, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	w = e.send(http.MethodGet, "/api/v1/tenants", root, "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var page struct {
+		TotalRows int64 `json:"total_rows"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, int64(3), page.TotalRows)
+
+	// Tenant mới dùng được ngay
+	w = e.send(http.MethodPost, "/api/v1/authen/login", "", `{"username": "admin", "password": "secret.123"}`, map[string]string{middlewares.TenantHeader: "globex"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code, w.Body.String())
+}
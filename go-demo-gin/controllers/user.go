@@ -16,15 +16,19 @@ import (
 var (
 	_ userResponse.UserList
 	_ userResponse.UserDetail
-	_ errorResponse.HTTPError
+	_ errorResponse.Problem
 )
 
 type UserService interface {
-	CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, int, string)
-	GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, int, string)
-	GetUserById(ctx context.Context, id string) (*userResponse.UserDetail, int, string)
-	UpdateUser(ctx context.Context, in *userRequest.UserUpdate, id string) (*userResponse.UserDetail, int, string)
-	DeleteUser(ctx context.Context, id string) (int, string)
+	CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, error)
+	GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, error)
+	GetUserById(ctx context.Context, id string) (*userResponse.UserDetail, error)
+	UpdateUser(ctx context.Context, in *userRequest.UserUpdate, id string) (*userResponse.UserDetail, error)
+	DeleteUser(ctx context.Context, id string) error
+	ImportUsers(ctx context.Context, rows []*userRequest.UserCreate, atomic bool) (map[int]error, error)
+	ExportUsers(ctx context.Context, search, sort string, fn func([]userResponse.UserDetail) error) error
+	BatchUsers(ctx context.Context, ops []userRequest.UserBatchOperation, atomic bool) (map[int]error, error)
+	ChangeUserStatus(ctx context.Context, id string, in *userRequest.UserStatusChange) (*userResponse.UserDetail, error)
 }
 
 type UserController struct {
@@ -46,8 +50,8 @@ func NewUserController(v *utils.Validator, svc UserService) *UserController {
 // @Produce      json
 // @Param        request  body      userRequest.UserCreate  true  "User to create"
 // @Success      201      {object}  userResponse.UserDetail
-// @Failure      400      {object}  errorResponse.HTTPError
-// @Failure      500      {string}  httputil.HTTPError
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
 // @Router       /api/v1/users [post]
 func (h *UserController) UsersCreate(c *gin.Context) {
 	// Logging
@@ -72,15 +76,15 @@ func (h *UserController) UsersCreate(c *gin.Context) {
 	}
 
 	// Create user
-	// detail, status, err := h.svc.CreateUser(ctx, &create)
-	// if detail == nil || err != "" {
-	// 	utils.HandleServiceError(c, status, err)
-	// 	// Logging
-	// 	utils.LogCtx(ctx, logrus.ErrorLevel, "Create user failed: "+err, nil)
-	// 	return
-	// }
-
-	c.JSON(http.StatusBadRequest, nil)
+	detail, err := h.svc.CreateUser(ctx, &create)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create user failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
 }
 
 // UsersIndex lists all existing users
@@ -95,9 +99,14 @@ func (h *UserController) UsersCreate(c *gin.Context) {
 // @Param        limit		query     string  false  "Number of results per page"				default(10)
 // @Param        page		query     string  false  "Current page in the paginated results"	default(1)
 // @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Param        If-None-Match		header    string  false  "ETag from a previous response"
+// @Param        If-Modified-Since	header    string  false  "Last-Modified from a previous response"
+// @Param        Cache-Control		header    string  false  "Request directives: no-cache, no-store, max-age, only-if-cached"
 // @Success      200   {array}   pkg.Pagination{result=[]userResponse.UserList}
-// @Failure      400   {object}  errorResponse.HTTPError
-// @Failure      500   {string}  httputil.HTTPError
+// @Success      304   "Not Modified"
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      504   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
 // @Router       /api/v1/users [get]
 func (h *UserController) UsersIndex(c *gin.Context) {
 	// Logging
@@ -117,15 +126,15 @@ func (h *UserController) UsersIndex(c *gin.Context) {
 	}
 
 	// Get user list
-	result, status, err := h.svc.GetUserList(ctx, &pag, search)
-	if result == nil || err != "" {
-		utils.HandleServiceError(c, status, err)
+	result, err := h.svc.GetUserList(ctx, &pag, search)
+	if err != nil {
+		utils.HandleServiceError(c, err)
 		// Logging
-		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of users failed: "+err, nil)
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of users failed: "+err.Error(), nil)
 		return
 	}
 
-	c.JSON(status, result)
+	c.JSON(http.StatusOK, result)
 }
 
 // UsersShow get user detail
@@ -138,8 +147,8 @@ func (h *UserController) UsersIndex(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      int  true  "User ID"
 // @Success      200  {object}  userResponse.UserDetail
-// @Failure      404  {string}  httputil.HTTPError
-// @Failure      500  {string}  httputil.HTTPError
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
 // @Router       /api/v1/users/{id} [get]
 func (h *UserController) UsersShow(c *gin.Context) {
 	// Logging
@@ -150,15 +159,15 @@ func (h *UserController) UsersShow(c *gin.Context) {
 	id := c.Param("id")
 
 	// Gte user detail
-	detail, status, err := h.svc.GetUserById(ctx, id)
-	if detail == nil || err != "" {
-		utils.HandleServiceError(c, status, err)
+	detail, err := h.svc.GetUserById(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
 		// Logging
-		utils.LogCtx(ctx, logrus.ErrorLevel, "Get user by id failed: "+err, nil)
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get user by id failed: "+err.Error(), nil)
 		return
 	}
 
-	c.JSON(status, detail)
+	c.JSON(http.StatusOK, detail)
 }
 
 // UsersUpdate updates an existing user
@@ -172,9 +181,9 @@ func (h *UserController) UsersShow(c *gin.Context) {
 // @Param        id       path      int                         true  "user ID"
 // @Param        request  body      userRequest.UserUpdate  true  "Updated user data"
 // @Success      200      {object}  userResponse.UserDetail
-// @Failure      400      {object}  errorResponse.HTTPError
-// @Failure      404      {string}  httputil.HTTPError
-// @Failure      500      {string}  httputil.HTTPError
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
 // @Router       /api/v1/users/{id} [put]
 func (h *UserController) UsersUpdate(c *gin.Context) {
 	// Logging
@@ -203,15 +212,15 @@ func (h *UserController) UsersUpdate(c *gin.Context) {
 	}
 
 	// Update user
-	detail, status, err := h.svc.UpdateUser(ctx, &update, id)
-	if detail == nil || err != "" {
-		utils.HandleServiceError(c, status, err)
+	detail, err := h.svc.UpdateUser(ctx, &update, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
 		// Logging
-		utils.LogCtx(ctx, logrus.ErrorLevel, "Update user failed: "+err, nil)
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Update user failed: "+err.Error(), nil)
 		return
 	}
 
-	c.JSON(status, detail)
+	c.JSON(http.StatusOK, detail)
 }
 
 // UsersDelete deletes an user
@@ -224,8 +233,8 @@ func (h *UserController) UsersUpdate(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      int  true  "User ID"
 // @Success      204  "No Content"
-// @Failure      404  {string}  httputil.HTTPError
-// @Failure      500  {string}  httputil.HTTPError
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
 // @Router       /api/v1/users/{id} [delete]
 func (h *UserController) UsersDelete(c *gin.Context) {
 	// Logging
@@ -236,13 +245,13 @@ func (h *UserController) UsersDelete(c *gin.Context) {
 	id := c.Param("id")
 
 	// Delete user
-	status, err := h.svc.DeleteUser(ctx, id)
-	if err != "" {
-		utils.HandleServiceError(c, status, err)
+	err := h.svc.DeleteUser(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
 		// Logging
-		utils.LogCtx(ctx, logrus.ErrorLevel, "Delete user failed: "+err, nil)
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Delete user failed: "+err.Error(), nil)
 		return
 	}
 
-	c.Status(status)
+	c.Status(http.StatusNoContent)
 }
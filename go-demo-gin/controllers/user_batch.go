@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"go-demo-gin/apperror"
+	userRequest "go-demo-gin/requests/user"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+var _ errorResponse.Problem
+
+// UsersBatch applies several operations to users in one request
+//
+// @Summary      Batch user operations
+// @Description  Apply up to 100 operations (`delete`, `restore`, `set_role`, `activate`, `disable`, `lock`, `unlock`) in one request. `disable` requires a reason. Each item gets its own HTTP-like status.
+// @Description  `mode=atomic` (default) applies all operations in one transaction: if any fails, nothing is applied and the other items get 424. `mode=partial` applies the operations that succeed.
+// @Description  Returns 200 when every operation succeeded, 207 otherwise. You cannot delete, disable or change the role of your own account.
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      userRequest.UserBatch  true  "Operations"
+// @Success      200      {object}  userResponse.BatchResult
+// @Success      207      {object}  userResponse.BatchResult
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/batch [post]
+func (h *UserController) UsersBatch(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the batch users controller", nil)
+
+	// Get data off request body
+	var in userRequest.UserBatch
+	if err := c.ShouldBindJSON(&in); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+	if fields := h.v.ValidateStructCtx(ctx, in); fields != nil {
+		utils.HandleValidationError(c, fields)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+	if in.Mode == "" {
+		in.Mode = userRequest.BatchAtomic
+	}
+	atomic := in.Mode == userRequest.BatchAtomic
+	localizer := utils.LocalizerFrom(ctx)
+	result := &userResponse.BatchResult{Mode: in.Mode, Results: make([]userResponse.BatchItemResult, len(in.Operations))}
+
+	// 1) Validate từng thao tác
+	var valid []userRequest.UserBatchOperation
+	var validIdx []int // vị trí trong result.Results của từng thao tác hợp lệ
+	for i, op := range in.Operations {
+		result.Results[i] = userResponse.BatchItemResult{Index: i, Op: op.Op, ID: op.ID, Status: http.StatusOK}
+		if fields := h.v.ValidateStructCtx(ctx, op); fields != nil {
+			setBatchError(localizer, &result.Results[i], apperror.Validation(utils.VALIDATION_FAILED, fields))
+			continue
+		}
+		valid = append(valid, op)
+		validIdx = append(validIdx, i)
+	}
+
+	// 2) Áp dụng (atomic: có thao tác không hợp lệ => không áp dụng gì)
+	var opErrs map[int]error
+	if len(valid) > 0 && !(atomic && len(valid) < len(in.Operations)) {
+		var err error
+		opErrs, err = h.svc.BatchUsers(ctx, valid, atomic)
+		if err != nil {
+			utils.HandleServiceError(c, err)
+			// Logging
+			utils.LogCtx(ctx, logrus.ErrorLevel, "Batch users failed: "+err.Error(), nil)
+			return
+		}
+	}
+	for i, idx := range validIdx {
+		if err, ok := opErrs[i]; ok {
+			appErr, isApp := apperror.As(err)
+			if !isApp {
+				appErr = apperror.Internal(nil, err)
+			}
+			setBatchError(localizer, &result.Results[idx], appErr)
+		}
+	}
+
+	// 3) Atomic có lỗi => các thao tác còn lại không được áp dụng
+	rolledBack := atomic && (len(valid) < len(in.Operations) || len(opErrs) > 0)
+	for i := range result.Results {
+		r := &result.Results[i]
+		switch {
+		case r.Status != http.StatusOK:
+			result.Failed++
+		case rolledBack:
+			r.Status = http.StatusFailedDependency
+			r.Code = utils.BATCH_ROLLED_BACK.ID
+			r.Detail = utils.LoadI18nMessage(localizer, utils.BATCH_ROLLED_BACK, nil)
+			result.Failed++
+		default:
+			result.Succeeded++
+		}
+	}
+
+	status := http.StatusOK
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, result)
+}
+
+// Ghi lỗi nghiệp vụ vào kết quả của 1 thao tác (status + mã lỗi + lỗi theo field đã dịch)
+func setBatchError(localizer *i18n.Localizer, r *userResponse.BatchItemResult, e *apperror.Error) {
+	code := utils.ErrorCode(e)
+	r.Status = utils.HTTPStatus(e)
+	r.Code = code.ID
+	r.Detail = utils.LoadI18nMessage(localizer, code, nil)
+	if len(e.Fields) > 0 {
+		r.Errors = importFieldErrors(localizer, e.Fields)
+	}
+}
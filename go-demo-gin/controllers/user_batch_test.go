@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Router có route batch và đổi trạng thái, request được thực hiện bởi admin (ID 1)
+func setupBatchRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	r, db := setupUserFileRouter(t)
+	var admin models.User
+	require.NoError(t, db.First(&admin, "username = ?", "admin").Error)
+	for _, name := range []string{"bob", "carol"} {
+		require.NoError(t, db.Create(&models.User{Username: name, Password: "x", Role: models.RoleCustomer}).Error)
+	}
+	asAdmin := func(c *gin.Context) {
+		c.Request = c.Request.WithContext(utils.WithInformation(c.Request.Context(), &admin))
+	}
+	h := NewUserController(utils.NewValidator(db), services.NewUserService(db, repo.NewGormUserRepo(db), repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db)))
+	r.POST("/api/v1/users/batch", asAdmin, h.UsersBatch)
+	r.POST("/api/v1/users/:id/activate", asAdmin, h.UsersActivate)
+	r.POST("/api/v1/users/:id/disable", asAdmin, h.UsersDisable)
+	r.POST("/api/v1/users/:id/lock", asAdmin, h.UsersLock)
+	r.POST("/api/v1/users/:id/unlock", asAdmin, h.UsersUnlock)
+	return r, db
+}
+
+func postBatch(t *testing.T, r *gin.Engine, body string) (int, userResponse.BatchResult) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var res userResponse.BatchResult
+	if w.Code == http.StatusOK || w.Code == http.StatusMultiStatus {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), w.Body.String())
+	}
+	return w.Code, res
+}
+
+func itemStatuses(res userResponse.BatchResult) []int {
+	out := make([]int, 0, len(res.Results))
+	for _, r := range res.Results {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func findUser(t *testing.T, db *gorm.DB, id uint) *models.User {
+	t.Helper()
+	var u models.User
+	require.NoError(t, db.Unscoped().First(&u, id).Error)
+	return &u
+}
+
+func TestUsersBatch_AtomicRollsBackOnFailure(t *testing.T) {
+	r, db := setupBatchRouter(t)
+
+	code, res := postBatch(t, r, `{"operations": [
+		{"op": "set_role", "id": 2, "role": "staff"},
+		{"op": "delete", "id": 99}
+	]}`)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusNotFound}, itemStatuses(res))
+	assert.Equal(t, utils.BATCH_ROLLED_BACK.ID, res.Results[0].Code)
+	assert.Equal(t, models.RoleCustomer, findUser(t, db, 2).Role) // đã rollback
+
+	code, res = postBatch(t, r, `{"operations": [
+		{"op": "set_role", "id": 2, "role": "staff"},
+		{"op": "disable", "id": 3, "reason": "Spam"},
+		{"op": "delete", "id": 3}
+	]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, res.Succeeded)
+	assert.Equal(t, models.RoleStaff, findUser(t, db, 2).Role)
+	carol := findUser(t, db, 3)
+	assert.Equal(t, models.UserDisabled, carol.Status)
+	assert.Equal(t, "Spam", carol.StatusReason)
+	assert.True(t, carol.DeletedAt.Valid)
+}
+
+func TestUsersBatch_PartialReportsPerItemStatus(t *testing.T) {
+	r, db := setupBatchRouter(t)
+
+	code, res := postBatch(t, r, `{"mode": "partial", "operations": [
+		{"op": "delete", "id": 2},
+		{"op": "restore", "id": 2},
+		{"op": "restore", "id": 3},
+		{"op": "set_role", "id": 3},
+		{"op": "disable", "id": 1, "reason": "Test"},
+		{"op": "rename", "id": 3}
+	]}`)
+	assert.Equal(t, http.StatusMultiStatus, code)
+	assert.Equal(t, []int{
+		http.StatusOK,         // xoá bob
+		http.StatusOK,         // khôi phục bob
+		http.StatusNotFound,   // carol chưa bị xoá
+		http.StatusBadRequest, // set_role thiếu role
+		http.StatusForbidden,  // không tự vô hiệu hoá
+		http.StatusBadRequest, // thao tác không hợp lệ
+	}, itemStatuses(res))
+	assert.Equal(t, 2, res.Succeeded)
+	assert.Equal(t, 4, res.Failed)
+	assert.Equal(t, "role", res.Results[3].Errors[0].Field)
+	assert.Equal(t, utils.ROLE_REQUIRE.ID, res.Results[3].Errors[0].Code)
+	assert.Equal(t, utils.CANNOT_MODIFY_SELF.ID, res.Results[4].Code)
+	assert.Equal(t, utils.INVALID_BATCH_OP.ID, res.Results[5].Errors[0].Code)
+	assert.False(t, findUser(t, db, 2).DeletedAt.Valid)
+	assert.Equal(t, models.UserActive, findUser(t, db, 1).Status)
+}
+
+func TestUsersBatch_Limits(t *testing.T) {
+	r, _ := setupBatchRouter(t)
+
+	code, _ := postBatch(t, r, `{"operations": []}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	ops := make([]map[string]any, 101)
+	for i := range ops {
+		ops[i] = map[string]any{"op": "delete", "id": 2}
+	}
+	body, _ := json.Marshal(map[string]any{"operations": ops})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"BATCH_SIZE"`)
+}
@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"go-demo-gin/pkg/tabular"
+	userRequest "go-demo-gin/requests/user"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+var _ errorResponse.Problem
+
+// Cột của file export: key JSON Lines (giống JSON của UserDetail) và tiêu đề đã dịch (CSV/XLSX)
+var exportColumns = []struct {
+	key    string
+	header *i18n.Message
+}{
+	{"id", utils.EXPORT_COL_ID},
+	{"username", utils.EXPORT_COL_USERNAME},
+	{"full_name", utils.EXPORT_COL_FULL_NAME},
+	{"role", utils.EXPORT_COL_ROLE},
+	{"birthday", utils.EXPORT_COL_BIRTHDAY},
+	{"created_at", utils.EXPORT_COL_CREATED_AT},
+	{"updated_at", utils.EXPORT_COL_UPDATED_AT},
+}
+
+// UsersExport exports users to a CSV, JSON Lines or XLSX file
+//
+// @Summary      Export users
+// @Description  Stream users matching `search`/`sort` (same as GET /users) to a file. Rows are read from the database in batches; CSV and JSON Lines are written as they are read.
+// @Description  CSV/XLSX column headers follow the request language. The export runs synchronously within the request (it is not a background job).
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format  query     string  false  "File format"  Enums(csv, jsonl, xlsx)  default(csv)
+// @Param        search  query     string  false  "Search by name or username"
+// @Param        sort    query     string  false  "Sort, e.g. `id desc`"
+// @Success      200     {file}    file
+// @Failure      400     {object}  errorResponse.Problem
+// @Failure      500     {object}  errorResponse.Problem
+// @Router       /api/v1/users/export [get]
+func (h *UserController) UsersExport(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the export users controller", nil)
+
+	// Get options off query string
+	var opts userRequest.UserExport
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+	format := tabular.Format(opts.Format)
+	if format == "" {
+		format = tabular.CSV
+	}
+
+	localizer := utils.LocalizerFrom(ctx)
+	keys := make([]string, len(exportColumns))
+	header := make([]string, len(exportColumns))
+	for i, col := range exportColumns {
+		keys[i] = col.key
+		header[i] = utils.LoadI18nMessage(localizer, col.header, nil)
+	}
+
+	// Header HTTP chỉ được gửi khi ghi lô đầu tiên => lỗi trước đó vẫn trả về Problem bình thường
+	filename := "users-" + time.Now().Format("20060102-150405") + "." + string(format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	w, err := tabular.NewWriter(c.Writer, format, header, keys)
+	if err == nil {
+		err = h.svc.ExportUsers(ctx, opts.Search, opts.Sort, func(users []userResponse.UserDetail) error {
+			for i := range users {
+				if err := w.WriteRow(exportRow(&users[i])); err != nil {
+					return err
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		})
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if c.Writer.Written() {
+			// Đã gửi một phần file => không thể đổi status, chỉ ghi log (client nhận file bị cắt)
+			utils.LogCtx(ctx, logrus.ErrorLevel, "Export users aborted: "+err.Error(), nil)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Export users failed: "+err.Error(), nil)
+	}
+}
+
+func exportRow(u *userResponse.UserDetail) []any {
+	birthday := ""
+	if !u.Birthday.IsZero() {
+		birthday = u.Birthday.Format("2006-01-02")
+	}
+	return []any{
+		u.ID,
+		u.Username,
+		u.Name,
+		u.Role,
+		birthday,
+		u.CreatedAt.Format(time.RFC3339),
+		u.UpdatedAt.Format(time.RFC3339),
+	}
+}
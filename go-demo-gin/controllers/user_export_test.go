@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"bytes"
+	"database/sql"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/tabular"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func getExport(r *gin.Engine, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/export"+query, nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func seedExportUsers(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	birthday := time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, name := range []string{"bob", "carol"} {
+		require.NoError(t, db.Create(&models.User{Username: name, Name: sql.NullString{String: strings.ToUpper(name), Valid: true}, Password: "x", Role: models.RoleCustomer, Birthday: &birthday}).Error)
+	}
+}
+
+func TestUsersExport_CSVLocalizedHeader(t *testing.T) {
+	r, db := setupUserFileRouter(t)
+	seedExportUsers(t, db)
+
+	w := getExport(r, "?sort=id%20desc&lang=vi")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="users-\d{8}-\d{6}\.csv"$`, w.Header().Get("Content-Disposition"))
+
+	rows, err := tabular.ReadAll(w.Body, tabular.CSV, 0)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"ID", "Tên đăng nhập", "Họ tên", "Vai trò", "Ngày sinh", "Ngày tạo", "Ngày cập nhật"}, rows[0])
+	assert.Equal(t, []string{"3", "carol", "CAROL", "customer", "2000-01-02"}, rows[1][:5])
+	assert.Equal(t, "admin", rows[3][1])
+}
+
+func TestUsersExport_JSONLAndXLSX(t *testing.T) {
+	r, db := setupUserFileRouter(t)
+	seedExportUsers(t, db)
+
+	w := getExport(r, "?format=jsonl&sort=id%20asc")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], `{"id":2,"username":"bob","full_name":"BOB","role":"customer","birthday":"2000-01-02",`), lines[1])
+
+	w = getExport(r, "?format=xlsx")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	rows, err := tabular.ReadAll(bytes.NewReader(w.Body.Bytes()), tabular.XLSX, 0)
+	require.NoError(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, "Username", rows[0][1])
+}
+
+func TestUsersExport_InvalidOptions(t *testing.T) {
+	r, _ := setupUserFileRouter(t)
+
+	w := getExport(r, "?format=pdf")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"format"`)
+
+	// Sort không hợp lệ => Problem bình thường, không có Content-Disposition
+	w = getExport(r, "?sort=id%20sideways")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/problem+json")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	assert.Contains(t, w.Body.String(), `"field":"sort"`)
+}
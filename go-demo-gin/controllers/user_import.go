@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/pkg/tabular"
+	userRequest "go-demo-gin/requests/user"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxImportFileSize = 10 << 20 // 10MB
+	maxImportRows     = 1000     // mỗi dòng tốn 2 lần bcrypt (validate + hash)
+)
+
+// Cột bắt buộc trong dòng header (tên cột giống JSON của UserCreate)
+var importRequiredColumns = []string{"username", "password", "role"}
+
+// UsersImport imports users from a CSV/XLSX file
+//
+// @Summary      Import users
+// @Description  Create users from a CSV or XLSX file (header: username,password,full_name,role,birthday). Every row is validated with the same rules as POST /users, including duplicate usernames within the file.
+// @Description  `dry_run` only validates. `mode=atomic` creates nothing if any row fails; `mode=best_effort` creates the valid rows. `report=csv` downloads the per-row report.
+// @Description  The import runs synchronously within the request (it is not a background job), hence the 1000-row limit.
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Produce      text/csv
+// @Param        file     formData  file    true   "CSV or XLSX file (max 10MB, 1000 rows)"
+// @Param        dry_run  query     bool    false  "Validate only, do not create users"
+// @Param        mode     query     string  false  "Import mode"        Enums(atomic, best_effort)  default(atomic)
+// @Param        report   query     string  false  "Report format"      Enums(json, csv)            default(json)
+// @Success      200      {object}  userResponse.ImportResult
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/import [post]
+func (h *UserController) UsersImport(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the import users controller", nil)
+
+	// Get options off query string
+	var opts userRequest.UserImport
+	if err := c.ShouldBindQuery(&opts); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+	if opts.Mode == "" {
+		opts.Mode = userRequest.ImportAtomic
+	}
+
+	// Read rows from uploaded file
+	rows, fileErr := readImportFile(c)
+	if fileErr != nil {
+		utils.HandleValidationError(c, map[string]*i18n.Message{"file": fileErr})
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Import file rejected: "+fileErr.ID, nil)
+		return
+	}
+
+	// Validate rows, then create valid rows
+	result, err := h.importRows(ctx, rows, opts)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Import users failed: "+err.Error(), nil)
+		return
+	}
+
+	if opts.Report == "csv" {
+		writeImportReport(c, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Đọc file upload thành danh sách UserCreate; lỗi trả về là mã lỗi của field "file"
+func readImportFile(c *gin.Context) ([]userRequest.UserCreate, *i18n.Message) {
+	fh, err := c.FormFile("file")
+	if err != nil || fh.Size > maxImportFileSize {
+		return nil, utils.INVALID_IMPORT_FILE
+	}
+	format, err := tabular.FormatFromFilename(fh.Filename)
+	if err != nil {
+		return nil, utils.INVALID_IMPORT_FILE
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, utils.INVALID_IMPORT_FILE
+	}
+	defer f.Close()
+
+	records, err := tabular.ReadAll(f, format, maxImportRows)
+	if errors.Is(err, tabular.ErrTooManyRows) || (err == nil && len(records) < 2) {
+		return nil, utils.IMPORT_TOO_MANY_ROWS
+	}
+	if err != nil {
+		return nil, utils.INVALID_IMPORT_FILE
+	}
+
+	// Vị trí của từng cột theo header (không phân biệt hoa thường)
+	cols := map[string]int{}
+	for i, name := range records[0] {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importRequiredColumns {
+		if _, ok := cols[name]; !ok {
+			return nil, utils.IMPORT_MISSING_COLUMNS
+		}
+	}
+	cell := func(record []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rows := make([]userRequest.UserCreate, 0, len(records)-1)
+	for _, record := range records[1:] {
+		rows = append(rows, userRequest.UserCreate{
+			Username: cell(record, "username"),
+			Pass:     cell(record, "password"),
+			Name:     cell(record, "full_name"),
+			Role:     cell(record, "role"),
+			Date:     cell(record, "birthday"),
+		})
+	}
+	return rows, nil
+}
+
+func (h *UserController) importRows(ctx context.Context, rows []userRequest.UserCreate, opts userRequest.UserImport) (*userResponse.ImportResult, error) {
+	localizer := utils.LocalizerFrom(ctx)
+	result := &userResponse.ImportResult{DryRun: opts.DryRun, Mode: opts.Mode, Total: len(rows)}
+
+	// 1) Validate từng dòng (cùng rule với POST /users) + trùng username trong chính file
+	var valid []*userRequest.UserCreate
+	var validIdx []int // vị trí trong result.Rows của từng dòng hợp lệ
+	seen := map[string]bool{}
+	for i := range rows {
+		row := &rows[i]
+		fields := h.v.ValidateStructCtx(ctx, *row)
+		if seen[row.Username] {
+			if fields == nil {
+				fields = map[string]*i18n.Message{}
+			}
+			fields["username"] = utils.DUPLICATE_USERNAME
+		}
+		seen[row.Username] = true
+
+		r := userResponse.ImportRow{Row: i + 2, Username: row.Username, Status: userResponse.ImportRowValid}
+		if fields != nil {
+			r.Status = userResponse.ImportRowFailed
+			r.Errors = importFieldErrors(localizer, fields)
+			result.Failed++
+		} else {
+			valid = append(valid, row)
+			validIdx = append(validIdx, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, r)
+	}
+	if opts.DryRun || len(valid) == 0 {
+		return result, nil
+	}
+
+	// 2) Atomic: có dòng lỗi => không tạo dòng nào
+	if opts.Mode == userRequest.ImportAtomic && result.Failed > 0 {
+		markImportSkipped(localizer, result, validIdx)
+		return result, nil
+	}
+
+	// 3) Tạo user trong 1 transaction
+	rowErrs, err := h.svc.ImportUsers(ctx, valid, opts.Mode == userRequest.ImportAtomic)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Mode == userRequest.ImportAtomic && len(rowErrs) > 0 {
+		markImportSkipped(localizer, result, validIdx)
+	}
+	for i, idx := range validIdx {
+		r := &result.Rows[idx]
+		if rowErr, ok := rowErrs[i]; ok {
+			r.Status = userResponse.ImportRowFailed
+			r.Errors = []errorResponse.FieldError{importRowError(localizer, rowErr)}
+			result.Failed++
+			continue
+		}
+		if r.Status == userResponse.ImportRowValid {
+			r.Status = userResponse.ImportRowCreated
+			result.Created++
+		}
+	}
+	return result, nil
+}
+
+// Đánh dấu các dòng hợp lệ là skipped (chế độ atomic có dòng lỗi)
+func markImportSkipped(localizer *i18n.Localizer, result *userResponse.ImportResult, validIdx []int) {
+	for _, idx := range validIdx {
+		result.Rows[idx].Status = userResponse.ImportRowSkipped
+		result.Rows[idx].Errors = []errorResponse.FieldError{{
+			Code:   utils.IMPORT_SKIPPED.ID,
+			Detail: utils.LoadI18nMessage(localizer, utils.IMPORT_SKIPPED, nil),
+		}}
+	}
+}
+
+func importFieldErrors(localizer *i18n.Localizer, fields map[string]*i18n.Message) []errorResponse.FieldError {
+	// Sắp xếp theo tên field để báo cáo ổn định
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	out := make([]errorResponse.FieldError, 0, len(names))
+	for _, field := range names {
+		out = append(out, errorResponse.FieldError{
+			Field:  field,
+			Code:   fields[field].ID,
+			Detail: utils.LoadI18nMessage(localizer, fields[field], nil),
+		})
+	}
+	return out
+}
+
+func importRowError(localizer *i18n.Localizer, err error) errorResponse.FieldError {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		appErr = apperror.Internal(nil, err)
+	}
+	code := utils.ErrorCode(appErr)
+	return errorResponse.FieldError{Code: code.ID, Detail: utils.LoadI18nMessage(localizer, code, nil)}
+}
+
+// Báo cáo dạng CSV (row, username, status, errors) để tải về
+func writeImportReport(c *gin.Context, result *userResponse.ImportResult) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="user-import-report.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"row", "username", "status", "errors"})
+	for _, r := range result.Rows {
+		msgs := make([]string, 0, len(r.Errors))
+		for _, e := range r.Errors {
+			if e.Field != "" {
+				msgs = append(msgs, e.Field+": "+e.Detail)
+			} else {
+				msgs = append(msgs, e.Detail)
+			}
+		}
+		_ = w.Write([]string{strconv.Itoa(r.Row), r.Username, r.Status, strings.Join(msgs, "; ")})
+	}
+	w.Flush()
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-demo-gin/initializers"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const importCSV = `username,password,full_name,role,birthday
+alice,secret.123,Alice,staff,2000-01-02
+bob,secret.123,Bob,root,2000-01-02
+alice,secret.123,Alice 2,staff,2000-01-02
+admin,secret.123,Admin,admin,2000-01-02
+`
+
+// Router chỉ có route import/export, UserService thật trên sqlite in-memory (đã có user "admin")
+func setupUserFileRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, initializers.LoadI18n())
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // mỗi connection :memory: là một DB riêng
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.PasswordHistory{}))
+	require.NoError(t, db.Create(&models.User{Username: "admin", Password: "x", Role: models.RoleAdmin}).Error)
+
+	svc := services.NewUserService(db, repo.NewGormUserRepo(db), repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db))
+	h := NewUserController(utils.NewValidator(db), svc)
+
+	r := gin.New()
+	r.Use(middlewares.ErrorHandler(), middlewares.I18n())
+	r.POST("/api/v1/users/import", h.UsersImport)
+	r.GET("/api/v1/users/export", h.UsersExport)
+	return r, db
+}
+
+func uploadImport(t *testing.T, r *gin.Engine, query, filename string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, _ = fw.Write(content)
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/import"+query, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeImport(t *testing.T, w *httptest.ResponseRecorder) userResponse.ImportResult {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var res userResponse.ImportResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func rowStatuses(res userResponse.ImportResult) []string {
+	out := make([]string, 0, len(res.Rows))
+	for _, r := range res.Rows {
+		out = append(out, r.Status)
+	}
+	return out
+}
+
+func countUsers(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var n int64
+	require.NoError(t, db.Model(&models.User{}).Count(&n).Error)
+	return n
+}
+
+func TestUsersImport_DryRun(t *testing.T) {
+	r, db := setupUserFileRouter(t)
+
+	res := decodeImport(t, uploadImport(t, r, "?dry_run=true", "users.csv", []byte(importCSV)))
+
+	assert.Equal(t, []string{"valid", "failed", "failed", "failed"}, rowStatuses(res))
+	assert.Equal(t, 4, res.Total)
+	assert.Equal(t, 3, res.Failed)
+	assert.Equal(t, utils.INVALID_ROLE.ID, res.Rows[1].Errors[0].Code)
+	assert.Equal(t, utils.DUPLICATE_USERNAME.ID, res.Rows[2].Errors[0].Code) // trùng trong file
+	assert.Equal(t, utils.DUPLICATE_USERNAME.ID, res.Rows[3].Errors[0].Code) // trùng với DB
+	assert.Equal(t, 4, res.Rows[2].Row)                                      // dòng 1 là header
+	assert.Equal(t, int64(1), countUsers(t, db))
+}
+
+func TestUsersImport_AtomicAndBestEffort(t *testing.T) {
+	r, db := setupUserFileRouter(t)
+
+	// Atomic: có dòng lỗi => không tạo dòng nào
+	res := decodeImport(t, uploadImport(t, r, "", "users.csv", []byte(importCSV)))
+	assert.Equal(t, []string{"skipped", "failed", "failed", "failed"}, rowStatuses(res))
+	assert.Equal(t, 0, res.Created)
+	assert.Equal(t, int64(1), countUsers(t, db))
+
+	// Best effort: tạo các dòng hợp lệ
+	res = decodeImport(t, uploadImport(t, r, "?mode=best_effort", "users.csv", []byte(importCSV)))
+	assert.Equal(t, []string{"created", "failed", "failed", "failed"}, rowStatuses(res))
+	assert.Equal(t, 1, res.Created)
+	assert.Equal(t, int64(2), countUsers(t, db))
+}
+
+func TestUsersImport_XLSXWithCSVReport(t *testing.T) {
+	r, db := setupUserFileRouter(t)
+
+	f := excelize.NewFile()
+	for i, row := range [][]any{
+		{"Username", "Password", "Role", "Birthday"}, // header không phân biệt hoa thường, thiếu full_name vẫn được
+		{"carol", "secret.123", "customer", "2000-01-02"},
+		{"dave", "secret.123", "customer", "2000-01-02"},
+	} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		require.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	buf, err := f.WriteToBuffer()
+	require.NoError(t, err)
+
+	w := uploadImport(t, r, "?report=csv&lang=vi", "users.xlsx", buf.Bytes())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "user-import-report.csv")
+	assert.Equal(t, "row,username,status,errors\n2,carol,created,\n3,dave,created,\n", w.Body.String())
+	assert.Equal(t, int64(3), countUsers(t, db))
+}
+
+func TestUsersImport_InvalidFile(t *testing.T) {
+	r, _ := setupUserFileRouter(t)
+
+	w := uploadImport(t, r, "", "users.csv", []byte("name,role\nx,staff\n"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"IMPORT_MISSING_COLUMNS"`)
+
+	w = uploadImport(t, r, "", "users.txt", []byte(importCSV))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"INVALID_IMPORT_FILE"`)
+
+	w = uploadImport(t, r, "?mode=all", "users.csv", []byte(importCSV))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"mode"`)
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	userRequest "go-demo-gin/requests/user"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ userResponse.UserDetail
+	_ errorResponse.Problem
+)
+
+// UsersActivate activates a pending or disabled user
+//
+// @Summary      Activate user
+// @Description  Move a `pending` or `disabled` account to `active`
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true   "User ID"
+// @Param        request  body      userRequest.UserStatusChange  false  "Optional reason"
+// @Success      200      {object}  userResponse.UserDetail
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/{id}/activate [post]
+func (h *UserController) UsersActivate(c *gin.Context) {
+	h.changeStatus(c, userRequest.StatusActivate)
+}
+
+// UsersDisable disables an user
+//
+// @Summary      Disable user
+// @Description  Disable a `pending`, `active` or `locked` account. A reason is required; existing tokens are rejected immediately.
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true  "User ID"
+// @Param        request  body      userRequest.UserStatusChange  true  "Reason"
+// @Success      200      {object}  userResponse.UserDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/{id}/disable [post]
+func (h *UserController) UsersDisable(c *gin.Context) {
+	h.changeStatus(c, userRequest.StatusDisable)
+}
+
+// UsersLock locks an active user
+//
+// @Summary      Lock user
+// @Description  Temporarily lock an `active` account; existing tokens are rejected immediately
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true   "User ID"
+// @Param        request  body      userRequest.UserStatusChange  false  "Optional reason"
+// @Success      200      {object}  userResponse.UserDetail
+// @Failure      403      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/{id}/lock [post]
+func (h *UserController) UsersLock(c *gin.Context) {
+	h.changeStatus(c, userRequest.StatusLock)
+}
+
+// UsersUnlock unlocks a locked user
+//
+// @Summary      Unlock user
+// @Description  Move a `locked` account back to `active`
+// @Tags         👨🏻‍💼Users
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true   "User ID"
+// @Param        request  body      userRequest.UserStatusChange  false  "Optional reason"
+// @Success      200      {object}  userResponse.UserDetail
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      409      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/users/{id}/unlock [post]
+func (h *UserController) UsersUnlock(c *gin.Context) {
+	h.changeStatus(c, userRequest.StatusUnlock)
+}
+
+// Dùng chung cho các endpoint đổi trạng thái; body chỉ bắt buộc với disable (validate lo phần đó)
+func (h *UserController) changeStatus(c *gin.Context, action string) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the change user status controller", logrus.Fields{"action": action})
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get data off request body
+	var change userRequest.UserStatusChange
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&change); err != nil {
+			utils.HandleBindError(c, err)
+			// Logging
+			utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+			return
+		}
+	}
+	change.Action = action
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, change); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Change status
+	detail, err := h.svc.ChangeUserStatus(ctx, id, &change)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Change user status failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
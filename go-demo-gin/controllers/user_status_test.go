@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-demo-gin/models"
+	errorResponse "go-demo-gin/responses/error"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postStatus(t *testing.T, r *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func problemCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p errorResponse.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p), w.Body.String())
+	return p.Code
+}
+
+func TestUsersStatus_Transitions(t *testing.T) {
+	r, db := setupBatchRouter(t)
+
+	// Disable bắt buộc có lý do
+	w := postStatus(t, r, "/api/v1/users/2/disable", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), utils.REASON_REQUIRE.ID)
+
+	w = postStatus(t, r, "/api/v1/users/2/disable", `{"reason": "Spam"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var detail userResponse.UserDetail
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.Equal(t, string(models.UserDisabled), detail.Status)
+	assert.Equal(t, "Spam", detail.StatusReason)
+
+	// disabled => lock không hợp lệ
+	w = postStatus(t, r, "/api/v1/users/2/lock", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, utils.INVALID_STATUS_TRANSITION.ID, problemCode(t, w))
+
+	w = postStatus(t, r, "/api/v1/users/2/activate", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	bob := findUser(t, db, 2)
+	assert.Equal(t, models.UserActive, bob.Status)
+	assert.Empty(t, bob.StatusReason)
+	assert.NotNil(t, bob.StatusChangedAt)
+
+	w = postStatus(t, r, "/api/v1/users/2/lock", `{"reason": "Too many attempts"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = postStatus(t, r, "/api/v1/users/2/unlock", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, models.UserActive, findUser(t, db, 2).Status)
+
+	// Mỗi lần đổi trạng thái đều được audit
+	var audits int64
+	db.Model(&models.AuditEvent{}).Where("action = ? AND target_id = ?", models.AuditUserStatus, 2).Count(&audits)
+	assert.Equal(t, int64(4), audits)
+}
+
+func TestUsersStatus_Errors(t *testing.T) {
+	r, _ := setupBatchRouter(t)
+
+	w := postStatus(t, r, "/api/v1/users/1/lock", "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, utils.CANNOT_MODIFY_SELF.ID, problemCode(t, w))
+
+	w = postStatus(t, r, "/api/v1/users/99/unlock", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = postStatus(t, r, "/api/v1/users/2/activate", "")
+	assert.Equal(t, http.StatusConflict, w.Code) // đã active
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"encoding/json"
+	"go-demo-gin/models"
+	userResponse "go-demo-gin/responses/user"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersCreate(t *testing.T) {
+	r, db := setupPasswordRouter(t)
+
+	w := sendJSON(r, http.MethodPost, "/api/v1/users", `{"username":"alice","password":"secret.123","full_name":"Alice","role":"staff","birthday":"2000-01-02"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var detail userResponse.UserDetail
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &detail))
+	assert.NotZero(t, detail.ID)
+	assert.Equal(t, "alice", detail.Username)
+
+	// User, audit và outbox event được ghi cùng transaction
+	created := findUser(t, db, detail.ID)
+	assert.Equal(t, models.UserActive, created.Status)
+	assert.NotEqual(t, "secret.123", created.Password)
+	var audits, outbox int64
+	db.Model(&models.AuditEvent{}).Where("action = ? AND target_id = ?", models.AuditUserCreate, detail.ID).Count(&audits)
+	db.Model(&models.OutboxEvent{}).Count(&outbox)
+	assert.Equal(t, int64(1), audits)
+	assert.Equal(t, int64(1), outbox)
+}
+
+func TestUsersCreate_Errors(t *testing.T) {
+	r, db := setupPasswordRouter(t)
+
+	// Validation (username trùng) => 400
+	w := sendJSON(r, http.MethodPost, "/api/v1/users", `{"username":"admin","password":"secret.123","role":"staff","birthday":"2000-01-02"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Body sai JSON => 400
+	w = sendJSON(r, http.MethodPost, "/api/v1/users", `{"username":`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Lỗi DB khi ghi (request hợp lệ) => 500 CREATE_FAIL, không còn user nào được tạo
+	require.NoError(t, db.Migrator().DropTable(&models.AuditEvent{}))
+	w = sendJSON(r, http.MethodPost, "/api/v1/users", `{"username":"bob","password":"secret.123","role":"staff","birthday":"2000-01-02"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "CREATE_FAIL", problemCode(t, w))
+	assert.Equal(t, int64(1), countUsers(t, db))
+}
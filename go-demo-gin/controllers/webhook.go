@@ -0,0 +1,323 @@
+package controllers
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	webhookRequest "go-demo-gin/requests/webhook"
+	errorResponse "go-demo-gin/responses/error"
+	webhookResponse "go-demo-gin/responses/webhook"
+	"go-demo-gin/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	_ webhookResponse.WebhookDetail
+	_ webhookResponse.WebhookDelivery
+	_ errorResponse.Problem
+)
+
+type WebhookService interface {
+	CreateEndpoint(ctx context.Context, in *webhookRequest.WebhookCreate) (*webhookResponse.WebhookDetail, error)
+	GetEndpointList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error)
+	GetEndpointById(ctx context.Context, id string) (*webhookResponse.WebhookDetail, error)
+	UpdateEndpoint(ctx context.Context, in *webhookRequest.WebhookUpdate, id string) (*webhookResponse.WebhookDetail, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	GetDeliveryList(ctx context.Context, id string, pag *pkg.Pagination) (*pkg.Pagination, error)
+	SendTestEvent(ctx context.Context, id string) (*webhookResponse.WebhookDelivery, error)
+}
+
+type WebhookController struct {
+	v   *utils.Validator
+	svc WebhookService
+}
+
+func NewWebhookController(v *utils.Validator, svc WebhookService) *WebhookController {
+	return &WebhookController{v: v, svc: svc}
+}
+
+// WebhooksCreate registers a new webhook endpoint
+//
+// @Summary      Create webhook
+// @Description  Register a webhook URL for selected user event types. The signing secret is only returned once.
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      webhookRequest.WebhookCreate  true  "Webhook to create"
+// @Success      201      {object}  webhookResponse.WebhookDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks [post]
+func (h *WebhookController) WebhooksCreate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create webhook controller", nil)
+
+	// Get data off request body
+	var create webhookRequest.WebhookCreate
+	if err := c.ShouldBindJSON(&create); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, create); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Create webhook
+	detail, err := h.svc.CreateEndpoint(ctx, &create)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Create webhook failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// WebhooksIndex lists webhook endpoints
+//
+// @Summary      List webhooks
+// @Description  Get list of registered webhook endpoints
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]webhookResponse.WebhookDetail}
+// @Failure      400   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks [get]
+func (h *WebhookController) WebhooksIndex(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of webhooks controller", nil)
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get webhook list
+	result, err := h.svc.GetEndpointList(ctx, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of webhooks failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// WebhooksShow get webhook detail
+//
+// @Summary      Get webhook detail
+// @Description  Get webhook endpoint by ID
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  webhookResponse.WebhookDetail
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks/{id} [get]
+func (h *WebhookController) WebhooksShow(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get webhook by id controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get webhook detail
+	detail, err := h.svc.GetEndpointById(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get webhook by id failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// WebhooksUpdate updates a webhook endpoint
+//
+// @Summary      Update webhook
+// @Description  Update URL, event types, secret or re-enable a webhook endpoint
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                           true  "Webhook ID"
+// @Param        request  body      webhookRequest.WebhookUpdate  true  "Updated webhook data"
+// @Success      200      {object}  webhookResponse.WebhookDetail
+// @Failure      400      {object}  errorResponse.Problem
+// @Failure      404      {object}  errorResponse.Problem
+// @Failure      500      {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks/{id} [put]
+func (h *WebhookController) WebhooksUpdate(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update webhook controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get data off request body
+	var update webhookRequest.WebhookUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Validation
+	if err := h.v.ValidateStructCtx(ctx, update); err != nil {
+		utils.HandleValidationError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Validation failed", nil)
+		return
+	}
+
+	// Update webhook
+	detail, err := h.svc.UpdateEndpoint(ctx, &update, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Update webhook failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// WebhooksDelete deletes a webhook endpoint
+//
+// @Summary      Delete webhook
+// @Description  Delete webhook endpoint by ID
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks/{id} [delete]
+func (h *WebhookController) WebhooksDelete(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete webhook controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Delete webhook
+	err := h.svc.DeleteEndpoint(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Delete webhook failed: "+err.Error(), nil)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// WebhooksDeliveries lists delivery history of a webhook endpoint
+//
+// @Summary      List webhook deliveries
+// @Description  Get delivery history (attempts, response codes, errors) of a webhook endpoint
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id			path      int     true   "Webhook ID"
+// @Param        limit		query     string  false  "Number of results per page"				default(10)
+// @Param        page		query     string  false  "Current page in the paginated results"	default(1)
+// @Param        sort		query     string  false  "Sorting criteria for the results"			default(id desc)
+// @Success      200   {array}   pkg.Pagination{result=[]webhookResponse.WebhookDelivery}
+// @Failure      404   {object}  errorResponse.Problem
+// @Failure      500   {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookController) WebhooksDeliveries(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of webhook deliveries controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Get pagination
+	var pag pkg.Pagination
+	if err := c.ShouldBindQuery(&pag); err != nil {
+		utils.HandleBindError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Request binding failed: "+err.Error(), nil)
+		return
+	}
+
+	// Get delivery list
+	result, err := h.svc.GetDeliveryList(ctx, id, &pag)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Get list of webhook deliveries failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// WebhooksTest sends a test event to a webhook endpoint
+//
+// @Summary      Send test event
+// @Description  Immediately send a signed "webhook.test" event and return the delivery result
+// @Tags         🪝Webhooks
+// @Security	 BearerAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  webhookResponse.WebhookDelivery
+// @Failure      404  {object}  errorResponse.Problem
+// @Failure      500  {object}  errorResponse.Problem
+// @Router       /api/v1/webhooks/{id}/test [post]
+func (h *WebhookController) WebhooksTest(c *gin.Context) {
+	// Logging
+	ctx := c.Request.Context()
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the send test webhook controller", nil)
+
+	// Get id from url
+	id := c.Param("id")
+
+	// Send test event
+	delivery, err := h.svc.SendTestEvent(ctx, id)
+	if err != nil {
+		utils.HandleServiceError(c, err)
+		// Logging
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Send test webhook failed: "+err.Error(), nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
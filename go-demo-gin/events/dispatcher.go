@@ -0,0 +1,151 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type OutboxRepository interface {
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id uint, sinks string, at time.Time) error
+	MarkFailed(ctx context.Context, id uint, attempts int, sinks string, next time.Time, lastErr string) error
+	MarkDead(ctx context.Context, id uint, attempts int, sinks string, lastErr string) error
+}
+
+type DispatcherConfig struct {
+	PollInterval time.Duration // chu kỳ quét outbox
+	BatchSize    int           // số event tối đa mỗi lần quét
+	Lease        time.Duration // thời gian giữ chỗ event đang gửi
+	MaxAttempts  int           // vượt quá => dead-letter
+	BaseBackoff  time.Duration // backoff = BaseBackoff * 2^(attempts-1), tối đa MaxBackoff
+	MaxBackoff   time.Duration
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        time.Minute,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// Dispatcher đọc outbox và gửi event tới tất cả sink (at-least-once).
+// Sink đã nhận thành công được ghi lại để lần thử sau chỉ gửi cho các sink còn lỗi.
+type Dispatcher struct {
+	repo  OutboxRepository
+	cfg   DispatcherConfig
+	sinks []Sink
+	now   func() time.Time
+	log   *logrus.Entry
+}
+
+func NewDispatcher(repo OutboxRepository, cfg DispatcherConfig, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		repo:  repo,
+		cfg:   cfg,
+		sinks: sinks,
+		now:   time.Now,
+		log:   logrus.WithField("source", "outbox"),
+	}
+}
+
+// Run quét outbox theo chu kỳ cho tới khi ctx bị huỷ
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.log.Info("Outbox dispatcher started")
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchOnce(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			d.log.WithError(err).Error("Outbox dispatch failed")
+		}
+		select {
+		case <-ctx.Done():
+			d.log.Info("Outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce xử lý một lô event đến hạn, trả về số event đã gửi thành công
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	batch, err := d.repo.ClaimDue(ctx, d.now(), d.cfg.BatchSize, d.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, m := range batch {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		ok, err := d.deliver(ctx, m)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, m models.OutboxEvent) (bool, error) {
+	e := FromOutbox(m)
+	done := splitSinks(m.DeliveredSinks)
+
+	var errs []error
+	for _, s := range d.sinks {
+		if slices.Contains(done, s.Name()) {
+			continue
+		}
+		if err := s.Publish(ctx, e); err != nil {
+			errs = append(errs, errors.New(s.Name()+": "+err.Error()))
+			continue
+		}
+		done = append(done, s.Name())
+	}
+	sinks := strings.Join(done, ",")
+
+	if len(errs) == 0 {
+		return true, d.repo.MarkDelivered(ctx, m.ID, sinks, d.now())
+	}
+
+	attempts := m.Attempts + 1
+	lastErr := errors.Join(errs...).Error()
+	log := d.log.WithFields(logrus.Fields{"event_id": m.EventID, "type": m.Type, "attempts": attempts})
+	if attempts >= d.cfg.MaxAttempts {
+		log.WithField("error", lastErr).Error("Outbox event moved to dead-letter")
+		return false, d.repo.MarkDead(ctx, m.ID, attempts, sinks, lastErr)
+	}
+	log.WithField("error", lastErr).Warn("Outbox event delivery failed, will retry")
+	return false, d.repo.MarkFailed(ctx, m.ID, attempts, sinks, d.now().Add(Backoff(d.cfg.BaseBackoff, d.cfg.MaxBackoff, attempts)), lastErr)
+}
+
+// Backoff luỹ thừa: base * 2^(attempts-1), chặn trên bởi max
+func Backoff(base, max time.Duration, attempts int) time.Duration {
+	b := base
+	for i := 1; i < attempts; i++ {
+		b *= 2
+		if b >= max {
+			return max
+		}
+	}
+	return b
+}
+
+func splitSinks(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
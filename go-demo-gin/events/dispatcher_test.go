@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Sink giả lập: lỗi trong failTimes lần đầu, sau đó thành công
+type fakeSink struct {
+	name      string
+	failTimes int
+	received  []Event
+}
+
+func (s *fakeSink) Name() string { return s.name }
+
+func (s *fakeSink) Publish(ctx context.Context, e Event) error {
+	if s.failTimes > 0 {
+		s.failTimes--
+		return errors.New("unavailable")
+	}
+	s.received = append(s.received, e)
+	return nil
+}
+
+func setupOutbox(t *testing.T) (*gorm.DB, *repo.GormOutboxRepo) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite memory: %v", err)
+	}
+	if err := db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, repo.NewGormOutboxRepo(db)
+}
+
+func enqueue(t *testing.T, r *repo.GormOutboxRepo) *models.OutboxEvent {
+	t.Helper()
+	e, err := NewOutboxEvent(UserCreated, "user", 1, UserPayload{ID: 1, Username: "alice"})
+	if err != nil {
+		t.Fatalf("new outbox event: %v", err)
+	}
+	if err := r.Create(context.Background(), e); err != nil {
+		t.Fatalf("create outbox event: %v", err)
+	}
+	return e
+}
+
+func testConfig() DispatcherConfig {
+	cfg := DefaultDispatcherConfig()
+	cfg.MaxAttempts = 3
+	cfg.BaseBackoff = time.Minute
+	return cfg
+}
+
+func TestDispatcher_DeliversToAllSinks(t *testing.T) {
+	db, r := setupOutbox(t)
+	e := enqueue(t, r)
+
+	bus := NewBus()
+	var got []Event
+	bus.Subscribe(UserCreated, func(ctx context.Context, e Event) error {
+		got = append(got, e)
+		return nil
+	})
+	sink := &fakeSink{name: "fake"}
+
+	d := NewDispatcher(r, testConfig(), bus, sink)
+	n, err := d.DispatchOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, got, 1)
+	assert.Len(t, sink.received, 1)
+	assert.Equal(t, e.EventID, sink.received[0].ID)
+	assert.JSONEq(t, `{"id":1,"username":"alice","full_name":"","role":""}`, string(sink.received[0].Payload))
+
+	var stored models.OutboxEvent
+	db.First(&stored, e.ID)
+	assert.Equal(t, models.OutboxDelivered, stored.Status)
+	assert.NotNil(t, stored.DeliveredAt)
+
+	// Đã gửi rồi thì lần quét sau không gửi lại
+	n, err = d.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDispatcher_RetriesWithBackoffOnlyFailedSinks(t *testing.T) {
+	db, r := setupOutbox(t)
+	e := enqueue(t, r)
+
+	ok := &fakeSink{name: "ok"}
+	flaky := &fakeSink{name: "flaky", failTimes: 1}
+	d := NewDispatcher(r, testConfig(), ok, flaky)
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	n, err := d.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	var stored models.OutboxEvent
+	db.First(&stored, e.ID)
+	assert.Equal(t, models.OutboxPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "ok", stored.DeliveredSinks)
+	assert.WithinDuration(t, now.Add(time.Minute), stored.NextAttemptAt, time.Second)
+
+	// Chưa tới hạn backoff => không gửi lại
+	n, _ = d.DispatchOnce(context.Background())
+	assert.Equal(t, 0, n)
+
+	// Tới hạn => chỉ gửi lại cho sink lỗi
+	now = now.Add(2 * time.Minute)
+	n, err = d.DispatchOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, ok.received, 1)
+	assert.Len(t, flaky.received, 1)
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	db, r := setupOutbox(t)
+	e := enqueue(t, r)
+
+	sink := &fakeSink{name: "down", failTimes: 100}
+	d := NewDispatcher(r, testConfig(), sink)
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	for i := 0; i < 3; i++ {
+		_, err := d.DispatchOnce(context.Background())
+		assert.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+
+	var stored models.OutboxEvent
+	db.First(&stored, e.ID)
+	assert.Equal(t, models.OutboxDead, stored.Status)
+	assert.Equal(t, 3, stored.Attempts)
+	assert.Contains(t, stored.LastError, "down: unavailable")
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"go-demo-gin/models"
+	"time"
+)
+
+// Các loại domain event của user
+const (
+	UserCreated       = "user.created"
+	UserUpdated       = "user.updated"
+	UserDeleted       = "user.deleted"
+	UserRoleChanged   = "user.role_changed"
+	UserRestored      = "user.restored"
+	UserStatusChanged = "user.status_changed"
+
+	// Event giả lập dùng cho chức năng "send test event" của webhook
+	WebhookTest = "webhook.test"
+)
+
+type Event struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	TenantID      uint            `json:"tenant_id,omitempty"` // tenant của aggregate (0: event không thuộc tenant nào)
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// UserPayload: dữ liệu user gửi kèm event (không bao giờ chứa password)
+type UserPayload struct {
+	ID           uint   `json:"id"`
+	Username     string `json:"username"`
+	Name         string `json:"full_name"`
+	Role         string `json:"role"`
+	PreviousRole string `json:"previous_role,omitempty"`
+	Status       string `json:"status,omitempty"`
+	// Chỉ có trong user.status_changed
+	PreviousStatus string `json:"previous_status,omitempty"`
+	StatusReason   string `json:"status_reason,omitempty"`
+}
+
+func NewUserPayload(u *models.User) UserPayload {
+	return UserPayload{
+		ID:       u.ID,
+		Username: u.Username,
+		Name:     u.Name.String,
+		Role:     string(u.Role),
+		Status:   string(u.Status),
+	}
+}
+
+// NewOutboxEvent tạo bản ghi outbox (chưa lưu) từ payload bất kỳ
+func NewOutboxEvent(eventType, aggregateType string, aggregateID uint, payload any) (*models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &models.OutboxEvent{
+		EventID:       NewID(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(data),
+		Status:        models.OutboxPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// FromOutbox chuyển bản ghi outbox thành Event để gửi tới sink
+func FromOutbox(m models.OutboxEvent) Event {
+	return Event{
+		ID:            m.EventID,
+		Type:          m.Type,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID,
+		TenantID:      m.TenantID,
+		OccurredAt:    m.CreatedAt,
+		Payload:       json.RawMessage(m.Payload),
+	}
+}
+
+// NewID sinh ID ngẫu nhiên dạng UUID v4 (consumer dùng để khử trùng lặp)
+func NewID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
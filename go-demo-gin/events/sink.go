@@ -0,0 +1,121 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Sink là nơi nhận event từ dispatcher. Publish trả lỗi => dispatcher sẽ thử lại.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, e Event) error
+}
+
+// Handler xử lý event cho subscriber trong cùng process
+type Handler func(ctx context.Context, e Event) error
+
+// Bus: sink in-process, gọi lần lượt các subscriber đã đăng ký theo loại event ("*" = tất cả)
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+func (b *Bus) Subscribe(eventType string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], h)
+}
+
+func (b *Bus) Name() string { return "bus" }
+
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	b.mu.RLock()
+	hs := append(append([]Handler{}, b.handlers[e.Type]...), b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range hs {
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookSink gửi event dạng JSON tới một URL cố định; mã 2xx được coi là thành công
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookSink{url: url, client: client}
+}
+
+func (s *WebhookSink) Name() string { return "webhook:" + s.url }
+
+func (s *WebhookSink) Publish(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", e.ID)
+	req.Header.Set("X-Event-Type", e.Type)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// Publisher: interface tối thiểu của message broker kiểu NATS/Kafka
+// (subject/topic + key để phân partition + headers)
+type Publisher interface {
+	Publish(ctx context.Context, topic, key string, payload []byte, headers map[string]string) error
+}
+
+// BrokerSink chuyển event sang Publisher; topic = prefix + loại event (vd: "users.user.created")
+type BrokerSink struct {
+	pub         Publisher
+	topicPrefix string
+}
+
+func NewBrokerSink(pub Publisher, topicPrefix string) *BrokerSink {
+	return &BrokerSink{pub: pub, topicPrefix: topicPrefix}
+}
+
+func (s *BrokerSink) Name() string { return "broker:" + s.topicPrefix }
+
+func (s *BrokerSink) Publish(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.pub.Publish(ctx, s.topicPrefix+e.Type, fmt.Sprint(e.AggregateID), body, map[string]string{
+		"event-id":   e.ID,
+		"event-type": e.Type,
+	})
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/utils"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Error: lỗi GraphQL có extensions.code (mã lỗi ổn định, giống "code" của problem+json)
+// và extensions.fields cho lỗi validation.
+type Error struct {
+	message    string
+	extensions map[string]any
+}
+
+func (e *Error) Error() string { return e.message }
+
+func (e *Error) Extensions() map[string]any { return e.extensions }
+
+// FieldError: lỗi theo field trong extensions.fields
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// newError chuyển lỗi nghiệp vụ thành lỗi GraphQL đã dịch theo ngôn ngữ của request
+func newError(ctx context.Context, err error) error {
+	appErr, ok := apperror.As(err)
+	if !ok {
+		appErr = apperror.Internal(nil, err)
+	}
+	if appErr.Kind == apperror.KindInternal {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "Unhandled error: "+err.Error(), nil)
+	}
+
+	localizer := utils.LocalizerFrom(ctx)
+	code := utils.ErrorCode(appErr)
+	e := &Error{
+		message:    utils.LoadI18nMessage(localizer, code, nil),
+		extensions: map[string]any{"code": code.ID},
+	}
+	if len(appErr.Fields) > 0 {
+		// Sắp xếp theo tên field để kết quả ổn định
+		names := make([]string, 0, len(appErr.Fields))
+		for field := range appErr.Fields {
+			names = append(names, field)
+		}
+		sort.Strings(names)
+		fields := make([]FieldError, 0, len(names))
+		for _, field := range names {
+			fields = append(fields, FieldError{
+				Field:  field,
+				Code:   appErr.Fields[field].ID,
+				Detail: utils.LoadI18nMessage(localizer, appErr.Fields[field], nil),
+			})
+		}
+		e.extensions["fields"] = fields
+	}
+	return e
+}
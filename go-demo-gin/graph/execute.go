@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+	"go-demo-gin/utils"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// Request: body của POST /graphql
+type Request struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Execute parse + validate request, kiểm tra Limits rồi mới thực thi resolver
+func Execute(ctx context.Context, schema graphql.Schema, req Request, limits Limits) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if vr := graphql.ValidateDocument(&schema, doc, nil); !vr.IsValid {
+		return &graphql.Result{Errors: vr.Errors}
+	}
+
+	depth, complexity := Measure(doc, req.OperationName, req.Variables)
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return limitResult(ctx, utils.QUERY_TOO_DEEP, depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return limitResult(ctx, utils.QUERY_TOO_COMPLEX, complexity, limits.MaxComplexity)
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// IsMutation: operation được chọn (operationName rỗng => mọi operation) có mutation không.
+// Query sai cú pháp => false (Execute sẽ trả lỗi).
+func IsMutation(req Request) bool {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if req.OperationName != "" && (op.Name == nil || op.Name.Value != req.OperationName) {
+			continue
+		}
+		if op.Operation == ast.OperationTypeMutation {
+			return true
+		}
+	}
+	return false
+}
+
+func limitResult(ctx context.Context, code *i18n.Message, actual, limit int) *graphql.Result {
+	data := map[string]any{"Actual": actual, "Max": limit}
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+		Message:    utils.LoadI18nMessage(utils.LocalizerFrom(ctx), code, data),
+		Extensions: map[string]any{"code": code.ID, "actual": actual, "max": limit},
+	}}}
+}
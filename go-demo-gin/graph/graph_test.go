@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Schema với UserService thật (sqlite in-memory), seed sẵn admin và customer
+func setupSchema(t *testing.T) (graphql.Schema, map[models.Role]*models.User) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // mỗi connection :memory: là một DB riêng
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.PasswordHistory{}))
+
+	users := map[models.Role]*models.User{}
+	for _, role := range []models.Role{models.RoleAdmin, models.RoleCustomer} {
+		u := &models.User{Username: string(role), Password: "x", Role: role}
+		require.NoError(t, db.Create(u).Error)
+		users[role] = u
+	}
+
+	svc := services.NewUserService(db, repo.NewGormUserRepo(db), repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db))
+	schema, err := NewSchema(utils.NewValidator(db), svc)
+	require.NoError(t, err)
+	return schema, users
+}
+
+// Chạy query và trả về kết quả dạng JSON (giống response HTTP)
+func run(t *testing.T, schema graphql.Schema, user *models.User, query string, vars map[string]any) map[string]any {
+	t.Helper()
+	ctx := context.Background()
+	if user != nil {
+		ctx = utils.WithInformation(ctx, user)
+	}
+	result := Execute(ctx, schema, Request{Query: query, Variables: vars}, Limits{MaxDepth: 5, MaxComplexity: 1000})
+	raw, err := json.Marshal(result)
+	require.NoError(t, err)
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
+// extensions.code của lỗi đầu tiên
+func firstErrorCode(out map[string]any) string {
+	errs, _ := out["errors"].([]any)
+	if len(errs) == 0 {
+		return ""
+	}
+	ext, _ := errs[0].(map[string]any)["extensions"].(map[string]any)
+	code, _ := ext["code"].(string)
+	return code
+}
+
+func TestQuery_ListAndDetailInOneRequest(t *testing.T) {
+	schema, users := setupSchema(t)
+
+	out := run(t, schema, users[models.RoleCustomer], `{
+		users(page: {limit: 1}, sort: "id asc") { nodes { id username } pageInfo { totalRows hasNextPage } }
+		user(id: "1") { username role createdAt }
+		me { username }
+	}`, nil)
+
+	require.Nil(t, out["errors"])
+	data := out["data"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"nodes":    []any{map[string]any{"id": "1", "username": "admin"}}, // chỉ có field được chọn
+		"pageInfo": map[string]any{"totalRows": float64(2), "hasNextPage": true},
+	}, data["users"])
+	assert.Equal(t, "admin", data["user"].(map[string]any)["username"])
+	assert.NotEmpty(t, data["user"].(map[string]any)["createdAt"])
+	assert.Equal(t, "customer", data["me"].(map[string]any)["username"])
+
+	// Không tìm thấy => null
+	out = run(t, schema, users[models.RoleAdmin], `{ user(id: "99") { id } }`, nil)
+	assert.Nil(t, out["errors"])
+	assert.Nil(t, out["data"].(map[string]any)["user"])
+}
+
+// Super admin có mọi quyền của admin, giống RequireRoles của REST và interceptor gRPC
+func TestQuery_SuperAdminAllowed(t *testing.T) {
+	schema, _ := setupSchema(t)
+	root := &models.User{Username: "root", Role: models.RoleSuperAdmin}
+
+	out := run(t, schema, root, `{ users { pageInfo { totalRows } } }`, nil)
+	require.Nil(t, out["errors"])
+	users := out["data"].(map[string]any)["users"].(map[string]any)
+	assert.Equal(t, float64(2), users["pageInfo"].(map[string]any)["totalRows"])
+}
+
+func TestMutation_RolesAndValidation(t *testing.T) {
+	schema, users := setupSchema(t)
+	create := `mutation($in: CreateUserInput!) { createUser(input: $in) { id username role birthday } }`
+	input := map[string]any{"in": map[string]any{
+		"username": "gqluser", "password": "secret.123", "role": "staff", "birthday": "2000-01-02",
+	}}
+
+	// Chưa đăng nhập / customer không được tạo user (giống RequireRoles(ADMIN, STAFF))
+	assert.Equal(t, utils.AUTHEN_REQUIRE.ID, firstErrorCode(run(t, schema, nil, create, input)))
+	assert.Equal(t, utils.PERMISSION_REQUIRE.ID, firstErrorCode(run(t, schema, users[models.RoleCustomer], create, input)))
+
+	out := run(t, schema, users[models.RoleAdmin], create, input)
+	require.Nil(t, out["errors"])
+	created := out["data"].(map[string]any)["createUser"].(map[string]any)
+	assert.Equal(t, "gqluser", created["username"])
+	assert.Equal(t, "2000-01-02", created["birthday"])
+
+	// Trùng username => lỗi validation kèm lỗi theo field
+	out = run(t, schema, users[models.RoleAdmin], create, input)
+	assert.Equal(t, utils.VALIDATION_FAILED.ID, firstErrorCode(out))
+	ext := out["errors"].([]any)[0].(map[string]any)["extensions"].(map[string]any)
+	assert.Equal(t, utils.DUPLICATE_USERNAME.ID, ext["fields"].([]any)[0].(map[string]any)["code"])
+
+	id := created["id"].(string)
+	out = run(t, schema, users[models.RoleCustomer],
+		`mutation($id: ID!) { updateUser(id: $id, input: {role: "customer", birthday: "2000-01-02"}) { role } }`,
+		map[string]any{"id": id})
+	require.Nil(t, out["errors"])
+	assert.Equal(t, "customer", out["data"].(map[string]any)["updateUser"].(map[string]any)["role"])
+
+	deleteQ := `mutation($id: ID!) { deleteUser(id: $id) }`
+	assert.Equal(t, utils.PERMISSION_REQUIRE.ID, firstErrorCode(run(t, schema, users[models.RoleCustomer], deleteQ, map[string]any{"id": id})))
+	out = run(t, schema, users[models.RoleAdmin], deleteQ, map[string]any{"id": id})
+	require.Nil(t, out["errors"])
+	assert.Equal(t, true, out["data"].(map[string]any)["deleteUser"])
+	assert.Equal(t, utils.NOT_FOUND.ID, firstErrorCode(run(t, schema, users[models.RoleAdmin], deleteQ, map[string]any{"id": id})))
+}
+
+func TestExecute_Limits(t *testing.T) {
+	schema, users := setupSchema(t)
+
+	// Độ phức tạp: 1 + (1 + 7 field) * 100 + ... > 1000
+	out := run(t, schema, users[models.RoleAdmin], `query($p: PageInput) {
+		users(page: $p) { nodes { id username fullName role birthday createdAt updatedAt } pageInfo { page limit } }
+	}`, map[string]any{"p": map[string]any{"limit": 100}})
+	assert.Equal(t, utils.QUERY_TOO_COMPLEX.ID, firstErrorCode(out))
+	assert.Nil(t, out["data"])
+
+	// Cùng query với limit mặc định thì được phép
+	out = run(t, schema, users[models.RoleAdmin], `{ users { nodes { id username } } }`, nil)
+	assert.Nil(t, out["errors"])
+}
+
+func TestMeasure(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: `
+		query A($p: PageInput) { users(page: $p) { ...Conn } me { id } }
+		query B { users(page: {limit: 2}) { nodes { id } } }
+		fragment Conn on UserConnection { nodes { id username } pageInfo { page } }
+	`})
+	require.NoError(t, err)
+
+	depth, complexity := Measure(doc, "A", map[string]any{"p": map[string]any{"limit": float64(5)}})
+	assert.Equal(t, 3, depth)
+	assert.Equal(t, 1+(3+2)*5+2, complexity) // users + (nodes, id, username, pageInfo, page) * 5 + me, id
+
+	depth, complexity = Measure(doc, "B", nil)
+	assert.Equal(t, 3, depth)
+	assert.Equal(t, 1+2*2, complexity)
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits: giới hạn độ sâu và độ phức tạp của 1 operation (chặn query lồng nhau/tốn kém)
+type Limits struct {
+	MaxDepth      int // số cấp selection lồng nhau tối đa
+	MaxComplexity int // tổng chi phí tối đa: mỗi field = 1, field phân trang nhân theo page.limit
+}
+
+// Giới hạn page.limit giống pkg.Pagination
+const maxPageLimit = 100
+
+// measurer tính độ sâu/chi phí của selection set (fragment được mở rộng tại chỗ)
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+}
+
+// Measure trả về độ sâu và độ phức tạp của operation được chọn (operationName rỗng => mọi operation)
+func Measure(doc *ast.Document, operationName string, variables map[string]any) (depth, complexity int) {
+	m := measurer{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	for _, def := range doc.Definitions {
+		if frag, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[frag.Name.Value] = frag
+		}
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (op.Name == nil || op.Name.Value != operationName) {
+			continue
+		}
+		d, c := m.selectionSet(op.SelectionSet, map[string]bool{})
+		depth = max(depth, d)
+		complexity = max(complexity, c)
+	}
+	return depth, complexity
+}
+
+// visiting: fragment đang mở rộng trên nhánh hiện tại (chống vòng lặp)
+func (m measurer) selectionSet(set *ast.SelectionSet, visiting map[string]bool) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, c int
+		switch s := sel.(type) {
+		case *ast.Field:
+			// Introspection (__schema, __type...) của playground không tính vào giới hạn
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			childDepth, childCost := m.selectionSet(s.SelectionSet, visiting)
+			d = childDepth + 1
+			c = 1 + childCost*m.multiplier(s)
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			frag, ok := m.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = m.selectionSet(frag.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+		depth = max(depth, d)
+		cost += c
+	}
+	return depth, cost
+}
+
+// Field có đối số page (danh sách phân trang) => chi phí field con nhân với số phần tử tối đa
+func (m measurer) multiplier(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "page" {
+			continue
+		}
+		limit := defaultPageLimit
+		switch v := arg.Value.(type) {
+		case *ast.ObjectValue:
+			for _, field := range v.Fields {
+				if field.Name.Value == "limit" {
+					limit = m.intValue(field.Value, limit)
+				}
+			}
+		case *ast.Variable:
+			if page, ok := m.variables[v.Name.Value].(map[string]any); ok {
+				limit = toInt(page["limit"], limit)
+			}
+		}
+		return min(max(limit, 1), maxPageLimit)
+	}
+	return 1
+}
+
+func (m measurer) intValue(v ast.Value, def int) int {
+	switch v := v.(type) {
+	case *ast.IntValue:
+		if n, err := strconv.Atoi(v.Value); err == nil {
+			return n
+		}
+	case *ast.Variable:
+		return toInt(m.variables[v.Name.Value], def)
+	}
+	return def
+}
+
+// Biến từ JSON được decode thành float64
+func toInt(v any, def int) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return def
+}
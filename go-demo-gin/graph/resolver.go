@@ -0,0 +1,255 @@
+package graph
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	userRequest "go-demo-gin/requests/user"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultPageLimit = 10
+
+// Phân quyền theo field, giống RequireRoles trên các route REST tương ứng
+var (
+	readRoles   = []models.Role{models.RoleAdmin, models.RoleStaff, models.RoleCustomer}
+	createRoles = []models.Role{models.RoleAdmin, models.RoleStaff}
+	updateRoles = []models.Role{models.RoleAdmin, models.RoleStaff, models.RoleCustomer}
+	deleteRoles = []models.Role{models.RoleAdmin, models.RoleStaff}
+)
+
+// userNode: kết quả trả về cho type User (default resolver đọc theo tag json)
+type userNode struct {
+	ID        string     `json:"id"`
+	Username  string     `json:"username"`
+	FullName  string     `json:"fullName"`
+	Role      string     `json:"role"`
+	Birthday  *string    `json:"birthday"`
+	Status    string     `json:"status"`
+	CreatedAt *time.Time `json:"createdAt"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+type pageInfo struct {
+	Page        int   `json:"page"`
+	Limit       int   `json:"limit"`
+	TotalRows   int64 `json:"totalRows"`
+	TotalPages  int   `json:"totalPages"`
+	HasNextPage bool  `json:"hasNextPage"`
+}
+
+type userConnection struct {
+	Nodes    []userNode `json:"nodes"`
+	PageInfo pageInfo   `json:"pageInfo"`
+}
+
+type resolver struct {
+	v   *utils.Validator
+	svc UserService
+}
+
+// Kiểm tra role của user đã xác thực (middleware Authentication gắn vào context)
+// và scope nếu request dùng API key (giống RequireScope của REST)
+func requireRoles(ctx context.Context, roles []models.Role, scope string) error {
+	user := utils.InformationFrom(ctx)
+	if user == nil {
+		return apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	if !user.Role.In(roles) {
+		return apperror.Forbidden(utils.PERMISSION_REQUIRE, nil)
+	}
+	if key := utils.APIKeyFrom(ctx); key != nil && !key.HasScope(scope) {
+		return apperror.Forbidden(utils.INSUFFICIENT_SCOPE, nil)
+	}
+	return nil
+}
+
+func (r *resolver) users(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the users resolver", nil)
+	if err := requireRoles(ctx, readRoles, models.ScopeUsersRead); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	var pag pkg.Pagination
+	if page, ok := p.Args["page"].(map[string]any); ok {
+		pag.Page, _ = page["page"].(int)
+		pag.Limit, _ = page["limit"].(int)
+	}
+	pag.Sort, _ = p.Args["sort"].(string)
+	var search string
+	if filter, ok := p.Args["filter"].(map[string]any); ok {
+		search, _ = filter["search"].(string)
+	}
+
+	result, err := r.svc.GetUserList(ctx, &pag, search)
+	if err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	conn := userConnection{
+		Nodes: []userNode{},
+		PageInfo: pageInfo{
+			Page:        result.Page,
+			Limit:       result.Limit,
+			TotalRows:   result.TotalRows,
+			TotalPages:  result.TotalPages,
+			HasNextPage: result.Page < result.TotalPages,
+		},
+	}
+	list, _ := result.Result.([]userResponse.UserList)
+	for _, u := range list {
+		conn.Nodes = append(conn.Nodes, userNode{
+			ID:       strconv.FormatUint(uint64(u.ID), 10),
+			Username: u.Username,
+			FullName: u.Name,
+			Role:     u.Role,
+			Birthday: formatBirthday(u.Birthday),
+			Status:   u.Status,
+		})
+	}
+	return conn, nil
+}
+
+func (r *resolver) user(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the user resolver", nil)
+	if err := requireRoles(ctx, readRoles, models.ScopeUsersRead); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	id, _ := p.Args["id"].(string)
+	detail, err := r.svc.GetUserById(ctx, id)
+	if err != nil {
+		// Không tìm thấy => null (không phải lỗi), giống cách GraphQL thường trả về
+		if apperror.KindOf(err) == apperror.KindNotFound {
+			return nil, nil
+		}
+		return nil, newError(ctx, err)
+	}
+	return nodeFromDetail(detail), nil
+}
+
+func (r *resolver) me(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the me resolver", nil)
+	if err := requireRoles(ctx, readRoles, models.ScopeUsersRead); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	detail, err := r.svc.GetUserById(ctx, strconv.FormatUint(uint64(utils.InformationFrom(ctx).ID), 10))
+	if err != nil {
+		return nil, newError(ctx, err)
+	}
+	return nodeFromDetail(detail), nil
+}
+
+func (r *resolver) createUser(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create user resolver", nil)
+	if err := requireRoles(ctx, createRoles, models.ScopeUsersWrite); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	input, _ := p.Args["input"].(map[string]any)
+	create := userRequest.UserCreate{
+		Username: stringArg(input, "username"),
+		Pass:     stringArg(input, "password"),
+		Name:     stringArg(input, "fullName"),
+		Role:     stringArg(input, "role"),
+		Date:     stringArg(input, "birthday"),
+	}
+
+	// Validation (cùng rule với REST)
+	if fields := r.v.ValidateStructCtx(ctx, create); fields != nil {
+		return nil, newError(ctx, apperror.Validation(utils.VALIDATION_FAILED, fields))
+	}
+
+	detail, err := r.svc.CreateUser(ctx, &create)
+	if err != nil {
+		return nil, newError(ctx, err)
+	}
+	return nodeFromDetail(detail), nil
+}
+
+func (r *resolver) updateUser(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update user resolver", nil)
+	if err := requireRoles(ctx, updateRoles, models.ScopeUsersWrite); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	id, _ := p.Args["id"].(string)
+	input, _ := p.Args["input"].(map[string]any)
+	update := userRequest.UserUpdate{
+		Pass: stringArg(input, "password"),
+		Name: stringArg(input, "fullName"),
+		Role: stringArg(input, "role"),
+		Date: stringArg(input, "birthday"),
+	}
+
+	// Validation (cùng rule với REST)
+	if fields := r.v.ValidateStructCtx(utils.WithUpdateID(ctx, id), update); fields != nil {
+		return nil, newError(ctx, apperror.Validation(utils.VALIDATION_FAILED, fields))
+	}
+
+	detail, err := r.svc.UpdateUser(ctx, &update, id)
+	if err != nil {
+		return nil, newError(ctx, err)
+	}
+	return nodeFromDetail(detail), nil
+}
+
+func (r *resolver) deleteUser(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete user resolver", nil)
+	if err := requireRoles(ctx, deleteRoles, models.ScopeUsersWrite); err != nil {
+		return nil, newError(ctx, err)
+	}
+
+	id, _ := p.Args["id"].(string)
+	if err := r.svc.DeleteUser(ctx, id); err != nil {
+		return nil, newError(ctx, err)
+	}
+	return true, nil
+}
+
+func stringArg(args map[string]any, key string) string {
+	s, _ := args[key].(string)
+	return s
+}
+
+func nodeFromDetail(d *userResponse.UserDetail) userNode {
+	return userNode{
+		ID:        strconv.FormatUint(uint64(d.ID), 10),
+		Username:  d.Username,
+		FullName:  d.Name,
+		Role:      d.Role,
+		Birthday:  formatBirthday(d.Birthday),
+		Status:    d.Status,
+		CreatedAt: &d.CreatedAt,
+		UpdatedAt: &d.UpdatedAt,
+	}
+}
+
+// Birthday dạng YYYY-MM-DD (giống định dạng khi tạo/sửa), null nếu chưa có
+func formatBirthday(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
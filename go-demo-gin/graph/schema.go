@@ -0,0 +1,151 @@
+// Package graph: schema GraphQL cho users, dùng chung tầng service với REST và gRPC.
+package graph
+
+import (
+	"context"
+	"go-demo-gin/pkg"
+	userRequest "go-demo-gin/requests/user"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+
+	"github.com/graphql-go/graphql"
+)
+
+// UserService: cùng nghiệp vụ với controllers.UserService (services.UserService)
+type UserService interface {
+	CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, error)
+	GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, error)
+	GetUserById(ctx context.Context, id string) (*userResponse.UserDetail, error)
+	UpdateUser(ctx context.Context, in *userRequest.UserUpdate, id string) (*userResponse.UserDetail, error)
+	DeleteUser(ctx context.Context, id string) error
+}
+
+var userType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "User",
+	Fields: graphql.Fields{
+		"id":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"username":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"fullName":  &graphql.Field{Type: graphql.String},
+		"role":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"birthday":  &graphql.Field{Type: graphql.String, Description: "YYYY-MM-DD"},
+		"status":    &graphql.Field{Type: graphql.String, Description: "pending, active, disabled, locked"},
+		"createdAt": &graphql.Field{Type: graphql.DateTime, Description: "Chỉ có khi lấy chi tiết (user, me, mutation)"},
+		"updatedAt": &graphql.Field{Type: graphql.DateTime, Description: "Chỉ có khi lấy chi tiết (user, me, mutation)"},
+	},
+})
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"page":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"limit":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"totalRows":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"totalPages":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+	},
+})
+
+var userConnectionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UserConnection",
+	Fields: graphql.Fields{
+		"nodes":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userType)))},
+		"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+	},
+})
+
+var userFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UserFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"search": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+var pageInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "PageInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"page":  &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 1},
+		"limit": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: defaultPageLimit},
+	},
+})
+
+var createUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CreateUserInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"username": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"password": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"fullName": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"role":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"birthday": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+var updateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "UpdateUserInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"password": &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Bỏ trống => giữ nguyên password cũ"},
+		"fullName": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"role":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"birthday": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+// NewSchema tạo schema GraphQL; resolver gọi UserService và kiểm tra role giống RequireRoles của REST
+func NewSchema(v *utils.Validator, svc UserService) (graphql.Schema, error) {
+	r := &resolver{v: v, svc: svc}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(userConnectionType),
+				Args: graphql.FieldConfigArgument{
+					"filter": &graphql.ArgumentConfig{Type: userFilterInput},
+					"page":   &graphql.ArgumentConfig{Type: pageInput},
+					"sort":   &graphql.ArgumentConfig{Type: graphql.String, Description: "Ví dụ: \"id desc\""},
+				},
+				Resolve: r.users,
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.user,
+			},
+			"me": &graphql.Field{
+				Type:    graphql.NewNonNull(userType),
+				Resolve: r.me,
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createUser": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createUserInput)},
+				},
+				Resolve: r.createUser,
+			},
+			"updateUser": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{
+					"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateUserInput)},
+				},
+				Resolve: r.updateUser,
+			},
+			"deleteUser": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: r.deleteUser,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+	authv1 "go-demo-gin/pb/auth/v1"
+	authenRequest "go-demo-gin/requests/authen"
+	"go-demo-gin/utils"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+// AuthService: cùng nghiệp vụ với controllers.AuthService (services.AuthService)
+type AuthService interface {
+	Authenticate(ctx context.Context, in *authenRequest.LoginForm) (*string, error)
+	Refresh(ctx context.Context, token string) (*string, error)
+}
+
+type AuthServer struct {
+	authv1.UnimplementedAuthServiceServer
+	svc AuthService
+}
+
+func NewAuthServer(svc AuthService) *AuthServer {
+	return &AuthServer{svc: svc}
+}
+
+func (s *AuthServer) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.TokenResponse, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the login rpc", nil)
+
+	// Tương đương binding:"required" của LoginForm bên HTTP
+	fields := map[string]*i18n.Message{}
+	if req.GetUsername() == "" {
+		fields["username"] = utils.USERNAME_REQUIRE
+	}
+	if req.GetPassword() == "" {
+		fields["password"] = utils.PASSWORD_REQUIRE
+	}
+	if len(fields) > 0 {
+		return nil, validationError(fields)
+	}
+
+	token, err := s.svc.Authenticate(ctx, &authenRequest.LoginForm{Username: req.GetUsername(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.TokenResponse{Token: *token}, nil
+}
+
+func (s *AuthServer) Refresh(ctx context.Context, req *authv1.RefreshRequest) (*authv1.TokenResponse, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the refresh token rpc", nil)
+
+	token, err := s.svc.Refresh(ctx, req.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	return &authv1.TokenResponse{Token: *token}, nil
+}
@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/utils"
+	"sort"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain trong ErrorInfo: client dùng Reason (mã lỗi ổn định, giống "code" của problem+json)
+const errorDomain = "go-demo-gin"
+
+// Loại lỗi nghiệp vụ => gRPC status code (tương ứng kindStatus của HTTP)
+var kindCode = map[apperror.Kind]codes.Code{
+	apperror.KindNotFound:        codes.NotFound,
+	apperror.KindConflict:        codes.AlreadyExists,
+	apperror.KindValidation:      codes.InvalidArgument,
+	apperror.KindUnauthorized:    codes.Unauthenticated,
+	apperror.KindForbidden:       codes.PermissionDenied,
+	apperror.KindTooManyRequests: codes.ResourceExhausted,
+	apperror.KindInternal:        codes.Internal,
+}
+
+// toStatus chuyển lỗi nghiệp vụ sang gRPC status: message dịch theo ngôn ngữ của request,
+// details gồm ErrorInfo (mã lỗi) và BadRequest (lỗi theo field).
+func toStatus(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err // đã là gRPC status (lỗi của grpc-go, context...)
+	}
+	appErr, ok := apperror.As(err)
+	if !ok {
+		appErr = apperror.Internal(nil, err)
+	}
+
+	localizer := utils.LocalizerFrom(ctx)
+	code := utils.ErrorCode(appErr)
+	st := status.New(kindCode[appErr.Kind], utils.LoadI18nMessage(localizer, code, nil))
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: code.ID, Domain: errorDomain}}
+	if len(appErr.Fields) > 0 {
+		// Sắp xếp theo tên field để kết quả ổn định
+		fields := make([]string, 0, len(appErr.Fields))
+		for field := range appErr.Fields {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		br := &errdetails.BadRequest{}
+		for _, field := range fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Reason:      appErr.Fields[field].ID,
+				Description: utils.LoadI18nMessage(localizer, appErr.Fields[field], nil),
+			})
+		}
+		details = append(details, br)
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func validationError(fields map[string]*i18n.Message) error {
+	return apperror.Validation(utils.VALIDATION_FAILED, fields)
+}
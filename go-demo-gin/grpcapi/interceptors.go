@@ -0,0 +1,185 @@
+package grpcapi
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"go-demo-gin/apperror"
+	"go-demo-gin/initializers"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Số panic đã recover trong gRPC handler, xem qua expvar ("grpc_panics_total")
+var panicsTotal = expvar.NewInt("grpc_panics_total")
+
+// Lấy giá trị đầu tiên của key trong metadata của request
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// LoggingInterceptor: giống AccessLogger của HTTP - gắn logger có request ID (metadata "x-request-id",
+// không có thì tự sinh) và thông tin request vào context, ghi log khi RPC kết thúc.
+func LoggingInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := metadataValue(ctx, "x-request-id")
+		if strings.TrimSpace(id) == "" {
+			id = strconv.FormatInt(time.Now().UnixMilli(), 10)
+		}
+		// Trả request ID về cho client qua header
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id))
+
+		entry := logrus.WithFields(logrus.Fields{
+			"id":     id,
+			"source": "service",
+		})
+		ctx = utils.WithLogger(ctx, entry)
+		reqInfo := &utils.RequestInfo{ID: id, UserAgent: metadataValue(ctx, "user-agent")}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			reqInfo.IP = p.Addr.String()
+		}
+		ctx = utils.WithRequestInfo(ctx, reqInfo)
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		entry.WithFields(logrus.Fields{
+			"method":   info.FullMethod,
+			"code":     status.Code(err).String(),
+			"duration": time.Since(start).String(),
+		}).Info("gRPC request completed")
+		return resp, err
+	}
+}
+
+// I18nInterceptor: ngôn ngữ lấy từ metadata "lang" hoặc "accept-language" (giống query ?lang và header Accept-Language)
+func I18nInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		localizer := i18n.NewLocalizer(initializers.Bundle, metadataValue(ctx, "lang"), metadataValue(ctx, "accept-language"))
+		return handler(utils.WithLocalizer(ctx, localizer), req)
+	}
+}
+
+// ErrorInterceptor: điểm duy nhất chuyển lỗi nghiệp vụ sang gRPC status (tương tự ErrorHandler của HTTP)
+func ErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, isStatus := status.FromError(err); !isStatus && apperror.KindOf(err) == apperror.KindInternal {
+			utils.LogCtx(ctx, logrus.ErrorLevel, "Unhandled error: "+err.Error(), logrus.Fields{"method": info.FullMethod})
+		}
+		return nil, toStatus(ctx, err)
+	}
+}
+
+// RecoveryInterceptor bắt panic trong handler: ghi log kèm stack trace và trả lỗi Internal
+func RecoveryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			panicsTotal.Add(1)
+			utils.LogCtx(ctx, logrus.ErrorLevel, fmt.Sprintf("Panic recovered: %v", rec), logrus.Fields{
+				"method": info.FullMethod,
+				"stack":  string(debug.Stack()),
+			})
+			resp, err = nil, apperror.Internal(nil, fmt.Errorf("panic: %v", rec))
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// TokenVerifier xác minh JWT (kể cả session bị thu hồi) và trả về username, ID user, tenant (services.AuthService)
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, tokenStr string) (string, uint, uint, error)
+}
+
+// TenantResolver: tra cứu tenant theo slug (thường là services.TenantService)
+type TenantResolver interface {
+	Resolve(ctx context.Context, slug string) (*models.Tenant, error)
+}
+
+// TenantInterceptor: tenant lấy từ metadata "x-tenant" (giống header X-Tenant của HTTP), không có => tenant mặc định
+func TenantInterceptor(tenants TenantResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		slug := strings.ToLower(strings.TrimSpace(metadataValue(ctx, "x-tenant")))
+		t, err := tenants.Resolve(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		return handler(utils.WithTenant(ctx, utils.TenantScope{ID: t.ID, Requested: slug != ""}), req)
+	}
+}
+
+// UserFinder: nguồn tra cứu user theo username (thường là repo.CachedUserRepo)
+type UserFinder interface {
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+}
+
+// AuthInterceptor: xác thực bằng metadata "authorization: Bearer <token>" (cùng JWT với HTTP) và phân quyền
+// theo roles[FullMethod]. Method trong public bỏ qua xác thực; method không khai báo trong roles bị từ chối.
+func AuthInterceptor(tokens TokenVerifier, users UserFinder, roles map[string][]models.Role, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(public, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		// 1. Lấy token từ metadata
+		authHeader := metadataValue(ctx, "authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return nil, apperror.Unauthorized(utils.INVALID_AUTHOR_HEADER, nil)
+		}
+
+		// 2. Xác minh token
+		username, userID, tenantID, err := tokens.VerifyToken(ctx, strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			utils.LogCtx(ctx, logrus.InfoLevel, "Token rejected: "+err.Error(), nil)
+			return nil, err
+		}
+
+		// 3. Truy vấn thông tin user (trong tenant của token, không phải tenant của request) và phân quyền
+		user, err := users.FindByUsername(utils.WithTenant(ctx, utils.TenantScope{ID: tenantID}), username)
+		if err != nil {
+			return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, err)
+		}
+		if user.ID != userID {
+			return nil, apperror.Unauthorized(utils.INVALID_CLAIM, nil)
+		}
+		if code := utils.AccountStatusError(user.Status); code != nil {
+			return nil, apperror.Forbidden(code, nil)
+		}
+		ctx, code := utils.ScopeToUser(ctx, user)
+		if code != nil {
+			return nil, apperror.Forbidden(code, nil)
+		}
+		if !user.Role.In(roles[info.FullMethod]) {
+			return nil, apperror.Forbidden(utils.PERMISSION_REQUIRE, nil)
+		}
+
+		// Lưu thông tin user vào context
+		return handler(utils.WithInformation(ctx, user), req)
+	}
+}
@@ -0,0 +1,56 @@
+// Package grpcapi: API gRPC cho user và auth, chạy song song với HTTP và dùng chung tầng service.
+package grpcapi
+
+import (
+	"go-demo-gin/models"
+	authv1 "go-demo-gin/pb/auth/v1"
+	userv1 "go-demo-gin/pb/user/v1"
+	"go-demo-gin/utils"
+
+	"google.golang.org/grpc"
+)
+
+// Config: các dependency của gRPC server (thường lấy từ routes.Container)
+type Config struct {
+	Validator  *utils.Validator
+	Users      UserService
+	Auth       AuthService
+	Tokens     TokenVerifier
+	UserFinder UserFinder
+	Tenants    TenantResolver // nil => request chưa xác thực (login) không giới hạn theo tenant
+}
+
+// Phân quyền theo method, giống RequireRoles trên các route HTTP tương ứng
+var methodRoles = map[string][]models.Role{
+	userv1.UserService_CreateUser_FullMethodName: {models.RoleAdmin, models.RoleStaff},
+	userv1.UserService_ListUsers_FullMethodName:  {models.RoleAdmin, models.RoleStaff, models.RoleCustomer},
+	userv1.UserService_GetUser_FullMethodName:    {models.RoleAdmin, models.RoleStaff, models.RoleCustomer},
+	userv1.UserService_UpdateUser_FullMethodName: {models.RoleAdmin, models.RoleStaff, models.RoleCustomer},
+	userv1.UserService_DeleteUser_FullMethodName: {models.RoleAdmin, models.RoleStaff},
+}
+
+// Các method không cần token
+var publicMethods = []string{
+	authv1.AuthService_Login_FullMethodName,
+	authv1.AuthService_Refresh_FullMethodName,
+}
+
+// NewServer tạo gRPC server đã đăng ký UserService/AuthService.
+// Thứ tự interceptor giống middleware HTTP: logging → i18n → chuyển lỗi → recovery → tenant → xác thực.
+func NewServer(cfg Config, opts ...grpc.ServerOption) *grpc.Server {
+	interceptors := []grpc.UnaryServerInterceptor{
+		LoggingInterceptor(),
+		I18nInterceptor(),
+		ErrorInterceptor(),
+		RecoveryInterceptor(),
+	}
+	if cfg.Tenants != nil {
+		interceptors = append(interceptors, TenantInterceptor(cfg.Tenants))
+	}
+	interceptors = append(interceptors, AuthInterceptor(cfg.Tokens, cfg.UserFinder, methodRoles, publicMethods...))
+	opts = append(opts, grpc.ChainUnaryInterceptor(interceptors...))
+	srv := grpc.NewServer(opts...)
+	userv1.RegisterUserServiceServer(srv, NewUserServer(cfg.Validator, cfg.Users))
+	authv1.RegisterAuthServiceServer(srv, NewAuthServer(cfg.Auth))
+	return srv
+}
@@ -0,0 +1,249 @@
+package grpcapi
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/initializers"
+	"go-demo-gin/models"
+	authv1 "go-demo-gin/pb/auth/v1"
+	userv1 "go-demo-gin/pb/user/v1"
+	userRequest "go-demo-gin/requests/user"
+	"go-demo-gin/routes"
+	"go-demo-gin/utils"
+	"net"
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type testClients struct {
+	users userv1.UserServiceClient
+	auth  authv1.AuthServiceClient
+	c     *routes.Container
+	db    *gorm.DB
+}
+
+// Chạy gRPC server trên bufconn với service thật (sqlite in-memory), seed sẵn admin và customer
+func setupGRPC(t *testing.T) testClients {
+	t.Helper()
+	t.Setenv("SECRET", "test-secret")
+	require.NoError(t, initializers.LoadI18n())
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // mỗi connection :memory: là một DB riêng
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.PasswordHistory{}, &models.Session{}))
+	for _, u := range []struct {
+		name string
+		role models.Role
+	}{{"admin", models.RoleAdmin}, {"customer", models.RoleCustomer}} {
+		hash, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+		require.NoError(t, db.Create(&models.User{Username: u.name, Password: string(hash), Role: u.role}).Error)
+	}
+
+	c := routes.NewContainer(db)
+	srv := NewServer(Config{
+		Validator:  c.Validator,
+		Users:      c.UserSvc,
+		Auth:       c.AuthSvc,
+		Tokens:     c.AuthSvc,
+		UserFinder: c.UserRepo,
+	})
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return testClients{users: userv1.NewUserServiceClient(conn), auth: authv1.NewAuthServiceClient(conn), c: c, db: db}
+}
+
+// Đăng nhập và trả về context có metadata authorization
+func loginCtx(t *testing.T, cl testClients, username string) context.Context {
+	t.Helper()
+	resp, err := cl.auth.Login(context.Background(), &authv1.LoginRequest{Username: username, Password: "secret.123"})
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+resp.GetToken())
+}
+
+// Mã lỗi ổn định (ErrorInfo.Reason) trong details của status
+func errorReason(t *testing.T, err error) string {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func TestUserService_CRUD(t *testing.T) {
+	cl := setupGRPC(t)
+	ctx := loginCtx(t, cl, "admin")
+
+	created, err := cl.users.CreateUser(ctx, &userv1.CreateUserRequest{
+		Username: "grpcuser", Password: "secret.123", FullName: "gRPC User", Role: "staff", Birthday: "2000-01-02",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.GetId())
+	assert.Equal(t, "staff", created.GetRole())
+	assert.Equal(t, "2000-01-02", created.GetBirthday())
+
+	var header metadata.MD
+	got, err := cl.users.GetUser(metadata.AppendToOutgoingContext(ctx, "x-request-id", "rid-1"),
+		&userv1.GetUserRequest{Id: created.GetId()}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, "grpcuser", got.GetUsername())
+	assert.Equal(t, []string{"rid-1"}, header.Get("x-request-id"))
+
+	list, err := cl.users.ListUsers(ctx, &userv1.ListUsersRequest{Limit: 2, Page: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), list.GetTotalRows())
+	assert.Len(t, list.GetUsers(), 2)
+
+	updated, err := cl.users.UpdateUser(ctx, &userv1.UpdateUserRequest{Id: created.GetId(), Role: "customer", Birthday: "2000-01-02"})
+	require.NoError(t, err)
+	assert.Equal(t, "customer", updated.GetRole())
+
+	_, err = cl.users.DeleteUser(ctx, &userv1.DeleteUserRequest{Id: created.GetId()})
+	require.NoError(t, err)
+
+	_, err = cl.users.GetUser(ctx, &userv1.GetUserRequest{Id: created.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, utils.NOT_FOUND.ID, errorReason(t, err))
+}
+
+func TestUserService_ValidationLocalized(t *testing.T) {
+	cl := setupGRPC(t)
+	ctx := metadata.AppendToOutgoingContext(loginCtx(t, cl, "admin"), "lang", "vi")
+
+	_, err := cl.users.CreateUser(ctx, &userv1.CreateUserRequest{Username: "admin", Password: "x", Role: "root", Birthday: "2000-01-02"})
+	require.Error(t, err)
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	vi := i18n.NewLocalizer(initializers.Bundle, "vi")
+	assert.Equal(t, utils.LoadI18nMessage(vi, utils.VALIDATION_FAILED, nil), st.Message())
+
+	var violations map[string]string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			violations = map[string]string{}
+			for _, v := range br.GetFieldViolations() {
+				violations[v.GetField()] = v.GetReason()
+			}
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"username": utils.DUPLICATE_USERNAME.ID,
+		"password": utils.INVALID_PASSWORD.ID,
+		"role":     utils.INVALID_ROLE.ID,
+	}, violations)
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	cl := setupGRPC(t)
+	req := &userv1.GetUserRequest{Id: 1}
+
+	_, err := cl.users.GetUser(context.Background(), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, utils.INVALID_AUTHOR_HEADER.ID, errorReason(t, err))
+
+	bad := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not-a-jwt")
+	_, err = cl.users.GetUser(bad, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, utils.INVALID_TOKEN.ID, errorReason(t, err))
+
+	// Customer được xem nhưng không được xoá (giống route HTTP)
+	customer := loginCtx(t, cl, "customer")
+	_, err = cl.users.GetUser(customer, req)
+	assert.NoError(t, err)
+	_, err = cl.users.DeleteUser(customer, &userv1.DeleteUserRequest{Id: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAuthService_LoginAndRefresh(t *testing.T) {
+	cl := setupGRPC(t)
+	ctx := context.Background()
+
+	_, err := cl.auth.Login(ctx, &authv1.LoginRequest{Username: "admin", Password: "wrong"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, utils.INVALID_USERNAME_PASSWORD.ID, errorReason(t, err))
+
+	_, err = cl.auth.Login(ctx, &authv1.LoginRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	login, err := cl.auth.Login(ctx, &authv1.LoginRequest{Username: "admin", Password: "secret.123"})
+	require.NoError(t, err)
+	refreshed, err := cl.auth.Refresh(ctx, &authv1.RefreshRequest{Token: login.GetToken()})
+	require.NoError(t, err)
+	assert.NotEmpty(t, refreshed.GetToken())
+
+	_, err = cl.auth.Refresh(ctx, &authv1.RefreshRequest{Token: "not-a-jwt"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	before := panicsTotal.Value()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.v1/Panic"}
+	_, err := RecoveryInterceptor()(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		var m map[string]int
+		m["boom"] = 1 // panic: assignment to entry in nil map
+		return nil, nil
+	})
+
+	assert.Equal(t, apperror.KindInternal, apperror.KindOf(err))
+	assert.Equal(t, before+1, panicsTotal.Value())
+	assert.Equal(t, codes.Internal, status.Code(toStatus(context.Background(), err)))
+}
+
+func TestAuthInterceptor_DisabledUserRejectedImmediately(t *testing.T) {
+	cl := setupGRPC(t)
+	ctx := loginCtx(t, cl, "customer")
+	_, err := cl.users.GetUser(ctx, &userv1.GetUserRequest{Id: 2}) // user được cache sau lần gọi này
+	require.NoError(t, err)
+
+	_, err = cl.c.UserSvc.BatchUsers(context.Background(), []userRequest.UserBatchOperation{{Op: userRequest.StatusDisable, ID: 2, Reason: "Spam"}}, true)
+	require.NoError(t, err)
+
+	// Token cũ vẫn còn hạn nhưng bị từ chối; đăng nhập lại cũng không được
+	_, err = cl.users.GetUser(ctx, &userv1.GetUserRequest{Id: 2})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, utils.ACCOUNT_DISABLED.ID, errorReason(t, err))
+	_, err = cl.auth.Login(context.Background(), &authv1.LoginRequest{Username: "customer", Password: "secret.123"})
+	assert.Equal(t, utils.ACCOUNT_DISABLED.ID, errorReason(t, err))
+}
+
+func TestAuthService_LoginRejectedPerStatus(t *testing.T) {
+	for st, code := range map[models.UserStatus]string{
+		models.UserPending:  utils.ACCOUNT_PENDING.ID,
+		models.UserDisabled: utils.ACCOUNT_DISABLED.ID,
+		models.UserLocked:   utils.ACCOUNT_LOCKED.ID,
+	} {
+		t.Run(string(st), func(t *testing.T) {
+			cl := setupGRPC(t) // DB mới mỗi case, tránh user đã được cache
+			require.NoError(t, cl.db.Model(&models.User{}).Where("username = ?", "customer").Update("status", st).Error)
+			_, err := cl.auth.Login(context.Background(), &authv1.LoginRequest{Username: "customer", Password: "secret.123"})
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+			assert.Equal(t, code, errorReason(t, err))
+		})
+	}
+}
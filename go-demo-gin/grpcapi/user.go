@@ -0,0 +1,153 @@
+package grpcapi
+
+import (
+	"context"
+	userv1 "go-demo-gin/pb/user/v1"
+	"go-demo-gin/pkg"
+	userRequest "go-demo-gin/requests/user"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UserService: cùng nghiệp vụ với controllers.UserService (services.UserService)
+type UserService interface {
+	CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, error)
+	GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, error)
+	GetUserById(ctx context.Context, id string) (*userResponse.UserDetail, error)
+	UpdateUser(ctx context.Context, in *userRequest.UserUpdate, id string) (*userResponse.UserDetail, error)
+	DeleteUser(ctx context.Context, id string) error
+}
+
+type UserServer struct {
+	userv1.UnimplementedUserServiceServer
+	v   *utils.Validator
+	svc UserService
+}
+
+func NewUserServer(v *utils.Validator, svc UserService) *UserServer {
+	return &UserServer{v: v, svc: svc}
+}
+
+func (s *UserServer) CreateUser(ctx context.Context, req *userv1.CreateUserRequest) (*userv1.User, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create user rpc", nil)
+
+	create := userRequest.UserCreate{
+		Username: req.GetUsername(),
+		Pass:     req.GetPassword(),
+		Name:     req.GetFullName(),
+		Role:     req.GetRole(),
+		Date:     req.GetBirthday(),
+	}
+
+	// Validation (cùng rule với HTTP)
+	if fields := s.v.ValidateStructCtx(ctx, create); fields != nil {
+		return nil, validationError(fields)
+	}
+
+	detail, err := s.svc.CreateUser(ctx, &create)
+	if err != nil {
+		return nil, err
+	}
+	return userFromDetail(detail), nil
+}
+
+func (s *UserServer) GetUser(ctx context.Context, req *userv1.GetUserRequest) (*userv1.User, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get user by id rpc", nil)
+
+	detail, err := s.svc.GetUserById(ctx, strconv.FormatUint(req.GetId(), 10))
+	if err != nil {
+		return nil, err
+	}
+	return userFromDetail(detail), nil
+}
+
+func (s *UserServer) ListUsers(ctx context.Context, req *userv1.ListUsersRequest) (*userv1.ListUsersResponse, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of users rpc", nil)
+
+	pag := pkg.Pagination{Limit: int(req.GetLimit()), Page: int(req.GetPage()), Sort: req.GetSort()}
+	result, err := s.svc.GetUserList(ctx, &pag, req.GetSearch())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &userv1.ListUsersResponse{
+		Limit:      int32(result.Limit),
+		Page:       int32(result.Page),
+		TotalRows:  result.TotalRows,
+		TotalPages: int32(result.TotalPages),
+	}
+	list, _ := result.Result.([]userResponse.UserList)
+	for _, u := range list {
+		resp.Users = append(resp.Users, &userv1.User{
+			Id:       uint64(u.ID),
+			Username: u.Username,
+			FullName: u.Name,
+			Role:     u.Role,
+			Birthday: formatBirthday(u.Birthday),
+		})
+	}
+	return resp, nil
+}
+
+func (s *UserServer) UpdateUser(ctx context.Context, req *userv1.UpdateUserRequest) (*userv1.User, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update user rpc", nil)
+
+	id := strconv.FormatUint(req.GetId(), 10)
+	update := userRequest.UserUpdate{
+		Pass: req.GetPassword(),
+		Name: req.GetFullName(),
+		Role: req.GetRole(),
+		Date: req.GetBirthday(),
+	}
+
+	// Validation (cùng rule với HTTP)
+	if fields := s.v.ValidateStructCtx(utils.WithUpdateID(ctx, id), update); fields != nil {
+		return nil, validationError(fields)
+	}
+
+	detail, err := s.svc.UpdateUser(ctx, &update, id)
+	if err != nil {
+		return nil, err
+	}
+	return userFromDetail(detail), nil
+}
+
+func (s *UserServer) DeleteUser(ctx context.Context, req *userv1.DeleteUserRequest) (*emptypb.Empty, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete user rpc", nil)
+
+	if err := s.svc.DeleteUser(ctx, strconv.FormatUint(req.GetId(), 10)); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func userFromDetail(d *userResponse.UserDetail) *userv1.User {
+	return &userv1.User{
+		Id:        uint64(d.ID),
+		Username:  d.Username,
+		FullName:  d.Name,
+		Role:      d.Role,
+		Birthday:  formatBirthday(d.Birthday),
+		CreatedAt: timestamppb.New(d.CreatedAt),
+		UpdatedAt: timestamppb.New(d.UpdatedAt),
+	}
+}
+
+// Birthday trả về dạng YYYY-MM-DD (giống định dạng khi tạo/sửa), rỗng nếu chưa có
+func formatBirthday(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
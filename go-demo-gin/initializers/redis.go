@@ -0,0 +1,38 @@
+package initializers
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Redis dùng chung (rate limit, cache...). nil nếu không cấu hình REDIS_URL => dùng bộ nhớ trong process
+var Redis *redis.Client
+
+func ConnectToRedis() error {
+	url := os.Getenv("REDIS_URL")
+	if url == "" {
+		logrus.WithField("source", "system").Warn("REDIS_URL is empty; using in-memory stores")
+		return nil
+	}
+
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		return err
+	}
+	client := redis.NewClient(opt)
+
+	// Check kết nối thực
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return err
+	}
+
+	Redis = client
+	logrus.WithField("source", "system").Info("Connected to redis")
+	return nil
+}
@@ -0,0 +1,252 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Repository interface {
+	Enqueue(ctx context.Context, j *models.Job) (bool, error)
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration, worker string) ([]models.Job, error)
+	Complete(ctx context.Context, j *models.Job, result string, at time.Time) error
+	Retry(ctx context.Context, j *models.Job, next time.Time, lastErr string) error
+	Fail(ctx context.Context, j *models.Job, lastErr string, at time.Time) error
+	Release(ctx context.Context, j *models.Job) error
+}
+
+// Handler xử lý một job; kết quả (nếu có) được lưu dạng JSON vào job.Result
+type Handler func(ctx context.Context, job *models.Job) (any, error)
+
+type Config struct {
+	Concurrency  int           // số job chạy đồng thời tối đa
+	PollInterval time.Duration // chu kỳ quét hàng đợi
+	Lease        time.Duration // thời gian giữ job, cũng là thời gian chạy tối đa của 1 lần thử
+	MaxAttempts  int           // mặc định cho job không chỉ định
+	BaseBackoff  time.Duration // backoff = BaseBackoff * 2^(attempts-1), tối đa MaxBackoff
+	MaxBackoff   time.Duration
+	DrainTimeout time.Duration // khi shutdown: chờ job đang chạy tối đa bao lâu trước khi huỷ
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Concurrency:  4,
+		PollInterval: time.Second,
+		Lease:        10 * time.Minute,
+		MaxAttempts:  5,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   time.Hour,
+		DrainTimeout: 30 * time.Second,
+	}
+}
+
+// EnqueueOptions: tuỳ chọn khi thêm job (giá trị zero => mặc định)
+type EnqueueOptions struct {
+	RunAt       time.Time // chạy sau thời điểm này (mặc định: ngay)
+	MaxAttempts int
+	UniqueKey   string // job cùng key đã tồn tại => không thêm
+	CreatedBy   *uint
+}
+
+// ErrDuplicate: đã có job cùng UniqueKey
+var ErrDuplicate = errors.New("jobs: duplicate unique key")
+
+// permanentError: lỗi không nên thử lại (payload sai, thiếu handler...)
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent đánh dấu lỗi để job chuyển thẳng sang failed, không thử lại
+func Permanent(err error) error { return &permanentError{err: err} }
+
+// Pool lấy job từ DB và chạy bằng tối đa Concurrency goroutine (at-least-once).
+// Nhiều instance có thể chạy cùng lúc trên cùng DB.
+type Pool struct {
+	repo      Repository
+	cfg       Config
+	handlers  map[string]Handler
+	schedules []schedule
+	worker    string
+	now       func() time.Time
+	log       *logrus.Entry
+}
+
+func NewPool(repo Repository, cfg Config) *Pool {
+	host, _ := os.Hostname()
+	return &Pool{
+		repo:     repo,
+		cfg:      cfg,
+		handlers: map[string]Handler{},
+		worker:   host + "-" + strconv.Itoa(os.Getpid()),
+		now:      time.Now,
+		log:      logrus.WithField("source", "jobs"),
+	}
+}
+
+// Register gắn handler cho một loại job (gọi trước Run)
+func (p *Pool) Register(jobType string, h Handler) {
+	p.handlers[jobType] = h
+}
+
+// Enqueue thêm job; payload được lưu dạng JSON. Trong transaction (utils.WithTx) job chỉ
+// được thấy khi transaction commit.
+func (p *Pool) Enqueue(ctx context.Context, jobType string, payload any, opts EnqueueOptions) (*models.Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	j := &models.Job{
+		Type:        jobType,
+		Payload:     string(raw),
+		Status:      models.JobQueued,
+		RunAt:       opts.RunAt,
+		MaxAttempts: opts.MaxAttempts,
+		CreatedBy:   opts.CreatedBy,
+	}
+	if j.RunAt.IsZero() {
+		j.RunAt = p.now()
+	}
+	if j.MaxAttempts <= 0 {
+		j.MaxAttempts = p.cfg.MaxAttempts
+	}
+	if opts.UniqueKey != "" {
+		j.UniqueKey = &opts.UniqueKey
+	}
+	created, err := p.repo.Enqueue(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrDuplicate
+	}
+	return j, nil
+}
+
+// Run quét hàng đợi và chạy job cho tới khi ctx bị huỷ, sau đó chờ các job đang chạy
+// xong (tối đa DrainTimeout). Job bị huỷ khi drain được trả lại hàng đợi.
+func (p *Pool) Run(ctx context.Context) {
+	p.log.WithField("worker", p.worker).Info("Job pool started")
+
+	// Context của job không bị huỷ ngay khi shutdown để job đang chạy hoàn tất (drain)
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, p.cfg.Concurrency)
+	done := make(chan struct{}, p.cfg.Concurrency) // worker xong => quét lại ngay
+	go p.runSchedules(ctx)
+
+	ticker := time.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if free := cap(slots) - len(slots); free > 0 {
+			batch, err := p.repo.Claim(ctx, p.now(), free, p.cfg.Lease, p.worker)
+			if err != nil && !errors.Is(err, context.Canceled) {
+				p.log.WithError(err).Error("Job claim failed")
+			}
+			for i := range batch {
+				j := batch[i]
+				slots <- struct{}{}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.execute(workCtx, &j)
+					<-slots
+					select {
+					case done <- struct{}{}:
+					default:
+					}
+				}()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			p.drain(&wg, cancelWork)
+			return
+		case <-ticker.C:
+		case <-done:
+		}
+	}
+}
+
+func (p *Pool) drain(wg *sync.WaitGroup, cancelWork context.CancelFunc) {
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(p.cfg.DrainTimeout):
+		p.log.Warn("Job drain timed out, cancelling running jobs")
+		cancelWork()
+		<-finished
+	}
+	p.log.Info("Job pool stopped")
+}
+
+// RunOnce nhận và chạy (tuần tự) một lô job đến hạn, trả về số job đã nhận
+func (p *Pool) RunOnce(ctx context.Context) (int, error) {
+	batch, err := p.repo.Claim(ctx, p.now(), p.cfg.Concurrency, p.cfg.Lease, p.worker)
+	if err != nil {
+		return 0, err
+	}
+	for i := range batch {
+		p.execute(ctx, &batch[i])
+	}
+	return len(batch), nil
+}
+
+func (p *Pool) execute(ctx context.Context, j *models.Job) {
+	log := p.log.WithFields(logrus.Fields{"job_id": j.ID, "type": j.Type, "attempts": j.Attempts})
+	result, err := p.call(ctx, j)
+
+	// Ghi trạng thái kể cả khi ctx đã bị huỷ
+	markCtx := context.WithoutCancel(ctx)
+	var markErr error
+	switch {
+	case err == nil:
+		raw, _ := json.Marshal(result)
+		markErr = p.repo.Complete(markCtx, j, string(raw), p.now())
+		log.Info("Job succeeded")
+	case ctx.Err() != nil:
+		markErr = p.repo.Release(markCtx, j)
+		log.WithError(err).Warn("Job interrupted by shutdown, released")
+	case errors.As(err, new(*permanentError)) || j.Attempts >= j.MaxAttempts:
+		markErr = p.repo.Fail(markCtx, j, err.Error(), p.now())
+		log.WithError(err).Error("Job failed")
+	default:
+		next := p.now().Add(events.Backoff(p.cfg.BaseBackoff, p.cfg.MaxBackoff, j.Attempts))
+		markErr = p.repo.Retry(markCtx, j, next, err.Error())
+		log.WithError(err).Warn("Job failed, will retry")
+	}
+	if markErr != nil {
+		log.WithError(markErr).Error("Job status update failed")
+	}
+}
+
+// Gọi handler với timeout = Lease; panic được chuyển thành lỗi
+func (p *Pool) call(ctx context.Context, j *models.Job) (result any, err error) {
+	h, ok := p.handlers[j.Type]
+	if !ok {
+		return nil, Permanent(fmt.Errorf("no handler for job type %q", j.Type))
+	}
+	jobCtx, cancel := context.WithTimeout(ctx, p.cfg.Lease)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(jobCtx, j)
+}
@@ -0,0 +1,210 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPool(t *testing.T, cfg Config) (*gorm.DB, *repo.GormJobRepo, *Pool) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // mỗi connection :memory: là một DB riêng
+	require.NoError(t, db.AutoMigrate(&models.Job{}))
+	r := repo.NewGormJobRepo(db)
+	return db, r, NewPool(r, cfg)
+}
+
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 3
+	cfg.PollInterval = 10 * time.Millisecond
+	return cfg
+}
+
+func reload(t *testing.T, r *repo.GormJobRepo, id uint) *models.Job {
+	t.Helper()
+	j, err := r.FindByID(context.Background(), id)
+	require.NoError(t, err)
+	return j
+}
+
+func TestPool_RunOnceSucceedsAndStoresResult(t *testing.T) {
+	ctx := context.Background()
+	_, r, p := setupPool(t, testConfig())
+	p.Register("echo", func(ctx context.Context, j *models.Job) (any, error) {
+		return map[string]string{"payload": j.Payload}, nil
+	})
+
+	j, err := p.Enqueue(ctx, "echo", map[string]int{"n": 1}, EnqueueOptions{UniqueKey: "echo-1"})
+	require.NoError(t, err)
+	_, err = p.Enqueue(ctx, "echo", nil, EnqueueOptions{UniqueKey: "echo-1"})
+	assert.ErrorIs(t, err, ErrDuplicate)
+
+	n, err := p.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	got := reload(t, r, j.ID)
+	assert.Equal(t, models.JobSucceeded, got.Status)
+	assert.Equal(t, 1, got.Attempts)
+	assert.JSONEq(t, `{"payload":"{\"n\":1}"}`, got.Result)
+	assert.NotNil(t, got.FinishedAt)
+
+	// Không còn job đến hạn
+	n, err = p.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestPool_RetriesWithBackoffThenFails(t *testing.T) {
+	ctx := context.Background()
+	_, r, p := setupPool(t, testConfig())
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	p.Register("flaky", func(context.Context, *models.Job) (any, error) { return nil, errors.New("boom") })
+
+	j, err := p.Enqueue(ctx, "flaky", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		n, err := p.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n, "attempt %d", attempt)
+		got := reload(t, r, j.ID)
+		assert.Equal(t, attempt, got.Attempts)
+		assert.Equal(t, "boom", got.LastError)
+		if attempt < 3 {
+			assert.Equal(t, models.JobQueued, got.Status)
+			assert.WithinDuration(t, now.Add(5*time.Second<<(attempt-1)), got.RunAt, time.Millisecond)
+			now = got.RunAt // tới hạn lần thử tiếp theo
+		} else {
+			assert.Equal(t, models.JobFailed, got.Status)
+		}
+	}
+}
+
+func TestPool_PermanentErrorsAndPanics(t *testing.T) {
+	ctx := context.Background()
+	_, r, p := setupPool(t, testConfig())
+	p.Register("bad", func(context.Context, *models.Job) (any, error) { return nil, Permanent(errors.New("invalid payload")) })
+	p.Register("panic", func(context.Context, *models.Job) (any, error) { panic("oops") })
+
+	bad, _ := p.Enqueue(ctx, "bad", nil, EnqueueOptions{})
+	unknown, _ := p.Enqueue(ctx, "unknown", nil, EnqueueOptions{})
+	panicky, _ := p.Enqueue(ctx, "panic", nil, EnqueueOptions{})
+	_, err := p.RunOnce(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.JobFailed, reload(t, r, bad.ID).Status)
+	assert.Equal(t, models.JobFailed, reload(t, r, unknown.ID).Status)
+	assert.Contains(t, reload(t, r, unknown.ID).LastError, "no handler")
+	got := reload(t, r, panicky.ID)
+	assert.Equal(t, models.JobQueued, got.Status) // panic được thử lại
+	assert.Equal(t, "panic: oops", got.LastError)
+}
+
+func TestGormJobRepo_ClaimSkipsClaimedAndReclaimsExpiredLease(t *testing.T) {
+	ctx := context.Background()
+	_, r, p := setupPool(t, testConfig())
+	j, err := p.Enqueue(ctx, "x", nil, EnqueueOptions{})
+	require.NoError(t, err)
+	now := time.Now()
+
+	first, err := r.Claim(ctx, now, 10, time.Minute, "w1")
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+	second, err := r.Claim(ctx, now, 10, time.Minute, "w2")
+	require.NoError(t, err)
+	assert.Empty(t, second)
+
+	// Worker w1 chết: hết lease => w2 nhận lại; w1 không còn ghi đè được kết quả
+	second, err = r.Claim(ctx, now.Add(2*time.Minute), 10, time.Minute, "w2")
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, 2, second[0].Attempts)
+	require.NoError(t, r.Fail(ctx, &first[0], "late", now))
+	assert.Equal(t, models.JobRunning, reload(t, r, j.ID).Status)
+	assert.Equal(t, "w2", reload(t, r, j.ID).LockedBy)
+}
+
+func TestPool_RunDrainsOnShutdown(t *testing.T) {
+	_, r, p := setupPool(t, testConfig())
+	started := make(chan struct{})
+	release := make(chan struct{})
+	p.Register("slow", func(ctx context.Context, _ *models.Job) (any, error) {
+		close(started)
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	j, err := p.Enqueue(context.Background(), "slow", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(stopped)
+	}()
+	<-started
+	cancel()
+
+	// Run chờ job đang chạy hoàn tất
+	select {
+	case <-stopped:
+		t.Fatal("Run returned before running job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-stopped
+	assert.Equal(t, models.JobSucceeded, reload(t, r, j.ID).Status)
+}
+
+func TestPool_DrainTimeoutReleasesJob(t *testing.T) {
+	cfg := testConfig()
+	cfg.DrainTimeout = 20 * time.Millisecond
+	_, r, p := setupPool(t, cfg)
+	started := make(chan struct{})
+	p.Register("stuck", func(ctx context.Context, _ *models.Job) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	j, err := p.Enqueue(context.Background(), "stuck", nil, EnqueueOptions{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(stopped)
+	}()
+	<-started
+	cancel()
+	<-stopped
+
+	// Trả lại hàng đợi, không tính là một lần thử
+	got := reload(t, r, j.ID)
+	assert.Equal(t, models.JobQueued, got.Status)
+	assert.Equal(t, 0, got.Attempts)
+}
+
+func TestPool_ScheduleRejectsInvalidSpec(t *testing.T) {
+	_, _, p := setupPool(t, testConfig())
+	assert.Error(t, p.Schedule("every day", "x", nil))
+	assert.NoError(t, p.Schedule("0 3 * * *", "x", nil))
+}
@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type schedule struct {
+	spec    cron.Schedule
+	jobType string
+	payload any
+}
+
+// Schedule thêm job định kỳ theo biểu thức cron 5 trường (vd: "0 3 * * *") hoặc "@every 1h".
+// Mỗi lần chạy dùng UniqueKey theo thời điểm nên nhiều instance chỉ tạo 1 job.
+func (p *Pool) Schedule(spec, jobType string, payload any) error {
+	s, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+	p.schedules = append(p.schedules, schedule{spec: s, jobType: jobType, payload: payload})
+	return nil
+}
+
+func (p *Pool) runSchedules(ctx context.Context) {
+	if len(p.schedules) == 0 {
+		return
+	}
+	next := make([]time.Time, len(p.schedules))
+	for i, s := range p.schedules {
+		next[i] = s.spec.Next(p.now())
+	}
+	for {
+		// Lịch gần nhất
+		first := 0
+		for i := range next {
+			if next[i].Before(next[first]) {
+				first = i
+			}
+		}
+		timer := time.NewTimer(time.Until(next[first]))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s, at := p.schedules[first], next[first]
+		key := "cron:" + s.jobType + ":" + strconv.FormatInt(at.Unix(), 10)
+		_, err := p.Enqueue(ctx, s.jobType, s.payload, EnqueueOptions{RunAt: at, UniqueKey: key})
+		if err != nil && !errors.Is(err, ErrDuplicate) {
+			p.log.WithError(err).WithField("type", s.jobType).Error("Scheduled job enqueue failed")
+		}
+		next[first] = s.spec.Next(at)
+	}
+}
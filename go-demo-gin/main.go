@@ -1,9 +1,20 @@
 package main
 
 import (
+	"context"
 	"go-demo-gin/docs"
+	"go-demo-gin/events"
+	"go-demo-gin/grpcapi"
 	"go-demo-gin/initializers"
+	"go-demo-gin/models"
 	"go-demo-gin/routes"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -35,7 +46,44 @@ func main() {
 		logrus.WithField("source", "system").WithError(err).Fatal("Fail to connect to database")
 	}
 
-	router := gin.Default()
+	// 5. Redis (tuỳ chọn)
+	if err := initializers.ConnectToRedis(); err != nil {
+		logrus.WithField("source", "system").WithError(err).Fatal("Fail to connect to redis")
+	}
+
+	// 6. Dependency (DI) dùng chung giữa HTTP, gRPC và worker nền. Tạo trước mọi goroutine:
+	// NewContainer đăng ký tenant scope (callback GORM) trên db dùng chung
+	container := routes.NewContainer(db)
+
+	// 7. Outbox dispatcher + webhook deliverer (gửi domain event chạy nền)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Các worker nền phải dừng hẳn trước khi đóng DB
+	var workers sync.WaitGroup
+	background := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+	bus := events.NewBus()
+	bus.Subscribe("*", func(ctx context.Context, e events.Event) error {
+		logrus.WithFields(logrus.Fields{"source": "outbox", "event_id": e.ID, "type": e.Type}).Info("Domain event published")
+		return nil
+	})
+	// Deliverer dùng chung WebhookService với API quản lý webhook (cùng cấu hình)
+	background(container.WebhookSvc.Run)
+	sinks := []events.Sink{bus, container.WebhookSvc}
+	if url := os.Getenv("EVENTS_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, events.NewWebhookSink(url, nil))
+	}
+	dispatcher := events.NewDispatcher(container.OutboxRepo, events.DefaultDispatcherConfig(), sinks...)
+	background(dispatcher.Run)
+
+	// Không dùng gin.Default(): panic được xử lý bởi middlewares.Recovery (gắn trong RegisterRoutes)
+	router := gin.New()
+	router.Use(gin.Logger())
 
 	// Swagger info
 	docs.SwaggerInfo.Title = "Swagger Example API"
@@ -43,12 +91,85 @@ func main() {
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 
-	// Routes (DI)
-	routes.SetupRoutes(router, db)
+	// Routes
+	routes.RegisterRoutes(router, container)
 
-	// (Optional) graceful shutdown: đóng sqlDB khi app dừng
-	sqlDB, _ := db.DB()
-	defer sqlDB.Close()
+	// gRPC server chạy song song với HTTP (cùng service, cache, audit...)
+	grpcAddr := os.Getenv("GRPC_ADDR")
+	if grpcAddr == "" {
+		grpcAddr = ":9090"
+	}
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		logrus.WithField("source", "system").WithError(err).Fatal("Fail to listen gRPC address")
+	}
+	grpcServer := grpcapi.NewServer(grpcapi.Config{
+		Validator:  container.Validator,
+		Users:      container.UserSvc,
+		Auth:       container.AuthSvc,
+		Tokens:     container.AuthSvc,
+		UserFinder: container.UserRepo,
+		Tenants:    container.TenantSvc,
+	})
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			logrus.WithField("source", "system").WithError(err).Error("gRPC server stopped")
+		}
+	}()
 
-	router.Run()
+	// 8. Job chạy nền: dọn dữ liệu cũ theo lịch (mặc định 3h sáng mỗi ngày)
+	retention, err := time.ParseDuration(os.Getenv("PURGE_RETENTION"))
+	if err != nil || retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	container.Jobs.Register("maintenance.purge", func(ctx context.Context, _ *models.Job) (any, error) {
+		before := time.Now().Add(-retention)
+		outbox, err := container.OutboxRepo.PurgeDelivered(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		finished, err := container.JobRepo.PurgeFinished(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		sessions, err := container.SessionRepo.PurgeExpired(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int64{"outbox_events": outbox, "jobs": finished, "sessions": sessions}, nil
+	})
+	purgeCron := os.Getenv("PURGE_CRON")
+	if purgeCron == "" {
+		purgeCron = "0 3 * * *"
+	}
+	if err := container.Jobs.Schedule(purgeCron, "maintenance.purge", nil); err != nil {
+		logrus.WithField("source", "system").WithError(err).Fatal("Invalid PURGE_CRON")
+	}
+	background(container.Jobs.Run)
+
+	// HTTP server (PORT giống gin.Run, mặc định 8080)
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	srv := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.WithField("source", "system").WithError(err).Fatal("HTTP server stopped")
+		}
+	}()
+
+	// Graceful shutdown: ngừng nhận request, chờ request/job/outbox đang chạy xong rồi mới đóng DB
+	<-ctx.Done()
+	logrus.WithField("source", "system").Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logrus.WithField("source", "system").WithError(err).Error("HTTP server shutdown failed")
+	}
+	grpcServer.GracefulStop()
+	workers.Wait()
+
+	sqlDB, _ := db.DB()
+	sqlDB.Close()
 }
@@ -24,6 +24,12 @@ func AccessLogger() gin.HandlerFunc {
 		log.Fatalf("Không thể tạo thư mục log: %v", err)
 	}
 
+	// Tạo sẵn file log: lumberjack chỉ tạo file ở lần ghi đầu tiên,
+	// trong khi công cụ đọc log (và access_log_test) cần file tồn tại ngay khi khởi động
+	if f, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644); err == nil {
+		f.Close()
+	}
+
 	rotator := &lumberjack.Logger{
 		Filename:   logFilePath,
 		MaxSize:    50, // MB
@@ -37,7 +43,8 @@ func AccessLogger() gin.HandlerFunc {
 	logger := log.New(mw, "", log.LstdFlags)
 
 	return func(c *gin.Context) {
-		if !strings.Contains(strings.ToLower(c.Request.RequestURI), "/api/v1") {
+		uri := strings.ToLower(c.Request.RequestURI)
+		if !strings.Contains(uri, "/api/v1") && !strings.HasPrefix(uri, "/graphql") {
 			c.Next()
 			return
 		}
@@ -71,6 +78,12 @@ func AccessLogger() gin.HandlerFunc {
 			"source": "service",
 		})
 		ctx := utils.WithLogger(c.Request.Context(), entry)
+		// Gắn request ID, IP, User-Agent vào context (dùng cho audit...)
+		ctx = utils.WithRequestInfo(ctx, &utils.RequestInfo{
+			ID:        id,
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
 		c.Request = c.Request.WithContext(ctx)
 
 		// Tiếp tục xử lý
@@ -84,6 +97,11 @@ func AccessLogger() gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 		contentTypeReq := c.ContentType()
 		contentTypeResp := c.Writer.Header().Get("Content-Type")
+		// Request impersonation => ghi kèm admin thực sự sau ID
+		logID := id
+		if actor := utils.ImpersonatorFrom(c.Request.Context()); actor != nil {
+			logID += " (impersonated by " + actor.Username + " #" + strconv.FormatUint(uint64(actor.ID), 10) + ")"
+		}
 		lang := c.Query("lang")
 		accept := c.GetHeader("Accept-Language")
 		if lang == "" && accept != "" {
@@ -113,7 +131,7 @@ func AccessLogger() gin.HandlerFunc {
 . Response body (Content type: %s):
 %s
 --------------------------------------------------------------------------
-`, id, clientIP, method, path, lang, statusCode, duration, contentTypeReq, formattedReq, contentTypeResp, formattedResp)
+`, logID, clientIP, method, path, lang, statusCode, duration, contentTypeReq, formattedReq, contentTypeResp, formattedResp)
 	}
 }
 
@@ -1,81 +1,207 @@
 package middlewares
 
 import (
+	"context"
+	"go-demo-gin/apperror"
 	"go-demo-gin/models"
-	errorResponse "go-demo-gin/responses/error"
 	"go-demo-gin/utils"
 	"net/http"
 	"os"
-	"slices"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"gorm.io/gorm"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
 )
 
-func Authentication(db *gorm.DB) func(allowedRoles ...models.Role) gin.HandlerFunc {
+// UserFinder: nguồn tra cứu user theo username (thường là repo.CachedUserRepo)
+type UserFinder interface {
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+}
+
+// APIKeyAuthenticator: xác thực personal API key (thường là services.APIKeyService)
+type APIKeyAuthenticator interface {
+	AuthenticateKey(ctx context.Context, raw string) (*models.User, *models.APIKey, error)
+}
+
+// SessionValidator: kiểm tra session (claim "sid") chưa bị thu hồi và ghi last-seen (thường là services.SessionService)
+type SessionValidator interface {
+	Validate(ctx context.Context, sid, userID uint) error
+}
+
+// Authentication chấp nhận JWT ("Authorization: Bearer ...") hoặc API key
+// ("Authorization: ApiKey ..." hoặc header "X-API-Key"); keys = nil => chỉ nhận JWT,
+// sessions = nil => không kiểm tra session của JWT
+func Authentication(users UserFinder, keys APIKeyAuthenticator, sessions SessionValidator) func(allowedRoles ...models.Role) gin.HandlerFunc {
 	return func(allowedRoles ...models.Role) gin.HandlerFunc {
 		return func(c *gin.Context) {
-			// Lấy localizer cho i18n
-			localizer := utils.LocalizerFrom(c.Request.Context())
-
-			// 1. Lấy header Authorization
+			var (
+				user  *models.User
+				actor *models.User // admin đang impersonate (claim "act")
+				key   *models.APIKey
+				sid   uint
+				ok    bool
+			)
+			// 1. Lấy header Authorization (hoặc X-API-Key)
 			authHeader := c.GetHeader("Authorization")
-			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": utils.LoadI18nMessage(localizer, utils.INVALID_AUTHOR_HEADER, nil)})
+			rawKey := c.GetHeader("X-API-Key")
+			if strings.HasPrefix(authHeader, "ApiKey ") {
+				rawKey = strings.TrimPrefix(authHeader, "ApiKey ")
+			}
+			switch {
+			case rawKey != "" && keys != nil:
+				user, key, ok = authenticateKey(c, keys, rawKey)
+			case strings.HasPrefix(authHeader, "Bearer "):
+				user, actor, sid, ok = authenticateToken(c, users, sessions, strings.TrimPrefix(authHeader, "Bearer "))
+			default:
+				utils.AbortWithProblem(c, http.StatusUnauthorized, utils.INVALID_AUTHOR_HEADER)
+				return
+			}
+			if !ok {
 				return
 			}
 
-			// 2. Cắt "Bearer " lấy token
-			tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-
-			// 3. Parse và xác minh token
-			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
-				// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
-				return []byte(os.Getenv("SECRET")), nil
-			}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse.Error{
-					Error: map[string]string{
-						"message": err.Error(),
-					},
-				})
+			// Tài khoản không còn active (vô hiệu hoá, khoá...) => token/key cũ (còn hạn) cũng bị từ chối ngay
+			if code := utils.AccountStatusError(user.Status); code != nil {
+				utils.AbortWithProblem(c, http.StatusForbidden, code)
 				return
 			}
 
-			// 4. Truy vấn thông tin user và phân quyền
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				username := claims["sub"]
-				var user models.User
-				result := db.First(&user, "username = ?", username)
-				if result.Error != nil {
-					c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse.Error{
-						Error: map[string]string{
-							"message": utils.LoadI18nMessage(localizer, utils.AUTHEN_REQUIRE, nil),
-						},
-					})
-					return
-				}
-
-				// Lưu thông tin user vào context
-				ctx := utils.WithInformation(c.Request.Context(), &user)
-				c.Request = c.Request.WithContext(ctx)
-
-				if slices.Contains(allowedRoles, user.Role) {
-					c.Next()
-				} else {
-					c.AbortWithStatusJSON(http.StatusForbidden, errorResponse.Error{
-						Error: map[string]string{
-							"message": utils.LoadI18nMessage(localizer, utils.PERMISSION_REQUIRE, nil),
-						},
-					})
-					return
-				}
+			// Dữ liệu của request giới hạn trong tenant của user (super admin được chọn tenant khác)
+			ctx, code := utils.ScopeToUser(c.Request.Context(), user)
+			if code != nil {
+				utils.AbortWithProblem(c, http.StatusForbidden, code)
+				return
+			}
+
+			// Lưu thông tin user (và key nếu có) vào context
+			ctx = utils.WithInformation(ctx, user)
+			if key != nil {
+				ctx = utils.WithAPIKey(ctx, key)
+			}
+			if sid != 0 {
+				ctx = utils.WithSessionID(ctx, sid)
+			}
+			// Impersonation: gắn admin thực sự vào context và mọi dòng log của request
+			if actor != nil {
+				ctx = utils.WithImpersonator(ctx, actor)
+				ctx = utils.WithLogger(ctx, utils.LoggerFrom(ctx).WithFields(logrus.Fields{
+					"impersonator_id": actor.ID,
+					"impersonator":    actor.Username,
+				}))
+				utils.LogCtx(ctx, logrus.InfoLevel, "Impersonated request", logrus.Fields{"user_id": user.ID})
+			}
+			c.Request = c.Request.WithContext(ctx)
+
+			if user.Role.In(allowedRoles) {
+				c.Next()
 			} else {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": utils.LoadI18nMessage(localizer, utils.INVALID_CLAIM, nil)})
+				utils.AbortWithProblem(c, http.StatusForbidden, utils.PERMISSION_REQUIRE)
 				return
 			}
 		}
 	}
 }
+
+func authenticateToken(c *gin.Context, users UserFinder, sessions SessionValidator, tokenStr string) (*models.User, *models.User, uint, bool) {
+	// Parse và xác minh token
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
+		// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
+		return []byte(os.Getenv("SECRET")), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		utils.LogCtx(c.Request.Context(), logrus.InfoLevel, "Token rejected: "+err.Error(), nil)
+		utils.AbortWithProblem(c, http.StatusUnauthorized, utils.INVALID_TOKEN)
+		return nil, nil, 0, false
+	}
+
+	// Truy vấn thông tin user
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		utils.AbortWithProblem(c, http.StatusUnauthorized, utils.INVALID_CLAIM)
+		return nil, nil, 0, false
+	}
+	// Username chỉ duy nhất trong tenant => tìm trong tenant của token (claim "tid"), không bao giờ theo tenant của request
+	username, _ := claims["sub"].(string)
+	user, err := users.FindByUsername(tenantCtx(c.Request.Context(), claims), username)
+	if err != nil {
+		utils.AbortWithProblem(c, http.StatusUnauthorized, utils.AUTHEN_REQUIRE)
+		return nil, nil, 0, false
+	}
+	// Claim "id" phải khớp user tìm được (username bị xoá rồi tạo lại, token của tenant khác...)
+	if !claimIDMatches(claims, user) {
+		utils.AbortWithProblem(c, http.StatusUnauthorized, utils.INVALID_CLAIM)
+		return nil, nil, 0, false
+	}
+
+	// Token impersonation (claim "act"): admin phải vẫn là admin đang active, session thuộc về admin
+	var actor *models.User
+	sessionOwner := user.ID
+	if act, isImpersonation := claims["act"]; isImpersonation {
+		actorClaim, _ := act.(map[string]any)
+		actorName, _ := actorClaim["sub"].(string)
+		actor, err = users.FindByUsername(tenantCtx(c.Request.Context(), actorClaim), actorName)
+		if err != nil || !claimIDMatches(actorClaim, actor) || !actor.Role.IsAdmin() || utils.AccountStatusError(actor.Status) != nil {
+			utils.AbortWithProblem(c, http.StatusUnauthorized, utils.INVALID_CLAIM)
+			return nil, nil, 0, false
+		}
+		sessionOwner = actor.ID
+	}
+
+	// Token gắn với session (claim "sid"): session bị thu hồi/hết hạn => từ chối; token cũ không có "sid" vẫn được chấp nhận
+	sidClaim, _ := claims["sid"].(float64)
+	sid := uint(sidClaim)
+	if sid != 0 && sessions != nil {
+		if err := sessions.Validate(c.Request.Context(), sid, sessionOwner); err != nil {
+			abortWithError(c, err, utils.SESSION_REVOKED)
+			return nil, nil, 0, false
+		}
+	}
+	return user, actor, sid, true
+}
+
+// Context tìm user theo claim "tid"; token cũ (trước multi-tenancy) thuộc tenant mặc định
+func tenantCtx(ctx context.Context, claims map[string]any) context.Context {
+	tenantID := models.DefaultTenantID
+	if tid, ok := claims["tid"].(float64); ok && tid > 0 {
+		tenantID = uint(tid)
+	}
+	return utils.WithTenant(ctx, utils.TenantScope{ID: tenantID})
+}
+
+// Claim "id" (số trong JSON => float64) phải trùng ID của user
+func claimIDMatches(claims map[string]any, u *models.User) bool {
+	id, ok := claims["id"].(float64)
+	return ok && id > 0 && uint(id) == u.ID
+}
+
+func authenticateKey(c *gin.Context, keys APIKeyAuthenticator, raw string) (*models.User, *models.APIKey, bool) {
+	user, key, err := keys.AuthenticateKey(c.Request.Context(), raw)
+	if err != nil {
+		utils.LogCtx(c.Request.Context(), logrus.InfoLevel, "API key rejected: "+err.Error(), nil)
+		abortWithError(c, err, utils.INVALID_API_KEY)
+		return nil, nil, false
+	}
+	return user, key, true
+}
+
+// Lỗi nghiệp vụ => status/mã lỗi tương ứng; lỗi khác => 401 với mã fallback
+func abortWithError(c *gin.Context, err error, fallback *i18n.Message) {
+	if appErr, ok := apperror.As(err); ok {
+		utils.AbortWithProblem(c, utils.HTTPStatus(appErr), utils.ErrorCode(appErr))
+		return
+	}
+	utils.AbortWithProblem(c, http.StatusUnauthorized, fallback)
+}
+
+// RequireScope: request xác thực bằng API key phải có scope; JWT (đăng nhập thật) thì không bị giới hạn
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := utils.APIKeyFrom(c.Request.Context()); key != nil && !key.HasScope(scope) {
+			utils.AbortWithProblem(c, http.StatusForbidden, utils.INSUFFICIENT_SCOPE)
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,10 +1,13 @@
 package middlewares
 
 import (
+	"errors"
+	"go-demo-gin/apperror"
 	errorResponse "go-demo-gin/responses/error"
-	"net/http"
+	"go-demo-gin/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 func ErrorHandler() gin.HandlerFunc {
@@ -15,17 +18,31 @@ func ErrorHandler() gin.HandlerFunc {
 		if len(errs) > 0 {
 			err := errs[0].Err
 
-			// Nếu là HTTPError, lấy status và message
-			if httpErr, ok := err.(*errorResponse.HTTPError); ok {
-				c.JSON(httpErr.StatusCode, httpErr.Message)
+			// Lỗi đã có sẵn status (middleware, test...)
+			var httpErr *errorResponse.HTTPError
+			if errors.As(err, &httpErr) {
+				utils.RenderProblem(c, httpErr)
 				return
 			}
 
-			// Lỗi thường
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":   "Internal Server Error",
-				"message": err.Error(),
-			})
+			// Lỗi nghiệp vụ; lỗi thường được coi là Internal và không trả nội dung lỗi gốc cho client
+			appErr, ok := apperror.As(err)
+			if !ok {
+				appErr = apperror.Internal(nil, err)
+			}
+			if appErr.Kind == apperror.KindInternal {
+				utils.LogCtx(c.Request.Context(), logrus.ErrorLevel, "Unhandled error: "+err.Error(), nil)
+			}
+			utils.RenderProblem(c, httpErrorFrom(appErr))
 		}
 	}
 }
+
+// Chuyển lỗi nghiệp vụ sang status + mã lỗi để render
+func httpErrorFrom(e *apperror.Error) *errorResponse.HTTPError {
+	return &errorResponse.HTTPError{
+		StatusCode: utils.HTTPStatus(e),
+		Code:       utils.ErrorCode(e),
+		Fields:     e.Fields,
+	}
+}
@@ -3,14 +3,18 @@ package middlewares
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 
+	"go-demo-gin/apperror"
 	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
 )
 
 func newRouterWithMW() *gin.Engine {
@@ -25,9 +29,8 @@ func TestErrorHandler_HTTPError(t *testing.T) {
 	r.GET("/bad", func(c *gin.Context) {
 		c.Error(&errorResponse.HTTPError{
 			StatusCode: http.StatusBadRequest,
-			Message: errorResponse.Error{
-				Error: map[string]string{"message": "bad input"},
-			},
+			Code:       &i18n.Message{ID: "BAD_INPUT", Other: "bad input"},
+			Fields:     map[string]*i18n.Message{"username": utils.USERNAME_REQUIRE},
 		})
 		// Không viết response ở handler; middleware sẽ xử lý.
 	})
@@ -46,12 +49,19 @@ func TestErrorHandler_HTTPError(t *testing.T) {
 		t.Fatalf("expected body to contain %q, got %s", "bad input", body)
 	}
 
-	// Thử parse để chắc có trường "error"
-	var m map[string]any
-	if err := json.Unmarshal(w.Body.Bytes(), &m); err == nil {
-		if _, ok := m["error"]; !ok {
-			t.Fatalf("expected top-level field 'error' in response JSON, got %v", m)
-		}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, errorResponse.ProblemContentType) {
+		t.Fatalf("expected content type %q, got %q", errorResponse.ProblemContentType, ct)
+	}
+
+	var p errorResponse.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("invalid problem JSON: %v", err)
+	}
+	if p.Code != "BAD_INPUT" || p.Type != "/problems/bad-input" || p.Status != http.StatusBadRequest || p.Instance != "/bad" {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "username" || p.Errors[0].Code != "USERNAME_REQUIRE" {
+		t.Fatalf("expected field error for username, got %+v", p.Errors)
 	}
 }
 
@@ -70,11 +80,12 @@ func TestErrorHandler_NormalError500(t *testing.T) {
 	}
 
 	body := w.Body.String()
-	if !strings.Contains(body, "Internal Server Error") {
-		t.Fatalf("expected body to contain %q, got %s", "Internal Server Error", body)
+	if !strings.Contains(body, "Internal Server Error") || !strings.Contains(body, "INTERNAL_ERROR") {
+		t.Fatalf("expected body to contain %q, got %s", "INTERNAL_ERROR", body)
 	}
-	if !strings.Contains(body, "boom") {
-		t.Fatalf("expected body to contain original error %q, got %s", "boom", body)
+	// Lỗi gốc chỉ ghi log, không trả về client
+	if strings.Contains(body, `"boom"`) {
+		t.Fatalf("expected body to hide original error %q, got %s", "boom", body)
 	}
 }
 
@@ -117,7 +128,42 @@ func TestErrorHandler_FirstErrorWins(t *testing.T) {
 	}
 
 	body := w.Body.String()
-	if !strings.Contains(body, "first") || strings.Contains(body, "second") && !strings.Contains(body, "first") {
-		t.Fatalf("expected middleware to respond using the FIRST error, got %s", body)
+	if strings.Count(body, `"code"`) != 1 {
+		t.Fatalf("expected a single problem for the FIRST error, got %s", body)
+	}
+}
+
+func TestErrorHandler_AppErrorKinds(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{apperror.NotFound(utils.NOT_FOUND, errors.New("record not found")), http.StatusNotFound, "NOT_FOUND"},
+		{apperror.Conflict(nil, nil), http.StatusConflict, "CONFLICT"},
+		{apperror.Validation(utils.VALIDATION_FAILED, map[string]*i18n.Message{"role": utils.INVALID_ROLE}), http.StatusBadRequest, "VALIDATION_FAILED"},
+		{apperror.Unauthorized(utils.INVALID_USERNAME_PASSWORD, nil), http.StatusUnauthorized, "INVALID_USERNAME_PASSWORD"},
+		{apperror.Forbidden(nil, nil), http.StatusForbidden, "PERMISSION_REQUIRE"},
+		{fmt.Errorf("update: %w", apperror.Internal(utils.UPDATE_FAIL, errors.New("db down"))), http.StatusInternalServerError, "UPDATE_FAIL"},
+	}
+
+	for _, tc := range cases {
+		r := newRouterWithMW()
+		r.GET("/x", func(c *gin.Context) { utils.HandleServiceError(c, tc.err) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+
+		var p errorResponse.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatalf("invalid problem JSON: %v", err)
+		}
+		if w.Code != tc.status || p.Code != tc.code {
+			t.Fatalf("%v: expected %d %s, got %d %s", tc.err, tc.status, tc.code, w.Code, p.Code)
+		}
+		// Nguyên nhân gốc không bao giờ xuất hiện trong response
+		if strings.Contains(w.Body.String(), "db down") || strings.Contains(w.Body.String(), "record not found") {
+			t.Fatalf("expected cause to be hidden, got %s", w.Body.String())
+		}
 	}
 }
@@ -15,9 +15,11 @@ func I18n() gin.HandlerFunc {
 
 		localizer := i18n.NewLocalizer(initializers.Bundle, lang, accept)
 
-		// Gắn vào context
+		// Gắn vào request context (service đọc qua utils.LocalizerFrom)
 		ctx := utils.WithLocalizer(c.Request.Context(), localizer)
 		c.Request = c.Request.WithContext(ctx)
+		// Handler đọc trực tiếp qua c.Get("localizer") (xem i18n_test) vẫn được hỗ trợ
+		c.Set("localizer", localizer)
 
 		c.Next()
 	}
@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-demo-gin/utils"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyRetryAfterSecs = 1
+)
+
+// IdempotencyRecord: response đầu tiên của một Idempotency-Key
+type IdempotencyRecord struct {
+	RequestHash string
+	Completed   bool // false = request đầu tiên vẫn đang xử lý (in-flight)
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// IdempotencyStore lưu response theo key trong khoảng TTL
+type IdempotencyStore interface {
+	// Begin giữ chỗ key nếu chưa tồn tại (started = true);
+	// ngược lại trả về record đang có (đang xử lý hoặc đã hoàn tất).
+	Begin(ctx context.Context, key, requestHash string, ttl time.Duration) (rec *IdempotencyRecord, started bool, err error)
+	Complete(ctx context.Context, key string, rec *IdempotencyRecord, ttl time.Duration) error
+	// Release bỏ giữ chỗ khi không lưu response (lỗi 5xx, panic...) để client có thể thử lại
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency lưu response đầu tiên của request có header Idempotency-Key
+// (theo caller + key) và trả lại y nguyên cho các lần gửi lại.
+// Không gắn vào endpoint cấp credential (login, register...): response chứa token
+// sẽ bị trả lại cho bất kỳ ai gửi lại cùng key từ cùng IP.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			utils.AbortWithProblem(c, http.StatusBadRequest, utils.INVALID_IDEMPOTENCY_KEY)
+			return
+		}
+
+		// Hash của request (method + path + body) để phát hiện dùng lại key cho request khác
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body)) // reset lại body
+		}
+		sum := sha256.New()
+		sum.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		sum.Write(body)
+		hash := hex.EncodeToString(sum.Sum(nil))
+
+		storeKey := "idem:" + idempotencyCaller(c) + ":" + key
+		rec, started, err := store.Begin(c.Request.Context(), storeKey, hash, ttl)
+		if err != nil {
+			// Store lỗi thì vẫn xử lý request bình thường (không chặn nghiệp vụ)
+			c.Next()
+			return
+		}
+
+		if !started {
+			switch {
+			case rec.RequestHash != hash:
+				utils.AbortWithProblem(c, http.StatusConflict, utils.IDEMPOTENCY_KEY_REUSED)
+			case !rec.Completed:
+				c.Header("Retry-After", strconv.Itoa(idempotencyRetryAfterSecs))
+				utils.AbortWithProblem(c, http.StatusConflict, utils.IDEMPOTENCY_IN_PROGRESS)
+			default:
+				// Trả lại response đã lưu
+				for k, vs := range rec.Header {
+					for _, v := range vs {
+						c.Writer.Header().Add(k, v)
+					}
+				}
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Writer.WriteHeader(rec.Status)
+				_, _ = c.Writer.Write(rec.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// Request đầu tiên: ghi lại response body
+		respBody := &bytes.Buffer{}
+		writer := &bodyWriter{body: respBody, ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		completed := false
+		defer func() {
+			// Panic hoặc không lưu được => bỏ giữ chỗ
+			if !completed {
+				_ = store.Release(context.WithoutCancel(c.Request.Context()), storeKey)
+			}
+		}()
+
+		c.Next()
+
+		// Chỉ lưu response đã được ghi trực tiếp và không phải lỗi server;
+		// lỗi qua c.Error được ErrorHandler ghi sau nên không lưu (client thử lại sẽ xử lý lại).
+		status := writer.Status()
+		if !writer.Written() || len(c.Errors) > 0 || status >= http.StatusInternalServerError {
+			return
+		}
+		err = store.Complete(c.Request.Context(), storeKey, &IdempotencyRecord{
+			RequestHash: hash,
+			Completed:   true,
+			Status:      status,
+			Header:      writer.Header().Clone(),
+			Body:        respBody.Bytes(),
+		}, ttl)
+		completed = err == nil
+	}
+}
+
+// Caller: user đã xác thực (nếu có), ngược lại là IP
+func idempotencyCaller(c *gin.Context) string {
+	if user := utils.InformationFrom(c.Request.Context()); user != nil {
+		return "user:" + strconv.FormatUint(uint64(user.ID), 10)
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,119 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MemoryIdempotencyStore: lưu trong bộ nhớ của process (phù hợp chạy 1 instance / test)
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	rec       IdempotencyRecord
+	expiresAt time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]memoryIdempotencyEntry), now: time.Now}
+}
+
+func (s *MemoryIdempotencyStore) Begin(ctx context.Context, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+	if e, ok := s.records[key]; ok && now.Before(e.expiresAt) {
+		rec := e.rec
+		return &rec, false, nil
+	}
+	s.records[key] = memoryIdempotencyEntry{
+		rec:       IdempotencyRecord{RequestHash: requestHash},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, true, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, rec *IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = memoryIdempotencyEntry{rec: *rec, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// Dọn các key hết hạn tối đa mỗi phút một lần
+func (s *MemoryIdempotencyStore) evictExpired(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for k, e := range s.records {
+		if now.After(e.expiresAt) {
+			delete(s.records, k)
+		}
+	}
+}
+
+// Giữ chỗ nguyên tử: SET NX nếu chưa có key, ngược lại trả về record đang lưu
+var idempotencyBeginScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+	return false
+end
+return redis.call('GET', KEYS[1])
+`)
+
+// RedisIdempotencyStore dùng chung giữa nhiều instance; record lưu dạng JSON
+type RedisIdempotencyStore struct {
+	client redis.Cmdable
+}
+
+func NewRedisIdempotencyStore(client redis.Cmdable) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client}
+}
+
+func (s *RedisIdempotencyStore) Begin(ctx context.Context, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, bool, error) {
+	pending, err := json.Marshal(IdempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, false, err
+	}
+	raw, err := idempotencyBeginScript.Run(ctx, s.client, []string{key}, pending, ttl.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var rec IdempotencyRecord
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, false, err
+	}
+	return &rec, false, nil
+}
+
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key string, rec *IdempotencyRecord, ttl time.Duration) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, key, raw, ttl).Err()
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
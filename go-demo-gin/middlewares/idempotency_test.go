@@ -0,0 +1,184 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouterWithIdempotency(t *testing.T, handler gin.HandlerFunc) *gin.Engine {
+	return setupRouterWithIdempotencyStore(t, NewMemoryIdempotencyStore(), handler)
+}
+
+func setupRouterWithIdempotencyStore(t *testing.T, store IdempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+
+	r := gin.New()
+	r.Use(I18n())
+	r.POST("/api/v1/users", Idempotency(store, time.Hour), handler)
+	return r
+}
+
+func postWithKey(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	var calls atomic.Int32
+	r := setupRouterWithIdempotency(t, func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("Location", "/api/v1/users/1")
+		c.JSON(http.StatusCreated, gin.H{"call": n})
+	})
+
+	first := postWithKey(r, "key-1", `{"username":"alice"}`)
+	second := postWithKey(r, "key-1", `{"username":"alice"}`)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "/api/v1/users/1", second.Header().Get("Location"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_KeyReusedWithDifferentBody(t *testing.T) {
+	r := setupRouterWithIdempotency(t, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	postWithKey(r, "key-1", `{"username":"alice"}`)
+	w := postWithKey(r, "key-1", `{"username":"bob"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"IDEMPOTENCY_KEY_REUSED"`)
+}
+
+func TestIdempotency_ConcurrentInFlightDuplicate(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := setupRouterWithIdempotency(t, func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postWithKey(r, "key-1", `{}`) }()
+	<-started
+
+	// Request trùng trong khi request đầu vẫn đang xử lý
+	w := postWithKey(r, "key-1", `{}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	close(release)
+	assert.Equal(t, http.StatusCreated, (<-done).Code)
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	var calls atomic.Int32
+	r := setupRouterWithIdempotency(t, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, postWithKey(r, "key-1", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, postWithKey(r, "key-1", `{}`).Code)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIdempotency_WithoutHeaderPassesThrough(t *testing.T) {
+	var calls atomic.Int32
+	r := setupRouterWithIdempotency(t, func(c *gin.Context) {
+		calls.Add(1)
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+
+	postWithKey(r, "", `{}`)
+	postWithKey(r, "", `{}`)
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestMemoryIdempotencyStore_Expires(t *testing.T) {
+	s := NewMemoryIdempotencyStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	_, started, _ := s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.True(t, started)
+	_, started, _ = s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.False(t, started)
+
+	now = now.Add(2 * time.Minute)
+	_, started, _ = s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.True(t, started)
+}
+
+func TestRedisIdempotencyStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	var calls atomic.Int32
+	r := setupRouterWithIdempotencyStore(t, NewRedisIdempotencyStore(client), func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("Location", "/api/v1/users/1")
+		c.JSON(http.StatusCreated, gin.H{"call": n})
+	})
+
+	first := postWithKey(r, "key-1", `{"username":"alice"}`)
+	second := postWithKey(r, "key-1", `{"username":"alice"}`)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "/api/v1/users/1", second.Header().Get("Location"))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+
+	assert.Equal(t, http.StatusConflict, postWithKey(r, "key-1", `{"username":"bob"}`).Code)
+
+	// Hết TTL => xử lý lại như request mới
+	mr.FastForward(2 * time.Hour)
+	postWithKey(r, "key-1", `{"username":"alice"}`)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRedisIdempotencyStore_InFlightAndRelease(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	s := NewRedisIdempotencyStore(client)
+
+	_, started, err := s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, started)
+	rec, started, err := s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, started)
+	assert.False(t, rec.Completed)
+	assert.Equal(t, "h", rec.RequestHash)
+
+	assert.NoError(t, s.Release(t.Context(), "k"))
+	_, started, _ = s.Begin(t.Context(), "k", "h", time.Minute)
+	assert.True(t, started)
+}
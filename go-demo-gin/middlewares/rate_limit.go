@@ -0,0 +1,155 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimit: tối đa Limit request trong mỗi Period (token bucket, burst = Limit)
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// RatePolicy cấu hình cho một nhóm route
+type RatePolicy struct {
+	Name    string                    // tên nhóm route, dùng làm prefix của key
+	Default RateLimit                 // áp dụng theo IP khi chưa xác thực, hoặc role không được cấu hình
+	Roles   map[models.Role]RateLimit // áp dụng theo user (role lấy từ user do Authentication nạp)
+	ByIP    bool                      // luôn giới hạn theo IP (policy đặt trước Authentication)
+}
+
+// ParseRateLimit đọc cấu hình dạng "<limit>/<period>", ví dụ "60/1m"
+func ParseRateLimit(s string) (RateLimit, error) {
+	n, p, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: want <limit>/<period>", s)
+	}
+	limit, err := strconv.Atoi(n)
+	if err != nil || limit <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: limit must be a positive integer", s)
+	}
+	period, err := time.ParseDuration(p)
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: period must be a positive duration", s)
+	}
+	return RateLimit{Limit: limit, Period: period}, nil
+}
+
+// FromEnv ghi đè giá trị mặc định của policy bằng biến môi trường:
+// RATE_LIMIT_<NAME> cho Default và RATE_LIMIT_<NAME>_<ROLE> cho từng role,
+// với NAME là Name viết hoa, ":" và "-" đổi thành "_" (vd: RATE_LIMIT_USERS_READ_ADMIN=600/1m).
+// Giá trị sai định dạng bị bỏ qua (giữ mặc định) và ghi log cảnh báo.
+func (p RatePolicy) FromEnv() RatePolicy {
+	prefix := "RATE_LIMIT_" + strings.ToUpper(strings.NewReplacer(":", "_", "-", "_").Replace(p.Name))
+	lookup := func(key string) (RateLimit, bool) {
+		v := os.Getenv(key)
+		if v == "" {
+			return RateLimit{}, false
+		}
+		l, err := ParseRateLimit(v)
+		if err != nil {
+			logrus.WithField("source", "system").WithError(err).Warn("Ignoring " + key)
+			return RateLimit{}, false
+		}
+		return l, true
+	}
+
+	out := p
+	if l, ok := lookup(prefix); ok {
+		out.Default = l
+	}
+	out.Roles = make(map[models.Role]RateLimit, len(p.Roles))
+	for role, l := range p.Roles {
+		out.Roles[role] = l
+	}
+	for _, role := range []models.Role{models.RoleSuperAdmin, models.RoleAdmin, models.RoleStaff, models.RoleCustomer} {
+		if l, ok := lookup(prefix + "_" + strings.ToUpper(string(role))); ok {
+			out.Roles[role] = l
+		}
+	}
+	return out
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // thời gian tới khi bucket đầy lại
+	RetryAfter time.Duration // thời gian phải chờ khi bị chặn
+}
+
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// RateLimiter trả về hàm tạo middleware theo policy, ví dụ:
+//
+//	Limit := middlewares.RateLimiter(store)
+//	users.GET("", RequireRoles(ADMIN), Limit(policy), uc.UsersIndex)
+//
+// Đặt sau Authentication để giới hạn theo user/role; nếu không có user (hoặc policy ByIP) thì giới hạn theo IP.
+func RateLimiter(store RateLimitStore) func(policy RatePolicy) gin.HandlerFunc {
+	return func(policy RatePolicy) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			limit := policy.Default
+			key := "rl:" + policy.Name + ":ip:" + c.ClientIP()
+			if user := utils.InformationFrom(c.Request.Context()); user != nil && !policy.ByIP {
+				key = "rl:" + policy.Name + ":user:" + strconv.FormatUint(uint64(user.ID), 10)
+				if l, ok := policy.Roles[user.Role]; ok {
+					limit = l
+				}
+			}
+
+			res, err := store.Allow(c.Request.Context(), key, limit)
+			if err != nil {
+				// Store lỗi => cho qua (fail-open) để không chặn toàn bộ hệ thống
+				utils.LogCtx(c.Request.Context(), logrus.WarnLevel, "Rate limit store failed: "+err.Error(), nil)
+				c.Next()
+				return
+			}
+
+			c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+			c.Header("RateLimit-Policy", strconv.Itoa(limit.Limit)+";w="+strconv.Itoa(ceilSeconds(limit.Period)))
+
+			if !res.Allowed {
+				c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				utils.AbortWithProblem(c, http.StatusTooManyRequests, utils.TOO_MANY_REQUESTS)
+				return
+			}
+			c.Next()
+		}
+	}
+}
+
+// gcraResult tính Remaining từ kết quả GCRA (dùng chung cho mọi store)
+func gcraResult(limit RateLimit, allowed bool, resetAfter, retryAfter time.Duration) RateLimitResult {
+	res := RateLimitResult{
+		Allowed:    allowed,
+		Limit:      limit.Limit,
+		ResetAfter: resetAfter,
+		RetryAfter: retryAfter,
+	}
+	if allowed {
+		interval := limit.Period / time.Duration(limit.Limit)
+		res.Remaining = int((limit.Period - resetAfter) / interval)
+	}
+	return res
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
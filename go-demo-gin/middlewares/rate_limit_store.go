@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Thuật toán GCRA (tương đương token bucket): mỗi key chỉ cần lưu TAT (theoretical arrival time).
+// interval = Period / Limit; request được phép khi now >= TAT + interval - Period.
+
+// MemoryRateLimitStore: lưu trong bộ nhớ của process (phù hợp chạy 1 instance / test)
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{tats: make(map[string]time.Time), now: time.Now}
+}
+
+func (s *MemoryRateLimitStore) Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	interval := limit.Period / time.Duration(limit.Limit)
+	tat, ok := s.tats[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+	newTat := tat.Add(interval)
+	allowAt := newTat.Add(-limit.Period)
+	if now.Before(allowAt) {
+		return gcraResult(limit, false, tat.Sub(now), allowAt.Sub(now)), nil
+	}
+	s.tats[key] = newTat
+	return gcraResult(limit, true, newTat.Sub(now), 0), nil
+}
+
+// Dọn các key đã đầy bucket (TAT đã qua) tối đa mỗi phút một lần
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for k, tat := range s.tats {
+		if tat.Before(now) {
+			delete(s.tats, k)
+		}
+	}
+}
+
+// Script chạy nguyên tử trên Redis; thời gian tính bằng mili giây
+var gcraScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - period
+if now < allow_at then
+	return {0, tat - now, allow_at - now}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil(new_tat - now))
+return {1, new_tat - now, 0}
+`)
+
+// RedisRateLimitStore dùng chung giữa nhiều instance (Redis hoặc server tương thích giao thức Redis)
+type RedisRateLimitStore struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+func NewRedisRateLimitStore(client redis.Scripter) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client, now: time.Now}
+}
+
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	interval := limit.Period / time.Duration(limit.Limit)
+	res, err := gcraScript.Run(ctx, s.client, []string{key},
+		s.now().UnixMilli(), interval.Milliseconds(), limit.Period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	return gcraResult(limit, res[0] == 1, time.Duration(res[1])*time.Millisecond, time.Duration(res[2])*time.Millisecond), nil
+}
@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var testPolicy = RatePolicy{
+	Name:    "test",
+	Default: RateLimit{Limit: 2, Period: time.Minute},
+	Roles: map[models.Role]RateLimit{
+		models.RoleAdmin: {Limit: 5, Period: time.Minute},
+	},
+}
+
+// Middleware stub: gắn user vào context như Authentication
+func withStubUser(user *models.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user != nil {
+			c.Request = c.Request.WithContext(utils.WithInformation(c.Request.Context(), user))
+		}
+		c.Next()
+	}
+}
+
+func setupRouterWithRateLimit(t *testing.T, store RateLimitStore, user *models.User) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+
+	r := gin.New()
+	r.Use(I18n())
+	r.GET("/api/v1/users", withStubUser(user), RateLimiter(store)(testPolicy), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return r
+}
+
+func get(r *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_PerIPDefaultLimit(t *testing.T) {
+	r := setupRouterWithRateLimit(t, NewMemoryRateLimitStore(), nil)
+
+	w := get(r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	w = get(r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = get(r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Too many requests")
+}
+
+func TestRateLimit_PerRoleLimit(t *testing.T) {
+	admin := &models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin}
+	r := setupRouterWithRateLimit(t, NewMemoryRateLimitStore(), admin)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, get(r).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get(r).Code)
+}
+
+func TestMemoryRateLimitStore_Refills(t *testing.T) {
+	s := NewMemoryRateLimitStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	limit := RateLimit{Limit: 2, Period: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		res, _ := s.Allow(t.Context(), "k", limit)
+		assert.True(t, res.Allowed)
+	}
+	res, _ := s.Allow(t.Context(), "k", limit)
+	assert.False(t, res.Allowed)
+
+	// Sau 1 interval (30s) có thêm 1 token
+	now = now.Add(30 * time.Second)
+	res, _ = s.Allow(t.Context(), "k", limit)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	store := NewRedisRateLimitStore(client)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	r := setupRouterWithRateLimit(t, store, nil)
+
+	assert.Equal(t, http.StatusOK, get(r).Code)
+	w := get(r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	w = get(r)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, http.StatusOK, get(r).Code)
+}
+
+func TestRateLimit_ByIPIgnoresUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+	admin := &models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin}
+	policy := testPolicy
+	policy.ByIP = true
+
+	r := gin.New()
+	r.Use(I18n())
+	r.GET("/api/v1/users", withStubUser(admin), RateLimiter(NewMemoryRateLimitStore())(policy), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	// Giới hạn mặc định theo IP (2), không dùng giới hạn của role admin (5)
+	assert.Equal(t, http.StatusOK, get(r).Code)
+	assert.Equal(t, http.StatusOK, get(r).Code)
+	assert.Equal(t, http.StatusTooManyRequests, get(r).Code)
+}
+
+func TestParseRateLimit(t *testing.T) {
+	l, err := ParseRateLimit("60/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, RateLimit{Limit: 60, Period: time.Minute}, l)
+
+	for _, s := range []string{"", "60", "0/1m", "x/1m", "60/abc", "60/-1s"} {
+		_, err := ParseRateLimit(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRatePolicy_FromEnv(t *testing.T) {
+	t.Setenv("RATE_LIMIT_USERS_READ", "30/1m")
+	t.Setenv("RATE_LIMIT_USERS_READ_ADMIN", "1000/1h")
+	t.Setenv("RATE_LIMIT_USERS_READ_STAFF", "not-a-limit")
+	t.Setenv("RATE_LIMIT_USERS_READ_CUSTOMER", "10/1s")
+	base := RatePolicy{
+		Name:    "users:read",
+		Default: RateLimit{Limit: 60, Period: time.Minute},
+		Roles: map[models.Role]RateLimit{
+			models.RoleAdmin: {Limit: 600, Period: time.Minute},
+			models.RoleStaff: {Limit: 300, Period: time.Minute},
+		},
+	}
+
+	p := base.FromEnv()
+	assert.Equal(t, RateLimit{Limit: 30, Period: time.Minute}, p.Default)
+	assert.Equal(t, RateLimit{Limit: 1000, Period: time.Hour}, p.Roles[models.RoleAdmin])
+	assert.Equal(t, RateLimit{Limit: 300, Period: time.Minute}, p.Roles[models.RoleStaff]) // sai định dạng => giữ mặc định
+	assert.Equal(t, RateLimit{Limit: 10, Period: time.Second}, p.Roles[models.RoleCustomer])
+	// Không sửa policy gốc
+	assert.Equal(t, RateLimit{Limit: 600, Period: time.Minute}, base.Roles[models.RoleAdmin])
+	assert.NotContains(t, base.Roles, models.RoleCustomer)
+}
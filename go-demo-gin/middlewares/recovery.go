@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"go-demo-gin/apperror"
+	"go-demo-gin/utils"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Số panic đã recover, xem qua expvar ("panics_total")
+var panicsTotal = expvar.NewInt("panics_total")
+
+// Recovery bắt panic trong handler: ghi log kèm stack trace (logger của request => có request ID)
+// và trả lỗi 500 dạng problem+json giống ErrorHandler. Client ngắt kết nối (broken pipe) chỉ ghi cảnh báo.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			ctx := c.Request.Context()
+
+			if isBrokenConnection(rec) {
+				// Không thể ghi response cho client đã đóng kết nối
+				utils.LogCtx(ctx, logrus.WarnLevel, fmt.Sprintf("Client connection closed: %v", rec), nil)
+				c.Abort()
+				return
+			}
+
+			panicsTotal.Add(1)
+			utils.LogCtx(ctx, logrus.ErrorLevel, fmt.Sprintf("Panic recovered: %v", rec), logrus.Fields{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"stack":  string(debug.Stack()),
+			})
+
+			if c.Writer.Written() {
+				// Handler đã gửi một phần response => chỉ có thể dừng
+				c.Abort()
+				return
+			}
+			utils.RenderProblem(c, httpErrorFrom(apperror.Internal(nil, fmt.Errorf("panic: %v", rec))))
+		}()
+		c.Next()
+	}
+}
+
+// Panic do client đóng kết nối giữa chừng (broken pipe, connection reset, http.ErrAbortHandler)
+func isBrokenConnection(rec any) bool {
+	err, ok := rec.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		var sysErr *os.SyscallError
+		if errors.As(opErr, &sysErr) {
+			msg := strings.ToLower(sysErr.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	errorResponse "go-demo-gin/responses/error"
+	"go-demo-gin/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouterWithRecovery(t *testing.T) (*gin.Engine, *test.Hook) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+
+	logger, hook := test.NewNullLogger()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx := utils.WithLogger(c.Request.Context(), logger.WithField("id", "req-1"))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	r.Use(ErrorHandler(), I18n(), Recovery())
+	r.GET("/api/v1/panic", func(c *gin.Context) {
+		var m map[string]int
+		m["boom"] = 1 // panic: assignment to entry in nil map
+	})
+	r.GET("/api/v1/broken", func(c *gin.Context) {
+		panic(&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)})
+	})
+	return r, hook
+}
+
+func TestRecovery_PanicReturnsProblem(t *testing.T) {
+	r, hook := setupRouterWithRecovery(t)
+	before := panicsTotal.Value()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), errorResponse.ProblemContentType)
+	var p errorResponse.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "INTERNAL_ERROR", p.Code)
+	assert.NotContains(t, w.Body.String(), "nil map")
+
+	assert.Equal(t, before+1, panicsTotal.Value())
+	entry := hook.LastEntry()
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+		assert.Equal(t, "req-1", entry.Data["id"])
+		assert.Contains(t, entry.Message, "nil map")
+		assert.True(t, strings.Contains(entry.Data["stack"].(string), "recovery_test.go"))
+	}
+}
+
+func TestRecovery_BrokenPipeIsQuiet(t *testing.T) {
+	r, hook := setupRouterWithRecovery(t)
+	before := panicsTotal.Value()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/broken", nil))
+
+	assert.Equal(t, before, panicsTotal.Value())
+	entry := hook.LastEntry()
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Nil(t, entry.Data["stack"])
+	}
+}
+
+// Recovery đứng đầu chain (như routes.RegisterRoutes) => bắt được cả panic trong middleware
+func TestRecovery_FirstInChainCatchesMiddlewarePanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+	before := panicsTotal.Value()
+
+	r := gin.New()
+	r.Use(Recovery(), ErrorHandler(), I18n(), func(c *gin.Context) {
+		panic("middleware boom")
+	})
+	r.GET("/api/v1/users", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var p errorResponse.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, "INTERNAL_ERROR", p.Code)
+	assert.Equal(t, before+1, panicsTotal.Value())
+}
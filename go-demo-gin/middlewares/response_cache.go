@@ -0,0 +1,286 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"go-demo-gin/cache"
+	"go-demo-gin/utils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const ResponseCacheHeader = "X-Cache"
+
+// ResponseCacheConfig: cấu hình cache response cho một resource (vd: "users")
+type ResponseCacheConfig struct {
+	Name string
+	TTL  time.Duration
+	// Các query param ảnh hưởng tới kết quả; param khác bị bỏ qua khi tạo key
+	VaryQuery []string
+	// Thời điểm thay đổi gần nhất của resource (max updated_at/deleted_at của bảng)
+	LastModified func(ctx context.Context) (time.Time, error)
+}
+
+// ResponseCache cache response 200 của các GET endpoint và trả 304 cho conditional GET.
+// Key gồm tenant, route, query đã chuẩn hoá, role của caller, Accept-Language và Last-Modified
+// của resource => khi dữ liệu đổi (Invalidate) key cũ tự động không còn được dùng.
+type ResponseCache struct {
+	store cache.Cache
+	cfg   ResponseCacheConfig
+}
+
+type cachedResponse struct {
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+func NewResponseCache(store cache.Cache, cfg ResponseCacheConfig) *ResponseCache {
+	return &ResponseCache{store: store, cfg: cfg}
+}
+
+// Invalidate xoá Last-Modified đã cache (của tenant trong ctx); request tiếp theo đọc lại từ DB và dùng key mới.
+// Với backend LRU, các instance khác vẫn có thể trả dữ liệu cũ tối đa TTL.
+func (rc *ResponseCache) Invalidate(ctx context.Context) {
+	if err := rc.store.Delete(context.WithoutCancel(ctx), rc.lastModifiedKey(ctx)); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Response cache invalidation failed: "+err.Error(), nil)
+	}
+}
+
+func (rc *ResponseCache) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		directives := parseCacheControl(c.GetHeader("Cache-Control"))
+		if directives.noStore {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		lastModified, err := rc.lastModified(ctx)
+		if err != nil {
+			// Lỗi khi lấy Last-Modified => bỏ qua cache, không chặn request
+			utils.LogCtx(ctx, logrus.WarnLevel, "Response cache unavailable: "+err.Error(), nil)
+			c.Next()
+			return
+		}
+
+		variant := rc.variant(c)
+		etag := responseETag(variant, lastModified)
+		header := c.Writer.Header()
+		header.Set("ETag", etag)
+		if !lastModified.IsZero() {
+			header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+		// Response phụ thuộc vào người gọi => chỉ cho client cache, luôn phải revalidate
+		header.Set("Cache-Control", "private, no-cache")
+		header.Add("Vary", "Authorization, Accept-Language")
+
+		if notModified(c.Request, etag, lastModified) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		key := rc.responseKey(variant, lastModified)
+		if !directives.noCache {
+			if entry := rc.load(ctx, key); entry != nil {
+				age := time.Since(entry.StoredAt)
+				if directives.maxAge < 0 || age <= time.Duration(directives.maxAge)*time.Second {
+					header.Set(ResponseCacheHeader, "HIT")
+					header.Set("Age", strconv.Itoa(int(age.Seconds())))
+					c.Data(entry.Status, entry.ContentType, entry.Body)
+					c.Abort()
+					return
+				}
+			}
+		}
+		if directives.onlyIfCached {
+			utils.AbortWithProblem(c, http.StatusGatewayTimeout, utils.NOT_CACHED)
+			return
+		}
+
+		header.Set(ResponseCacheHeader, "MISS")
+		writer := &cacheWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() != http.StatusOK || len(c.Errors) > 0 {
+			return
+		}
+		rc.save(ctx, key, &cachedResponse{
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body,
+			StoredAt:    time.Now(),
+		})
+	}
+}
+
+// Last-Modified của resource: ưu tiên giá trị đã cache, hết hạn/bị xoá thì hỏi DB
+func (rc *ResponseCache) lastModified(ctx context.Context) (time.Time, error) {
+	key := rc.lastModifiedKey(ctx)
+	if data, ok, err := rc.store.Get(ctx, key); err == nil && ok {
+		if t, err := time.Parse(time.RFC3339Nano, string(data)); err == nil {
+			return t, nil
+		}
+	}
+	t, err := rc.cfg.LastModified(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := rc.store.Set(ctx, key, []byte(t.UTC().Format(time.RFC3339Nano)), rc.cfg.TTL); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Response cache write failed: "+err.Error(), nil)
+	}
+	return t, nil
+}
+
+func (rc *ResponseCache) load(ctx context.Context, key string) *cachedResponse {
+	data, ok, err := rc.store.Get(ctx, key)
+	if err != nil || !ok {
+		return nil
+	}
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+func (rc *ResponseCache) save(ctx context.Context, key string, entry *cachedResponse) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := rc.store.Set(ctx, key, data, rc.cfg.TTL); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Response cache write failed: "+err.Error(), nil)
+	}
+}
+
+// Biến thể của response: tenant + route + query đã chuẩn hoá + role + ngôn ngữ
+func (rc *ResponseCache) variant(c *gin.Context) string {
+	query := url.Values{}
+	for _, name := range rc.cfg.VaryQuery {
+		if v := strings.TrimSpace(c.Query(name)); v != "" {
+			query.Set(name, v)
+		}
+	}
+	role := ""
+	if user := utils.InformationFrom(c.Request.Context()); user != nil {
+		role = string(user.Role)
+	}
+	lang := strings.ToLower(strings.ReplaceAll(c.GetHeader("Accept-Language"), " ", ""))
+	// url.Values.Encode sắp xếp theo tên param => thứ tự query không ảnh hưởng tới key
+	return strings.Join([]string{tenantKey(c.Request.Context()), c.FullPath(), query.Encode(), role, lang}, "|")
+}
+
+// Mỗi tenant có Last-Modified riêng (dữ liệu được lọc theo tenant)
+func (rc *ResponseCache) lastModifiedKey(ctx context.Context) string {
+	return "resp:" + rc.cfg.Name + ":" + tenantKey(ctx) + ":last_modified"
+}
+
+func tenantKey(ctx context.Context) string {
+	if id, ok := utils.TenantIDFrom(ctx); ok {
+		return strconv.FormatUint(uint64(id), 10)
+	}
+	return "-"
+}
+
+func (rc *ResponseCache) responseKey(variant string, lastModified time.Time) string {
+	sum := sha256.Sum256([]byte(variant))
+	return "resp:" + rc.cfg.Name + ":" + strconv.FormatInt(lastModified.UnixNano(), 10) + ":" + hex.EncodeToString(sum[:16])
+}
+
+func responseETag(variant string, lastModified time.Time) string {
+	sum := sha256.Sum256([]byte(variant + "|" + strconv.FormatInt(lastModified.UnixNano(), 10)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Conditional GET: If-None-Match được ưu tiên hơn If-Modified-Since (RFC 7232)
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !lastModified.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
+
+type cacheControl struct {
+	noStore      bool
+	noCache      bool
+	onlyIfCached bool
+	maxAge       int // -1 = không giới hạn
+}
+
+// Các directive của Cache-Control phía request
+func parseCacheControl(v string) cacheControl {
+	cc := cacheControl{maxAge: -1}
+	for _, part := range strings.Split(v, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(strings.ToLower(part)), "=")
+		switch name {
+		case "no-store":
+			cc.noStore = true
+		case "no-cache":
+			cc.noCache = true
+		case "only-if-cached":
+			cc.onlyIfCached = true
+		case "max-age":
+			if n, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && n >= 0 {
+				cc.maxAge = n
+			}
+		}
+	}
+	return cc
+}
+
+// cacheWriter ghi lại body để cache và bỏ ETag/Last-Modified nếu response không phải 200
+type cacheWriter struct {
+	gin.ResponseWriter
+	body    []byte
+	written bool
+}
+
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	w.beforeWrite()
+	w.body = append(w.body, b...)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheWriter) WriteString(s string) (int, error) {
+	w.beforeWrite()
+	w.body = append(w.body, s...)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheWriter) beforeWrite() {
+	if w.written {
+		return
+	}
+	w.written = true
+	if w.Status() != http.StatusOK {
+		for _, h := range []string{"ETag", "Last-Modified", "Cache-Control", ResponseCacheHeader} {
+			w.Header().Del(h)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-demo-gin/cache"
+	"go-demo-gin/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type responseCacheFixture struct {
+	router       *gin.Engine
+	rc           *ResponseCache
+	calls        int
+	lastModified time.Time
+}
+
+func setupRouterWithResponseCache(t *testing.T, user *models.User) *responseCacheFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	setupTestBundle(t)
+
+	f := &responseCacheFixture{lastModified: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}
+	f.rc = NewResponseCache(cache.NewLRU(100), ResponseCacheConfig{
+		Name:      "users",
+		TTL:       time.Minute,
+		VaryQuery: []string{"limit", "page", "sort", "search"},
+		LastModified: func(ctx context.Context) (time.Time, error) {
+			return f.lastModified, nil
+		},
+	})
+
+	f.router = gin.New()
+	f.router.Use(I18n())
+	f.router.GET("/api/v1/users", withStubUser(user), f.rc.Handler(), func(c *gin.Context) {
+		f.calls++
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"calls": f.calls})
+	})
+	return f
+}
+
+func (f *responseCacheFixture) get(target string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestResponseCache_HitOnSecondRequest(t *testing.T) {
+	f := setupRouterWithResponseCache(t, nil)
+
+	w := f.get("/api/v1/users?page=1&limit=10", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MISS", w.Header().Get(ResponseCacheHeader))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.Equal(t, "Wed, 01 May 2024 10:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	// Thứ tự query khác + param không liên quan => vẫn cùng key
+	w = f.get("/api/v1/users?limit=10&page=1&utm=x", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get(ResponseCacheHeader))
+	assert.JSONEq(t, `{"calls":1}`, w.Body.String())
+	assert.Equal(t, 1, f.calls)
+}
+
+func TestResponseCache_VariesByRoleAndLanguage(t *testing.T) {
+	admin := &models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin}
+	f := setupRouterWithResponseCache(t, admin)
+
+	f.get("/api/v1/users", map[string]string{"Accept-Language": "en"})
+	w := f.get("/api/v1/users", map[string]string{"Accept-Language": "vi"})
+	assert.Equal(t, "MISS", w.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 2, f.calls)
+
+	admin.Role = models.RoleStaff
+	w = f.get("/api/v1/users", map[string]string{"Accept-Language": "vi"})
+	assert.Equal(t, "MISS", w.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 3, f.calls)
+}
+
+func TestResponseCache_ConditionalGet(t *testing.T) {
+	f := setupRouterWithResponseCache(t, nil)
+
+	w := f.get("/api/v1/users", nil)
+	etag := w.Header().Get("ETag")
+
+	w = f.get("/api/v1/users", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = f.get("/api/v1/users", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 10:00:00 GMT"})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	w = f.get("/api/v1/users", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 09:59:59 GMT"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, f.calls)
+}
+
+func TestResponseCache_InvalidateChangesETag(t *testing.T) {
+	f := setupRouterWithResponseCache(t, nil)
+
+	w := f.get("/api/v1/users", nil)
+	etag := w.Header().Get("ETag")
+
+	// Dữ liệu đổi nhưng chưa invalidate => vẫn trả bản cũ (Last-Modified đang được cache)
+	f.lastModified = f.lastModified.Add(time.Hour)
+	w = f.get("/api/v1/users", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	f.rc.Invalidate(context.Background())
+	w = f.get("/api/v1/users", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.Equal(t, "MISS", w.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 2, f.calls)
+}
+
+func TestResponseCache_RequestDirectives(t *testing.T) {
+	f := setupRouterWithResponseCache(t, nil)
+
+	w := f.get("/api/v1/users", map[string]string{"Cache-Control": "only-if-cached"})
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, 0, f.calls)
+
+	f.get("/api/v1/users", nil)
+
+	w = f.get("/api/v1/users", map[string]string{"Cache-Control": "no-cache"})
+	assert.Equal(t, "MISS", w.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 2, f.calls)
+
+	w = f.get("/api/v1/users", map[string]string{"Cache-Control": "no-store"})
+	assert.Empty(t, w.Header().Get(ResponseCacheHeader))
+	assert.Equal(t, 3, f.calls)
+
+	// Bản cache hiện tại là của request no-cache (calls = 2)
+	w = f.get("/api/v1/users", map[string]string{"Cache-Control": "max-age=60"})
+	assert.Equal(t, "HIT", w.Header().Get(ResponseCacheHeader))
+	assert.JSONEq(t, `{"calls":2}`, w.Body.String())
+}
+
+func TestResponseCache_ErrorsAreNotCached(t *testing.T) {
+	f := setupRouterWithResponseCache(t, nil)
+
+	w := f.get("/api/v1/users?fail=1", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	f.get("/api/v1/users?fail=1", nil)
+	assert.Equal(t, 2, f.calls)
+}
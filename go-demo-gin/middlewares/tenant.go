@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"context"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHeader: client chọn tenant bằng slug (ưu tiên hơn subdomain)
+const TenantHeader = "X-Tenant"
+
+// TenantResolver: tra cứu tenant theo slug (thường là services.TenantService)
+type TenantResolver interface {
+	Resolve(ctx context.Context, slug string) (*models.Tenant, error)
+}
+
+// ResolveTenant xác định tenant của request: header X-Tenant, sau đó subdomain của baseDomain
+// (acme.api.example.com với baseDomain "api.example.com"); không có => tenant mặc định.
+// Request đã xác thực được Authentication giới hạn lại theo tenant của user.
+func ResolveTenant(tenants TenantResolver, baseDomain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := strings.ToLower(strings.TrimSpace(c.GetHeader(TenantHeader)))
+		if slug == "" {
+			slug = subdomain(c.Request.Host, baseDomain)
+		}
+		t, err := tenants.Resolve(c.Request.Context(), slug)
+		if err != nil {
+			abortWithError(c, err, utils.UNKNOWN_TENANT)
+			return
+		}
+		ctx := utils.WithTenant(c.Request.Context(), utils.TenantScope{ID: t.ID, Requested: slug != ""})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// Nhãn đứng ngay trước baseDomain; host khác (IP, domain khác, nhiều cấp) => ""
+func subdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -20,5 +20,17 @@ func main() {
 		logrus.WithField("source", "system").WithError(err).Fatal("Fail to connect to database")
 	}
 
-	db.AutoMigrate(&models.User{})
+	db.AutoMigrate(&models.Tenant{}, &models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.WebhookEndpoint{}, &models.WebhookDelivery{}, &models.Job{}, &models.UserIdentity{}, &models.APIKey{}, &models.Session{}, &models.PasswordHistory{}, &models.Organization{}, &models.Team{}, &models.OrganizationMember{}, &models.TeamMember{}, &models.Invitation{})
+
+	// 3. Multi-tenant: tenant mặc định chứa dữ liệu cũ (tenant_id default 1)
+	def := models.Tenant{ID: models.DefaultTenantID, Slug: "default", Name: "Default"}
+	if err := db.Where("id = ?", def.ID).FirstOrCreate(&def).Error; err != nil {
+		logrus.WithField("source", "system").WithError(err).Fatal("Fail to create default tenant")
+	}
+	// Postgres: insert với id tường minh không tăng sequence => đồng bộ lại để tenant tạo sau không trùng id
+	if db.Dialector.Name() == "postgres" {
+		if err := db.Exec("SELECT setval(pg_get_serial_sequence('tenants', 'id'), GREATEST((SELECT MAX(id) FROM tenants), 1))").Error; err != nil {
+			logrus.WithField("source", "system").WithError(err).Fatal("Fail to sync tenants id sequence")
+		}
+	}
 }
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Scope của API key; key chỉ dùng được cho route yêu cầu scope mà key có
+const (
+	ScopeUsersRead     = "users:read"
+	ScopeUsersWrite    = "users:write"
+	ScopeAuditRead     = "audit:read"
+	ScopeWebhooksRead  = "webhooks:read"
+	ScopeWebhooksWrite = "webhooks:write"
+	ScopeJobsRead      = "jobs:read"
+	ScopeOrgsRead      = "orgs:read"
+	ScopeOrgsWrite     = "orgs:write"
+	// Không cấp được cho key => route yêu cầu scope này chỉ dùng được khi đăng nhập bằng JWT
+	ScopeSession = "session"
+)
+
+// APIKey: khoá truy cập cho tích hợp máy-máy, thuộc về 1 user và mang quyền (role) của user đó.
+// Key đầy đủ chỉ hiển thị 1 lần khi tạo; DB chỉ lưu prefix (để tra cứu) và SHA-256 của key.
+type APIKey struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uint   `gorm:"index;not null"`
+	Name       string `gorm:"type:varchar(100)"`
+	Prefix     string `gorm:"type:varchar(32);uniqueIndex"`
+	Hash       string `gorm:"type:varchar(64)"`
+	Scopes     string `gorm:"type:varchar(255)"` // phân tách bằng dấu cách
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(k.Scopes), scope)
+}
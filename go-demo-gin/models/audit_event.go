@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditUserCreate      AuditAction = "user.create"
+	AuditUserUpdate      AuditAction = "user.update"
+	AuditUserRoleChange  AuditAction = "user.role_change"
+	AuditUserDelete      AuditAction = "user.delete"
+	AuditUserRestore     AuditAction = "user.restore"
+	AuditUserStatus      AuditAction = "user.status_change"
+	AuditUserImpersonate AuditAction = "user.impersonate"
+)
+
+// AuditEvent chỉ ghi thêm (append-only) nên không dùng gorm.Model (không có UpdatedAt/DeletedAt)
+type AuditEvent struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"index"`
+	TenantID  uint      `gorm:"not null;default:1;index"` // tenant của request ghi audit
+	ActorID   *uint     `gorm:"index"`
+	ActorName string    `gorm:"type:varchar(50)"`
+	// Admin đang đăng nhập dưới danh nghĩa actor (impersonation); nil nếu actor tự thao tác
+	ImpersonatorID   *uint       `gorm:"index"`
+	ImpersonatorName string      `gorm:"type:varchar(50)"`
+	Action           AuditAction `gorm:"type:varchar(50);index"`
+	TargetType       string      `gorm:"type:varchar(50)"`
+	TargetID         uint        `gorm:"index"`
+	Changes          string      `gorm:"type:text"` // JSON: {"field": {"before": ..., "after": ...}}
+	RequestID        string      `gorm:"type:varchar(64)"`
+	IP               string      `gorm:"type:varchar(64)"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed" // lỗi không thử lại được hoặc vượt quá số lần thử
+)
+
+// Job: tác vụ chạy nền (purge theo lịch...) lưu trong DB, worker pool lấy ra chạy.
+// Import/export user vẫn xử lý đồng bộ trong request: payload import chứa mật khẩu dạng rõ
+// (không được lưu vào bảng jobs), export stream thẳng cho client (chưa có nơi lưu file kết quả).
+// Worker chạy handler với context không có tenant (mọi tenant); handler cần giới hạn theo tenant thì dùng TenantID.
+type Job struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	TenantID    uint      `gorm:"not null;default:1;index"` // tenant của request tạo job; job hệ thống (cron) thuộc tenant mặc định
+	Type        string    `gorm:"type:varchar(100);index"`
+	Payload     string    `gorm:"type:text"` // JSON
+	Status      JobStatus `gorm:"type:varchar(20);index:idx_jobs_due,priority:1"`
+	RunAt       time.Time `gorm:"index:idx_jobs_due,priority:2"` // thời điểm được chạy (lần đầu hoặc lần thử lại)
+	Attempts    int
+	MaxAttempts int
+	LockedBy    string     `gorm:"type:varchar(100)"` // worker đang giữ job
+	LockedUntil *time.Time // hết lease mà chưa xong (worker chết) => worker khác được nhận lại
+	UniqueKey   *string    `gorm:"type:varchar(191);uniqueIndex"` // chống enqueue trùng (vd: cron chạy trên nhiều instance)
+	CreatedBy   *uint      `gorm:"index"`                         // user tạo job (nil: hệ thống/cron)
+	Result      string     `gorm:"type:text"`                     // JSON
+	LastError   string     `gorm:"type:text"`
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+}
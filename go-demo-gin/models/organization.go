@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// MembershipRole: quyền của user trong 1 organization/team (độc lập với Role toàn hệ thống)
+type MembershipRole string
+
+const (
+	MemberOwner   MembershipRole = "owner"
+	MemberManager MembershipRole = "manager"
+	MemberMember  MembershipRole = "member"
+)
+
+// CanManage: owner/manager được quản lý thành viên
+func (r MembershipRole) CanManage() bool {
+	return r == MemberOwner || r == MemberManager
+}
+
+// CanAssign: r được đổi quyền của thành viên từ from sang to (from rỗng => thêm mới).
+// Chỉ owner mới được cấp/đổi/xoá quyền owner; manager quản lý các quyền còn lại.
+func (r MembershipRole) CanAssign(from, to MembershipRole) bool {
+	if !r.CanManage() {
+		return false
+	}
+	if r == MemberOwner {
+		return true
+	}
+	return from != MemberOwner && to != MemberOwner
+}
+
+// Organization: nhóm user (công ty con, phòng ban...) trong 1 tenant
+type Organization struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TenantID  uint   `gorm:"not null;default:1;index"`
+	Name      string `gorm:"type:varchar(100);not null"`
+}
+
+// Team thuộc 1 organization; thành viên team phải là thành viên của organization
+type Team struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TenantID       uint   `gorm:"not null;default:1;index"`
+	OrganizationID uint   `gorm:"not null;index"`
+	Name           string `gorm:"type:varchar(100);not null"`
+}
+
+type OrganizationMember struct {
+	ID             uint      `gorm:"primarykey"`
+	CreatedAt      time.Time // thời điểm tham gia
+	UpdatedAt      time.Time
+	TenantID       uint           `gorm:"not null;default:1;index"`
+	OrganizationID uint           `gorm:"not null;uniqueIndex:idx_org_members_org_user,priority:1"`
+	UserID         uint           `gorm:"not null;uniqueIndex:idx_org_members_org_user,priority:2;index"`
+	User           *User          `gorm:"constraint:OnDelete:CASCADE"`
+	Role           MembershipRole `gorm:"type:varchar(20);not null"`
+}
+
+type TeamMember struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	TenantID  uint           `gorm:"not null;default:1;index"`
+	TeamID    uint           `gorm:"not null;uniqueIndex:idx_team_members_team_user,priority:1"`
+	UserID    uint           `gorm:"not null;uniqueIndex:idx_team_members_team_user,priority:2;index"`
+	User      *User          `gorm:"constraint:OnDelete:CASCADE"`
+	Role      MembershipRole `gorm:"type:varchar(20);not null"`
+}
+
+// Invitation mời 1 email vào organization (và team nếu có TeamID). Token chỉ gửi qua email,
+// DB lưu SHA-256 của token; dùng 1 lần.
+type Invitation struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TenantID       uint   `gorm:"not null;default:1;index"`
+	OrganizationID uint   `gorm:"not null;index"`
+	TeamID         *uint  `gorm:"index"`
+	Email          string `gorm:"type:varchar(255);not null;index"`
+	// Quyền nhận được: trong team nếu có TeamID (organization => member), ngược lại trong organization
+	Role         MembershipRole `gorm:"type:varchar(20);not null"`
+	TokenHash    string         `gorm:"type:varchar(64);uniqueIndex"`
+	InvitedByID  uint
+	ExpiresAt    time.Time
+	AcceptedAt   *time.Time
+	AcceptedByID *uint
+	RevokedAt    *time.Time
+}
+
+// Pending: chưa chấp nhận, chưa thu hồi và còn hạn
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMembershipRole_CanAssign(t *testing.T) {
+	cases := []struct {
+		actor    MembershipRole
+		from, to MembershipRole
+		ok       bool
+	}{
+		{MemberOwner, "", MemberOwner, true},
+		{MemberOwner, MemberOwner, MemberMember, true},
+		{MemberManager, "", MemberManager, true},
+		{MemberManager, MemberMember, MemberManager, true},
+		{MemberManager, "", MemberOwner, false},
+		{MemberManager, MemberOwner, MemberMember, false},
+		{MemberMember, "", MemberMember, false},
+		{"", "", MemberMember, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.ok, tc.actor.CanAssign(tc.from, tc.to), "%s: %q -> %q", tc.actor, tc.from, tc.to)
+	}
+}
+
+func TestInvitation_Pending(t *testing.T) {
+	now := time.Now()
+	inv := Invitation{ExpiresAt: now.Add(time.Hour)}
+	assert.True(t, inv.Pending(now))
+	assert.False(t, inv.Pending(now.Add(2*time.Hour)))
+	inv.AcceptedAt = &now
+	assert.False(t, inv.Pending(now))
+}
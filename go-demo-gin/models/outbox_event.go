@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxDelivered OutboxStatus = "delivered"
+	OutboxDead      OutboxStatus = "dead" // dead-letter: vượt quá số lần thử
+)
+
+// OutboxEvent được ghi trong cùng transaction với thay đổi nghiệp vụ,
+// dispatcher chạy nền sẽ đọc và gửi tới các sink (at-least-once)
+type OutboxEvent struct {
+	ID             uint `gorm:"primarykey"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TenantID       uint         `gorm:"not null;default:1;index"`
+	EventID        string       `gorm:"type:varchar(36);uniqueIndex"`
+	Type           string       `gorm:"type:varchar(100);index"`
+	AggregateType  string       `gorm:"type:varchar(50)"`
+	AggregateID    uint         `gorm:"index"`
+	Payload        string       `gorm:"type:text"`
+	Status         OutboxStatus `gorm:"type:varchar(20);index"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	DeliveredSinks string    `gorm:"type:text"` // tên các sink đã nhận thành công, phân tách bằng dấu phẩy
+	LastError      string    `gorm:"type:text"`
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// PasswordHistory: hash của các mật khẩu đã dùng (kể cả mật khẩu hiện tại), để chặn dùng lại
+type PasswordHistory struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"index;not null"`
+	Hash      string `gorm:"type:varchar(255);not null"`
+}
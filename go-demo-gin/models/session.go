@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Session: 1 lần đăng nhập (1 thiết bị/trình duyệt); JWT mang ID của session trong claim "sid"
+// => thu hồi session là token tương ứng bị từ chối ngay dù còn hạn
+type Session struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uint   `gorm:"index;not null"`
+	UserAgent  string `gorm:"type:varchar(255)"`
+	IP         string `gorm:"type:varchar(64)"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time `gorm:"index"` // = hạn của token mới nhất cấp cho session
+	RevokedAt  *time.Time
+}
+
+// Active: chưa bị thu hồi và token chưa hết hạn
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
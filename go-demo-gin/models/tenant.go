@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Tenant mặc định: dữ liệu có sẵn trước khi có multi-tenancy và request không chọn tenant
+const DefaultTenantID uint = 1
+
+// Tenant: công ty khách hàng; user (và audit, webhook, outbox event) của các tenant tách biệt nhau.
+// Mọi model có trường TenantID được repo tự lọc theo tenant của request (repo.RegisterTenantScope).
+type Tenant struct {
+	ID         uint `gorm:"primarykey"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Slug       string `gorm:"type:varchar(63);uniqueIndex;not null"` // dùng trong header X-Tenant và subdomain
+	Name       string `gorm:"type:varchar(255)"`
+	DisabledAt *time.Time
+}
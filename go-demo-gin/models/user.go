@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,13 +14,68 @@ const (
 	RoleAdmin    Role = "admin"
 	RoleStaff    Role = "staff"
 	RoleCustomer Role = "customer"
+	// Quản trị toàn hệ thống: có mọi quyền của admin và được chọn tenant khác (header X-Tenant);
+	// không cấp được qua API
+	RoleSuperAdmin Role = "super_admin"
 )
 
+// IsAdmin: admin của tenant hoặc super admin
+func (r Role) IsAdmin() bool {
+	return r == RoleAdmin || r == RoleSuperAdmin
+}
+
+// In: role có trong danh sách được phép không; super admin dùng được mọi chỗ admin dùng được
+func (r Role) In(roles []Role) bool {
+	if r == RoleSuperAdmin {
+		return slices.Contains(roles, RoleAdmin) || slices.Contains(roles, RoleSuperAdmin)
+	}
+	return slices.Contains(roles, r)
+}
+
+type UserStatus string
+
+const (
+	UserPending  UserStatus = "pending"  // chưa kích hoạt
+	UserActive   UserStatus = "active"   // trạng thái duy nhất được đăng nhập
+	UserDisabled UserStatus = "disabled" // bị vô hiệu hoá (kèm lý do)
+	UserLocked   UserStatus = "locked"   // bị khoá tạm thời, mở khoá => active
+)
+
+// Các chuyển trạng thái hợp lệ: action => trạng thái đích và các trạng thái nguồn được phép
+var userTransitions = map[string]struct {
+	to   UserStatus
+	from []UserStatus
+}{
+	"activate": {UserActive, []UserStatus{UserPending, UserDisabled}},
+	"disable":  {UserDisabled, []UserStatus{UserPending, UserActive, UserLocked}},
+	"lock":     {UserLocked, []UserStatus{UserActive}},
+	"unlock":   {UserActive, []UserStatus{UserLocked}},
+}
+
+// Transition trả về trạng thái mới sau action; ok=false nếu action không hợp lệ với trạng thái hiện tại
+func (s UserStatus) Transition(action string) (UserStatus, bool) {
+	t, found := userTransitions[action]
+	if !found || !slices.Contains(t.from, s) {
+		return s, false
+	}
+	return t.to, true
+}
+
 type User struct {
 	gorm.Model
+	// Username/email chỉ duy nhất trong tenant
+	TenantID uint `gorm:"not null;default:1;uniqueIndex:idx_users_tenant_email,priority:1"`
 	Username string
 	Password string
 	Name     sql.NullString
 	Birthday *time.Time `gorm:"type:date"`
 	Role     Role       `gorm:"type:varchar(20)"`
+	// Email (nil với user cũ), duy nhất trong 1 tenant; tự đăng ký thì phải xác thực qua link trước khi active
+	Email              *string `gorm:"type:varchar(255);uniqueIndex:idx_users_tenant_email,priority:2"`
+	EmailVerifiedAt    *time.Time
+	VerificationSentAt *time.Time // chống gửi lại liên tục
+	// Trạng thái tài khoản: khác active => không đăng nhập được, token cũ bị từ chối
+	Status          UserStatus `gorm:"type:varchar(20);default:active;index"`
+	StatusReason    string     `gorm:"type:varchar(255)"`
+	StatusChangedAt *time.Time
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserIdentity: liên kết user với danh tính ở IdP ngoài (OIDC); 1 user có thể có nhiều danh tính,
+// mỗi cặp (provider, subject) chỉ thuộc về 1 user
+type UserIdentity struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uint    `gorm:"index;not null"`
+	Provider    string  `gorm:"type:varchar(50);uniqueIndex:idx_identity_subject,priority:1;not null"`
+	Subject     string  `gorm:"type:varchar(255);uniqueIndex:idx_identity_subject,priority:2;not null"` // claim "sub"
+	Email       *string `gorm:"type:varchar(255)"`                                                      // email IdP báo lúc liên kết
+	LastLoginAt *time.Time
+}
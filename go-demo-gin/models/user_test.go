@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatus_Transition(t *testing.T) {
+	cases := []struct {
+		from   UserStatus
+		action string
+		to     UserStatus
+		ok     bool
+	}{
+		{UserPending, "activate", UserActive, true},
+		{UserDisabled, "activate", UserActive, true},
+		{UserActive, "activate", "", false},
+		{UserPending, "disable", UserDisabled, true},
+		{UserLocked, "disable", UserDisabled, true},
+		{UserDisabled, "disable", "", false},
+		{UserActive, "lock", UserLocked, true},
+		{UserPending, "lock", "", false},
+		{UserLocked, "unlock", UserActive, true},
+		{UserActive, "unlock", "", false},
+		{UserActive, "delete", "", false},
+	}
+	for _, tc := range cases {
+		to, ok := tc.from.Transition(tc.action)
+		assert.Equal(t, tc.ok, ok, "%s -> %s", tc.from, tc.action)
+		if tc.ok {
+			assert.Equal(t, tc.to, to)
+		}
+	}
+}
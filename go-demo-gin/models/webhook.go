@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookEndpoint struct {
+	gorm.Model
+	TenantID     uint   `gorm:"not null;default:1;index"` // chỉ nhận event của user cùng tenant
+	URL          string `gorm:"type:varchar(2048)"`
+	Secret       string `gorm:"type:varchar(128)"`
+	EventTypes   string `gorm:"type:text"` // các loại event đăng ký, phân tách bằng dấu phẩy
+	Description  string
+	Active       bool
+	FailureCount int // số lần gửi lỗi liên tiếp, reset khi gửi thành công
+	DisabledAt   *time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // hết số lần thử hoặc endpoint đã bị tắt
+)
+
+// WebhookDelivery: một event gửi tới một endpoint (kèm kết quả của lần thử gần nhất)
+type WebhookDelivery struct {
+	ID            uint `gorm:"primarykey"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	EndpointID    uint                  `gorm:"uniqueIndex:idx_webhook_delivery_event"`
+	EventID       string                `gorm:"type:varchar(36);uniqueIndex:idx_webhook_delivery_event"`
+	EventType     string                `gorm:"type:varchar(100)"`
+	Payload       string                `gorm:"type:text"`
+	Status        WebhookDeliveryStatus `gorm:"type:varchar(20);index"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"index"`
+	ResponseCode  int
+	ResponseBody  string `gorm:"type:text"`
+	Error         string `gorm:"type:text"`
+	DurationMs    int64
+	DeliveredAt   *time.Time
+}
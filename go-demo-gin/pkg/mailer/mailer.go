@@ -0,0 +1,89 @@
+// Package mailer: gửi email qua Sender có thể thay thế (SMTP khi chạy thật, Memory khi test).
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string // text/plain
+}
+
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTP gửi qua máy chủ SMTP (PLAIN auth nếu có Username)
+type SMTP struct {
+	Addr     string // host:port
+	From     string
+	Username string
+	Password string
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := net.SplitHostPort(s.Addr)
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\nTo: %s\r\nSubject: %s\r\n", s.From, msg.To, msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	return smtp.SendMail(s.Addr, auth, s.From, []string{msg.To}, []byte(b.String()))
+}
+
+// Log chỉ ghi log (môi trường dev không cấu hình SMTP)
+type Log struct{}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	logrus.WithFields(logrus.Fields{"source": "mailer", "to": msg.To, "subject": msg.Subject}).Info(msg.Body)
+	return nil
+}
+
+// Memory giữ lại email đã gửi, dùng cho test
+type Memory struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func NewMemory() *Memory { return &Memory{} }
+
+func (m *Memory) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent trả về bản sao các email đã gửi (theo thứ tự)
+func (m *Memory) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last: email gần nhất gửi tới địa chỉ to
+func (m *Memory) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To == to {
+			return m.sent[i], true
+		}
+	}
+	return Message{}, false
+}
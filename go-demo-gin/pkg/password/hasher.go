@@ -0,0 +1,148 @@
+// Package password: hash/verify mật khẩu (Argon2id, bcrypt) và chính sách mật khẩu.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type Algorithm string
+
+const (
+	Argon2id Algorithm = "argon2id"
+	Bcrypt   Algorithm = "bcrypt"
+)
+
+var ErrUnknownHash = errors.New("password: unknown hash format")
+
+// Argon2Params: tham số Argon2id (Memory tính bằng KiB)
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// HashConfig: thuật toán dùng cho hash mới; hash cũ khác thuật toán/tham số sẽ được hash lại khi đăng nhập
+type HashConfig struct {
+	Algorithm  Algorithm
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+// DefaultHashConfig: Argon2id theo khuyến nghị OWASP (19 MiB, 2 vòng, 1 luồng)
+func DefaultHashConfig() HashConfig {
+	return HashConfig{
+		Algorithm:  Argon2id,
+		Argon2:     Argon2Params{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		BcryptCost: bcrypt.DefaultCost,
+	}
+}
+
+type Hasher struct {
+	cfg HashConfig
+}
+
+func NewHasher(cfg HashConfig) *Hasher {
+	return &Hasher{cfg: cfg}
+}
+
+// Hash mật khẩu với thuật toán/tham số hiện tại
+func (h *Hasher) Hash(plain string) (string, error) {
+	if h.cfg.Algorithm == Bcrypt {
+		b, err := bcrypt.GenerateFromPassword([]byte(plain), h.cfg.BcryptCost)
+		return string(b), err
+	}
+
+	p := h.cfg.Argon2
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	// Định dạng PHC: $argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify so khớp mật khẩu với hash đã lưu (bcrypt hoặc Argon2id);
+// needsRehash = true khi khớp nhưng hash dùng thuật toán/tham số cũ
+func (h *Hasher) Verify(hash, plain string) (ok, needsRehash bool, err error) {
+	switch {
+	case hash == "":
+		return false, false, nil // user không có mật khẩu local (vd: tạo qua OIDC)
+	case strings.HasPrefix(hash, "$argon2id$"):
+		p, salt, key, err := decodeArgon2(hash)
+		if err != nil {
+			return false, false, err
+		}
+		got := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return false, false, nil
+		}
+		want := h.cfg.Argon2
+		return true, h.cfg.Algorithm != Argon2id || p.Memory != want.Memory || p.Iterations != want.Iterations ||
+			p.Parallelism != want.Parallelism || uint32(len(key)) != want.KeyLength, nil
+	case strings.HasPrefix(hash, "$2"):
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		cost, _ := bcrypt.Cost([]byte(hash))
+		return true, h.cfg.Algorithm != Bcrypt || cost != h.cfg.BcryptCost, nil
+	default:
+		return false, false, ErrUnknownHash
+	}
+}
+
+func decodeArgon2(hash string) (p Argon2Params, salt, key []byte, err error) {
+	// ["", "argon2id", "v=19", "m=...,t=...,p=...", salt, key]
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, ErrUnknownHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, ErrUnknownHash
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return p, nil, nil, ErrUnknownHash
+	}
+	return p, salt, key, nil
+}
+
+// Hasher dùng chung (request DTO hash mật khẩu khi map sang model); cấu hình lúc khởi động bằng SetDefault
+var defaultHasher atomic.Pointer[Hasher]
+
+func init() {
+	defaultHasher.Store(NewHasher(DefaultHashConfig()))
+}
+
+func SetDefault(h *Hasher) { defaultHasher.Store(h) }
+
+func Default() *Hasher { return defaultHasher.Load() }
+
+// Hash bằng hasher dùng chung
+func Hash(plain string) (string, error) { return Default().Hash(plain) }
+
+// Verify bằng hasher dùng chung
+func Verify(hash, plain string) (ok, needsRehash bool, err error) {
+	return Default().Verify(hash, plain)
+}
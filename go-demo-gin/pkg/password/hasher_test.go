@@ -0,0 +1,80 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Tham số nhỏ cho test nhanh
+func testConfig() HashConfig {
+	cfg := DefaultHashConfig()
+	cfg.Argon2.Memory = 1024
+	cfg.Argon2.Iterations = 1
+	cfg.BcryptCost = bcrypt.MinCost
+	return cfg
+}
+
+func TestHasher_Argon2idRoundTrip(t *testing.T) {
+	h := NewHasher(testConfig())
+	hash, err := h.Hash("Secret.123")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), hash)
+
+	other, _ := h.Hash("Secret.123")
+	assert.NotEqual(t, hash, other) // salt ngẫu nhiên
+
+	ok, rehash, err := h.Verify(hash, "Secret.123")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, _, err = h.Verify(hash, "secret.123")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	cfg := testConfig()
+	h := NewHasher(cfg)
+
+	// bcrypt cũ => cần chuyển sang Argon2id
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("secret.123"), bcrypt.MinCost)
+	ok, rehash, err := h.Verify(string(legacy), "secret.123")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	// Argon2id với tham số cũ
+	stronger := cfg
+	stronger.Argon2.Iterations = 2
+	hash, _ := h.Hash("secret.123")
+	ok, rehash, _ = NewHasher(stronger).Verify(hash, "secret.123")
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	// Cấu hình bcrypt: cost khác => cần hash lại
+	bcryptCfg := cfg
+	bcryptCfg.Algorithm = Bcrypt
+	bcryptCfg.BcryptCost = bcrypt.MinCost + 1
+	ok, rehash, _ = NewHasher(bcryptCfg).Verify(string(legacy), "secret.123")
+	assert.True(t, ok)
+	assert.True(t, rehash)
+}
+
+func TestHasher_InvalidHashes(t *testing.T) {
+	h := NewHasher(testConfig())
+
+	ok, _, err := h.Verify("", "anything")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = h.Verify("plaintext", "plaintext")
+	assert.ErrorIs(t, err, ErrUnknownHash)
+
+	_, _, err = h.Verify("$argon2id$v=19$m=x$salt$key", "x")
+	assert.ErrorIs(t, err, ErrUnknownHash)
+}
@@ -0,0 +1,107 @@
+package password
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxHistory: số mật khẩu cũ tối đa được lưu cho mỗi user
+const MaxHistory = 24
+
+var (
+	ErrTooShort       = errors.New("password: too short")
+	ErrTooLong        = errors.New("password: too long")
+	ErrCharacterClass = errors.New("password: missing required character class")
+	ErrBreached       = errors.New("password: found in breached password list")
+	ErrSameAsUsername = errors.New("password: contains username")
+)
+
+// Policy: chính sách mật khẩu cho tạo user, đăng ký, đổi mật khẩu
+type Policy struct {
+	MinLength        int // số ký tự
+	MaxLength        int // số ký tự
+	MaxBytes         int // 0 = không giới hạn; bcrypt chỉ dùng 72 byte đầu => đặt 72
+	RequireUpper     bool
+	RequireLower     bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	DisallowUsername bool
+	History          int // không được dùng lại N mật khẩu gần nhất (0 = tắt, tối đa MaxHistory)
+
+	breached map[string]struct{}
+}
+
+func DefaultPolicy() Policy {
+	return Policy{MinLength: 8, MaxLength: 64, DisallowUsername: true, History: 5}
+}
+
+// LoadBreached nạp danh sách mật khẩu đã lộ từ file (mỗi dòng 1 mật khẩu, so sánh không phân biệt hoa thường)
+func (p *Policy) LoadBreached(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	set := make(map[string]struct{})
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	p.breached = set
+	return nil
+}
+
+// Check độ dài và các loại ký tự bắt buộc
+func (p Policy) Check(plain string) error {
+	n := utf8.RuneCountInString(plain)
+	if n < p.MinLength {
+		return ErrTooShort
+	}
+	if (p.MaxLength > 0 && n > p.MaxLength) || (p.MaxBytes > 0 && len(plain) > p.MaxBytes) {
+		return ErrTooLong
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if (p.RequireUpper && !upper) || (p.RequireLower && !lower) || (p.RequireDigit && !digit) || (p.RequireSymbol && !symbol) {
+		return ErrCharacterClass
+	}
+	return nil
+}
+
+// Breached: mật khẩu nằm trong danh sách đã lộ
+func (p Policy) Breached(plain string) bool {
+	_, ok := p.breached[strings.ToLower(plain)]
+	return ok
+}
+
+// ContainsUsername: mật khẩu chứa username (không phân biệt hoa thường)
+func (p Policy) ContainsUsername(plain, username string) bool {
+	return p.DisallowUsername && username != "" && strings.Contains(strings.ToLower(plain), strings.ToLower(username))
+}
+
+// HistoryDepth: số mật khẩu cũ cần kiểm tra (đã giới hạn trong [0, MaxHistory])
+func (p Policy) HistoryDepth() int {
+	return min(max(p.History, 0), MaxHistory)
+}
@@ -0,0 +1,54 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	p := Policy{MinLength: 8, MaxLength: 16, MaxBytes: 16, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+	cases := []struct {
+		password string
+		want     error
+	}{
+		{"Ab1!", ErrTooShort},
+		{"Abcdefgh1!abcdefg", ErrTooLong},
+		{"Ábcdéfgh1!ééé", ErrTooLong}, // 13 ký tự nhưng > 16 byte
+		{"abcdefgh1!", ErrCharacterClass},
+		{"Abcdefgh!!", ErrCharacterClass},
+		{"Abcdefgh12", ErrCharacterClass},
+		{"Abcdefgh1!", nil},
+		{"Mật khẩu 1", nil}, // khoảng trắng được tính là ký tự đặc biệt
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, p.Check(c.password), c.password)
+	}
+
+	// Mặc định: chỉ giới hạn độ dài
+	assert.NoError(t, DefaultPolicy().Check("secret.123"))
+}
+
+func TestPolicy_BreachedAndUsername(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# top passwords\nPassword1\n\nqwerty123\n"), 0o600))
+
+	p := DefaultPolicy()
+	assert.False(t, p.Breached("password1")) // chưa nạp danh sách
+	require.NoError(t, p.LoadBreached(path))
+	assert.True(t, p.Breached("password1"))
+	assert.True(t, p.Breached("QWERTY123"))
+	assert.False(t, p.Breached("# top passwords"))
+	assert.Error(t, p.LoadBreached(filepath.Join(t.TempDir(), "missing.txt")))
+
+	assert.True(t, p.ContainsUsername("Alice2024!", "alice"))
+	assert.False(t, p.ContainsUsername("secret.123", "alice"))
+	p.DisallowUsername = false
+	assert.False(t, p.ContainsUsername("Alice2024!", "alice"))
+
+	p.History = 100
+	assert.Equal(t, MaxHistory, p.HistoryDepth())
+}
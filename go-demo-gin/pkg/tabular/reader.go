@@ -0,0 +1,109 @@
+// Package tabular: đọc/ghi dữ liệu dạng bảng (CSV, XLSX) cho import/export.
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	CSV   Format = "csv"
+	XLSX  Format = "xlsx"
+	JSONL Format = "jsonl" // JSON Lines: mỗi dòng 1 object (chỉ hỗ trợ ghi)
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("tabular: unsupported format")
+	ErrTooManyRows       = errors.New("tabular: too many rows")
+)
+
+// FormatFromFilename: định dạng theo phần mở rộng của file (.csv, .xlsx)
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ReadAll đọc toàn bộ dòng (kể cả header) của file CSV hoặc sheet đầu tiên của file XLSX.
+// maxRows > 0 giới hạn số dòng dữ liệu (không tính header). Dòng trống bị bỏ qua.
+func ReadAll(r io.Reader, format Format, maxRows int) ([][]string, error) {
+	var rows [][]string
+	add := func(row []string) error {
+		if isBlank(row) {
+			return nil
+		}
+		if maxRows > 0 && len(rows) > maxRows { // rows[0] là header
+			return ErrTooManyRows
+		}
+		rows = append(rows, row)
+		return nil
+	}
+
+	switch format {
+	case CSV:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		// Excel thường lưu CSV kèm BOM UTF-8
+		cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		cr.FieldsPerRecord = -1 // cho phép dòng thiếu cột
+		cr.TrimLeadingSpace = true
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := add(row); err != nil {
+				return nil, err
+			}
+		}
+	case XLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		it, err := f.Rows(f.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+		defer it.Close()
+		for it.Next() {
+			row, err := it.Columns()
+			if err != nil {
+				return nil, err
+			}
+			if err := add(row); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	return rows, nil
+}
+
+func isBlank(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
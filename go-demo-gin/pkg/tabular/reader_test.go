@@ -0,0 +1,29 @@
+package tabular
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAll_CSV(t *testing.T) {
+	// BOM UTF-8, dòng trống và dòng thiếu cột
+	data := "\xef\xbb\xbfusername,role\n\nalice,staff\nbob\n"
+	rows, err := ReadAll(strings.NewReader(data), CSV, 0)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"username", "role"}, {"alice", "staff"}, {"bob"}}, rows)
+
+	_, err = ReadAll(strings.NewReader(data), CSV, 1)
+	assert.ErrorIs(t, err, ErrTooManyRows)
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	f, err := FormatFromFilename("Users.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, XLSX, f)
+
+	_, err = FormatFromFilename("users.xls")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
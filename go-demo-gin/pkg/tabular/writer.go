@@ -0,0 +1,172 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer ghi từng dòng dữ liệu; Close phải được gọi để ghi phần còn lại (XLSX chỉ ghi ra w khi Close)
+type Writer interface {
+	WriteRow(values []any) error
+	Flush() error // đẩy các dòng đã ghi ra w (XLSX: không làm gì)
+	Close() error
+}
+
+// ContentType của từng định dạng (dùng cho header HTTP)
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case JSONL:
+		return "application/x-ndjson"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// NewWriter tạo writer cho format. header là tiêu đề cột (CSV/XLSX, đã dịch);
+// keys là tên field của mỗi object JSON Lines (cùng thứ tự với values).
+func NewWriter(w io.Writer, format Format, header, keys []string) (Writer, error) {
+	switch format {
+	case CSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case JSONL:
+		return &jsonlWriter{w: w, keys: keys}, nil
+	case XLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(f.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+		xw := &xlsxWriter{w: w, f: f, sw: sw}
+		cells := make([]any, len(header))
+		for i, h := range header {
+			cells[i] = h
+		}
+		if err := xw.WriteRow(cells); err != nil {
+			return nil, err
+		}
+		return xw, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			v = escapeFormula(s) // số âm vẫn giữ nguyên
+		}
+		record[i] = cellString(v)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error { return c.Flush() }
+
+type jsonlWriter struct {
+	w    io.Writer
+	keys []string
+	buf  bytes.Buffer
+}
+
+// Ghi object theo đúng thứ tự keys (map của encoding/json sẽ sắp xếp lại key)
+func (j *jsonlWriter) WriteRow(values []any) error {
+	j.buf.Reset()
+	j.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(j.keys[i])
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.buf.Write(key)
+		j.buf.WriteByte(':')
+		j.buf.Write(val)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonlWriter) Flush() error { return nil }
+
+func (j *jsonlWriter) Close() error { return nil }
+
+type xlsxWriter struct {
+	w   io.Writer
+	f   *excelize.File
+	sw  *excelize.StreamWriter
+	row int
+}
+
+func (x *xlsxWriter) WriteRow(values []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	cells := make([]any, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			v = escapeFormula(s)
+		}
+		cells[i] = v
+	}
+	return x.sw.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Flush() error { return nil }
+
+// StreamWriter lưu dòng vào file tạm; file .xlsx (zip) chỉ được ghi ra khi Close
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+	if err := x.sw.Flush(); err != nil {
+		return err
+	}
+	return x.f.Write(x.w)
+}
+
+func cellString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// Ký tự đầu khiến Excel/LibreOffice hiểu ô là công thức (CSV/formula injection)
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula thêm dấu ' trước chuỗi bắt đầu bằng ký tự công thức để ô luôn được hiển thị là văn bản
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
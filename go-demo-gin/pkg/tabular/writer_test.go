@@ -0,0 +1,59 @@
+package tabular
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAll(t *testing.T, format Format) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, []string{"ID", "Tên"}, []string{"id", "name"})
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]any{uint(1), `a,"b"`}))
+	require.NoError(t, w.WriteRow([]any{uint(2), ""}))
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestNewWriter_CSVAndJSONL(t *testing.T) {
+	assert.Equal(t, "ID,Tên\n1,\"a,\"\"b\"\"\"\n2,\n", string(writeAll(t, CSV)))
+	// Giữ đúng thứ tự keys
+	assert.Equal(t, "{\"id\":1,\"name\":\"a,\\\"b\\\"\"}\n{\"id\":2,\"name\":\"\"}\n", string(writeAll(t, JSONL)))
+
+	_, err := NewWriter(&bytes.Buffer{}, Format("xml"), nil, nil)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestNewWriter_XLSXRoundTrip(t *testing.T) {
+	rows, err := ReadAll(bytes.NewReader(writeAll(t, XLSX)), XLSX, 0)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"ID", "Tên"}, {"1", `a,"b"`}, {"2"}}, rows)
+}
+
+func TestNewWriter_EscapesFormulaCells(t *testing.T) {
+	values := []any{"=HYPERLINK(\"http://x\")", "+1", "-2", "@SUM(A1)", "\tcmd", "ok", -3}
+	write := func(format Format) []byte {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, format, []string{"a", "b", "c", "d", "e", "f", "g"}, []string{"a", "b", "c", "d", "e", "f", "g"})
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRow(values))
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	want := []string{"'=HYPERLINK(\"http://x\")", "'+1", "'-2", "'@SUM(A1)", "'\tcmd", "ok", "-3"}
+	rows, err := ReadAll(bytes.NewReader(write(CSV)), CSV, 0)
+	require.NoError(t, err)
+	assert.Equal(t, want, rows[1])
+
+	rows, err = ReadAll(bytes.NewReader(write(XLSX)), XLSX, 0)
+	require.NoError(t, err)
+	assert.Equal(t, want, rows[1])
+
+	// JSON Lines không bị mở như bảng tính => giữ nguyên giá trị
+	assert.Contains(t, string(write(JSONL)), `"a":"=HYPERLINK(\"http://x\")"`)
+}
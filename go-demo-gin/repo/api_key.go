@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormAPIKeyRepo struct{ db *gorm.DB }
+
+func NewGormAPIKeyRepo(db *gorm.DB) *GormAPIKeyRepo { return &GormAPIKeyRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormAPIKeyRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormAPIKeyRepo) Create(ctx context.Context, k *models.APIKey) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(k).Error
+}
+
+func (r *GormAPIKeyRepo) FindByID(ctx context.Context, id uint) (*models.APIKey, error) {
+	var k models.APIKey
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&k, id).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *GormAPIKeyRepo) FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var k models.APIKey
+	if err := r.dbFrom(ctx).WithContext(ctx).Where("prefix = ?", prefix).First(&k).Error; err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// ListByUser: key của 1 user (kể cả đã thu hồi/hết hạn, để xem lịch sử)
+func (r *GormAPIKeyRepo) ListByUser(ctx context.Context, userID uint, pag *pkg.Pagination) ([]models.APIKey, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.APIKey{}).Where("user_id = ?", userID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var keys []models.APIKey
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&keys).Error; err != nil {
+		return nil, 0, err
+	}
+	return keys, total, nil
+}
+
+// Revoke chỉ thu hồi key chưa bị thu hồi (giữ nguyên thời điểm thu hồi đầu tiên)
+func (r *GormAPIKeyRepo) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// TouchLastUsed ghi thời điểm dùng gần nhất, bỏ qua nếu lần ghi trước mới hơn notAfter
+// (tránh mỗi request đều UPDATE)
+func (r *GormAPIKeyRepo) TouchLastUsed(ctx context.Context, id uint, at, notAfter time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at <= ?)", id, notAfter).
+		Update("last_used_at", at).Error
+}
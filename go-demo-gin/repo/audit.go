@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	auditRequest "go-demo-gin/requests/audit"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormAuditRepo struct{ db *gorm.DB }
+
+func NewGormAuditRepo(db *gorm.DB) *GormAuditRepo { return &GormAuditRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormAuditRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormAuditRepo) Create(ctx context.Context, e *models.AuditEvent) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(e).Error
+}
+
+func (r *GormAuditRepo) List(ctx context.Context, pag *pkg.Pagination, f *auditRequest.AuditFilter) ([]models.AuditEvent, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.AuditEvent{})
+	if f.ActorID != 0 {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if f.ImpersonatorID != 0 {
+		q = q.Where("impersonator_id = ?", f.ImpersonatorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID != 0 {
+		q = q.Where("target_id = ?", f.TargetID)
+	}
+	if !f.From.IsZero() {
+		q = q.Where("created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		q = q.Where("created_at < ?", f.To)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var events []models.AuditEvent
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"go-demo-gin/cache"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+// Tên cache dùng cho metrics (hit ratio)
+const userCacheName = "users"
+
+type userRepository interface {
+	Create(ctx context.Context, u *models.User) error
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	Update(ctx context.Context, u *models.User) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, pag *pkg.Pagination, search string) ([]models.User, int64, error)
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	FindCredentials(ctx context.Context, username string) (*models.User, error)
+	Stream(ctx context.Context, search, sort string, batchSize int, fn func([]models.User) error) error
+	FindDeletedByID(ctx context.Context, id uint) (*models.User, error)
+	Restore(ctx context.Context, id uint) error
+	UpdateStatus(ctx context.Context, u *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	MarkVerificationSent(ctx context.Context, id uint, at, notAfter time.Time) (bool, error)
+	UpdatePasswordHash(ctx context.Context, u *models.User) error
+	AddPasswordHistory(ctx context.Context, userID uint, hash string, keep int) error
+}
+
+// CachedUserRepo bọc một user repository và cache kết quả FindByID/FindByUsername.
+// Đọc trong transaction luôn đi thẳng xuống DB để thấy dữ liệu chưa commit.
+type CachedUserRepo struct {
+	inner userRepository
+	cache cache.Cache
+	ttl   time.Duration
+	group singleflight.Group
+}
+
+func NewCachedUserRepo(inner userRepository, c cache.Cache, ttl time.Duration) *CachedUserRepo {
+	return &CachedUserRepo{inner: inner, cache: c, ttl: ttl}
+}
+
+func (r *CachedUserRepo) Create(ctx context.Context, u *models.User) error {
+	return r.inner.Create(ctx, u)
+}
+
+// ID là duy nhất toàn hệ thống => cache không phụ thuộc tenant, kiểm tra tenant sau khi đọc
+func (r *CachedUserRepo) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	u, err := r.find(ctx, userIDKey(id), func(ctx context.Context) (*models.User, error) {
+		return r.inner.FindByID(utils.AllTenants(ctx), id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tenantID, ok := utils.TenantIDFrom(ctx); ok && u.TenantID != tenantID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return u, nil
+}
+
+// Username chỉ duy nhất trong tenant => chỉ cache khi đã biết tenant
+func (r *CachedUserRepo) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	tenantID, ok := utils.TenantIDFrom(ctx)
+	if !ok {
+		return r.inner.FindByUsername(ctx, username)
+	}
+	return r.find(ctx, usernameKey(tenantID, username), func(ctx context.Context) (*models.User, error) {
+		return r.inner.FindByUsername(ctx, username)
+	})
+}
+
+// Hash mật khẩu không nằm trong cache => đăng nhập luôn đọc thẳng DB
+func (r *CachedUserRepo) FindCredentials(ctx context.Context, username string) (*models.User, error) {
+	return r.inner.FindCredentials(ctx, username)
+}
+
+func (r *CachedUserRepo) Update(ctx context.Context, u *models.User) error {
+	if err := r.inner.Update(ctx, u); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, u)
+	return nil
+}
+
+func (r *CachedUserRepo) Delete(ctx context.Context, id uint) error {
+	if err := r.inner.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, &models.User{}, id)
+	return nil
+}
+
+func (r *CachedUserRepo) List(ctx context.Context, pag *pkg.Pagination, search string) ([]models.User, int64, error) {
+	return r.inner.List(ctx, pag, search)
+}
+
+func (r *CachedUserRepo) Stream(ctx context.Context, search, sort string, batchSize int, fn func([]models.User) error) error {
+	return r.inner.Stream(ctx, search, sort, batchSize, fn)
+}
+
+func (r *CachedUserRepo) UpdateStatus(ctx context.Context, u *models.User) error {
+	if err := r.inner.UpdateStatus(ctx, u); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, u)
+	return nil
+}
+
+func (r *CachedUserRepo) UpdatePasswordHash(ctx context.Context, u *models.User) error {
+	if err := r.inner.UpdatePasswordHash(ctx, u); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, u)
+	return nil
+}
+
+// Lịch sử mật khẩu không nằm trong bản cache của user
+func (r *CachedUserRepo) AddPasswordHistory(ctx context.Context, userID uint, hash string, keep int) error {
+	return r.inner.AddPasswordHistory(ctx, userID, hash, keep)
+}
+
+// Tra cứu theo email ít dùng (đăng ký, gửi lại xác thực) => không cache
+func (r *CachedUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.inner.FindByEmail(ctx, email)
+}
+
+func (r *CachedUserRepo) MarkVerificationSent(ctx context.Context, id uint, at, notAfter time.Time) (bool, error) {
+	ok, err := r.inner.MarkVerificationSent(ctx, id, at, notAfter)
+	if ok {
+		r.Invalidate(ctx, &models.User{}, id)
+	}
+	return ok, err
+}
+
+// User đã xoá không được cache
+func (r *CachedUserRepo) FindDeletedByID(ctx context.Context, id uint) (*models.User, error) {
+	return r.inner.FindDeletedByID(ctx, id)
+}
+
+func (r *CachedUserRepo) Restore(ctx context.Context, id uint) error {
+	if err := r.inner.Restore(ctx, id); err != nil {
+		return err
+	}
+	r.Invalidate(ctx, &models.User{}, id)
+	return nil
+}
+
+// Invalidate xoá cache của user (theo ID và username); ids bổ sung dùng khi chỉ biết ID.
+// UserService gọi lại sau khi transaction commit để tránh request khác nạp lại dữ liệu cũ.
+func (r *CachedUserRepo) Invalidate(ctx context.Context, u *models.User, ids ...uint) {
+	keys := make([]string, 0, 2+len(ids))
+	if u.ID != 0 {
+		keys = append(keys, userIDKey(u.ID))
+	}
+	if u.Username != "" {
+		keys = append(keys, usernameKey(u.TenantID, u.Username))
+	}
+	for _, id := range ids {
+		keys = append(keys, userIDKey(id))
+	}
+	if err := r.cache.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "User cache invalidation failed: "+err.Error(), nil)
+	}
+}
+
+func (r *CachedUserRepo) find(ctx context.Context, key string, load func(ctx context.Context) (*models.User, error)) (*models.User, error) {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return load(ctx)
+	}
+
+	if data, ok, err := r.cache.Get(ctx, key); err == nil && ok {
+		var u models.User
+		if err := json.Unmarshal(data, &u); err == nil {
+			cache.RecordHit(userCacheName)
+			return &u, nil
+		}
+	}
+	cache.RecordMiss(userCacheName)
+
+	// Nhiều request cùng miss một key => chỉ một truy vấn xuống DB.
+	// Request đầu bị huỷ không được làm hỏng kết quả của các request đang chờ chung => bỏ cancel của ctx
+	v, err, _ := r.group.Do(key, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		u, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Không lưu hash mật khẩu vào cache (Redis có thể dùng chung với hệ thống khác)
+		u.Password = ""
+		if data, err := json.Marshal(u); err == nil {
+			if err := r.cache.Set(ctx, key, data, r.ttl); err != nil {
+				utils.LogCtx(ctx, logrus.WarnLevel, "User cache write failed: "+err.Error(), nil)
+			}
+		}
+		return u, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Trả bản sao để các caller dùng chung kết quả không sửa lẫn nhau
+	u := *v.(*models.User)
+	return &u, nil
+}
+
+func userIDKey(id uint) string { return "user:id:" + strconv.FormatUint(uint64(id), 10) }
+
+func usernameKey(tenantID uint, username string) string {
+	return "user:username:" + strconv.FormatUint(uint64(tenantID), 10) + ":" + username
+}
@@ -0,0 +1,171 @@
+package repo
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-demo-gin/cache"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Đếm số lần truy vấn xuống repo gốc
+type countingUserRepo struct {
+	*GormUserRepo
+	finds atomic.Int32
+	gate  chan struct{} // nếu khác nil: FindByID chờ tới khi gate đóng
+}
+
+func (r *countingUserRepo) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	r.finds.Add(1)
+	if r.gate != nil {
+		<-r.gate
+	}
+	return r.GormUserRepo.FindByID(ctx, id)
+}
+
+func setupCachedUserRepo(t *testing.T) (*gorm.DB, *countingUserRepo, *CachedUserRepo, *models.User) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite memory: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	u := &models.User{Username: "alice", Password: "x", Role: models.RoleStaff}
+	if err := db.Create(u).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	inner := &countingUserRepo{GormUserRepo: NewGormUserRepo(db)}
+	return db, inner, NewCachedUserRepo(inner, cache.NewLRU(100), time.Minute), u
+}
+
+func TestCachedUserRepo_FindByID_HitsCache(t *testing.T) {
+	ctx := context.Background()
+	_, inner, r, u := setupCachedUserRepo(t)
+
+	for range 3 {
+		got, err := r.FindByID(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", got.Username)
+	}
+	assert.Equal(t, int32(1), inner.finds.Load())
+}
+
+func TestCachedUserRepo_ConcurrentMissesAreDeduplicated(t *testing.T) {
+	ctx := context.Background()
+	_, inner, r, u := setupCachedUserRepo(t)
+	inner.gate = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := r.FindByID(ctx, u.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, u.ID, got.ID)
+		}()
+	}
+	// Đợi request đầu tiên vào tới DB rồi mới mở gate
+	assert.Eventually(t, func() bool { return inner.finds.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(inner.gate)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inner.finds.Load())
+}
+
+func TestCachedUserRepo_UpdateAndDeleteInvalidate(t *testing.T) {
+	ctx := context.Background()
+	_, _, r, u := setupCachedUserRepo(t)
+
+	r.FindByUsername(ctx, "alice")
+	r.FindByID(ctx, u.ID)
+
+	assert.NoError(t, r.Update(ctx, &models.User{Model: gorm.Model{ID: u.ID}, Username: "alice", Role: models.RoleAdmin}))
+	got, err := r.FindByUsername(ctx, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, got.Role)
+
+	assert.NoError(t, r.Delete(ctx, u.ID))
+	_, err = r.FindByID(ctx, u.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCachedUserRepo_BypassesCacheInTransaction(t *testing.T) {
+	ctx := context.Background()
+	db, inner, r, u := setupCachedUserRepo(t)
+
+	r.FindByID(ctx, u.ID)
+	db.Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		r.FindByID(ctxTx, u.ID)
+		r.FindByID(ctxTx, u.ID)
+		return nil
+	})
+	assert.Equal(t, int32(3), inner.finds.Load())
+}
+
+func TestCachedUserRepo_DoesNotCachePasswordHash(t *testing.T) {
+	ctx := context.Background()
+	_, _, r, u := setupCachedUserRepo(t)
+
+	miss, err := r.FindByID(ctx, u.ID)
+	assert.NoError(t, err)
+	hit, err := r.FindByID(ctx, u.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, miss.Password)
+	assert.Empty(t, hit.Password)
+
+	data, ok, err := r.cache.Get(ctx, userIDKey(u.ID))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NotContains(t, string(data), `"Password":"x"`)
+
+	// Đăng nhập đọc thẳng DB nên vẫn có hash
+	creds, err := r.FindCredentials(ctx, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", creds.Password)
+}
+
+func TestCachedUserRepo_CancelledLeaderDoesNotFailWaiters(t *testing.T) {
+	_, inner, r, u := setupCachedUserRepo(t)
+	inner.gate = make(chan struct{})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan struct{})
+	go func() {
+		defer close(leaderDone)
+		r.FindByID(leaderCtx, u.ID)
+	}()
+	assert.Eventually(t, func() bool { return inner.finds.Load() == 1 }, time.Second, time.Millisecond)
+
+	var got *models.User
+	var err error
+	waiterDone := make(chan struct{})
+	go func() {
+		defer close(waiterDone)
+		got, err = r.FindByID(context.Background(), u.ID)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// Request đầu bị huỷ khi truy vấn đang chạy => request chờ chung vẫn nhận được kết quả
+	cancel()
+	close(inner.gate)
+	<-leaderDone
+	<-waiterDone
+
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, got.ID)
+	assert.Equal(t, int32(1), inner.finds.Load())
+}
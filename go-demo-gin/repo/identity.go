@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormIdentityRepo struct{ db *gorm.DB }
+
+func NewGormIdentityRepo(db *gorm.DB) *GormIdentityRepo { return &GormIdentityRepo{db: db} }
+
+func (r *GormIdentityRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormIdentityRepo) FindBySubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error) {
+	var i models.UserIdentity
+	if err := r.dbFrom(ctx).WithContext(ctx).
+		Where("provider = ? AND subject = ?", provider, subject).
+		First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *GormIdentityRepo) Create(ctx context.Context, i *models.UserIdentity) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(i).Error
+}
+
+func (r *GormIdentityRepo) TouchLogin(ctx context.Context, id uint, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.UserIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormInvitationRepo struct{ db *gorm.DB }
+
+func NewGormInvitationRepo(db *gorm.DB) *GormInvitationRepo { return &GormInvitationRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormInvitationRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormInvitationRepo) Create(ctx context.Context, inv *models.Invitation) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(inv).Error
+}
+
+func (r *GormInvitationRepo) FindByID(ctx context.Context, id uint) (*models.Invitation, error) {
+	var inv models.Invitation
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&inv, id).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *GormInvitationRepo) FindByTokenHash(ctx context.Context, hash string) (*models.Invitation, error) {
+	var inv models.Invitation
+	if err := r.dbFrom(ctx).WithContext(ctx).Where("token_hash = ?", hash).First(&inv).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListPending: lời mời chưa chấp nhận/thu hồi và còn hạn của 1 organization
+func (r *GormInvitationRepo) ListPending(ctx context.Context, orgID uint, now time.Time, pag *pkg.Pagination) ([]models.Invitation, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.Invitation{}).
+		Where("organization_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", orgID, now)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var list []models.Invitation
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// MarkAccepted chỉ đánh dấu lời mời chưa dùng; false => đã bị dùng/thu hồi (2 request chấp nhận cùng lúc)
+func (r *GormInvitationRepo) MarkAccepted(ctx context.Context, id, userID uint, at time.Time) (bool, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]any{"accepted_at": at, "accepted_by_id": userID})
+	return res.RowsAffected == 1, res.Error
+}
+
+// Revoke chỉ thu hồi lời mời chưa dùng (giữ nguyên thời điểm thu hồi đầu tiên)
+func (r *GormInvitationRepo) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormJobRepo struct{ db *gorm.DB }
+
+func NewGormJobRepo(db *gorm.DB) *GormJobRepo { return &GormJobRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormJobRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// Enqueue thêm job; job có UniqueKey đã tồn tại thì bỏ qua và trả về false
+func (r *GormJobRepo) Enqueue(ctx context.Context, j *models.Job) (bool, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(j)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *GormJobRepo) FindByID(ctx context.Context, id uint) (*models.Job, error) {
+	var j models.Job
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&j, id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Claim nhận tối đa limit job đến hạn (hoặc job running đã hết lease) cho worker.
+// Postgres: SELECT ... FOR UPDATE SKIP LOCKED nên nhiều instance không chờ/nhận trùng nhau.
+// SQLite (không có SKIP LOCKED): cập nhật có điều kiện theo attempts, bản ghi đã bị nhận thì bỏ qua.
+func (r *GormJobRepo) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration, worker string) ([]models.Job, error) {
+	var claimed []models.Job
+	err := r.dbFrom(ctx).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)",
+			models.JobQueued, now, models.JobRunning, now).
+			Order("run_at asc, id asc").Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			q = q.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		var candidates []models.Job
+		if err := q.Find(&candidates).Error; err != nil {
+			return err
+		}
+
+		until := now.Add(lease)
+		for _, j := range candidates {
+			res := tx.Model(&models.Job{}).
+				Where("id = ? AND status = ? AND attempts = ?", j.ID, j.Status, j.Attempts).
+				Updates(map[string]any{
+					"status":       models.JobRunning,
+					"attempts":     j.Attempts + 1,
+					"locked_by":    worker,
+					"locked_until": until,
+					"started_at":   now,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 1 {
+				j.Status = models.JobRunning
+				j.Attempts++
+				j.LockedBy = worker
+				j.LockedUntil = &until
+				j.StartedAt = &now
+				claimed = append(claimed, j)
+			}
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+// Các hàm đánh dấu chỉ cập nhật khi job vẫn thuộc lần chạy này (status running + attempts),
+// tránh worker cũ (đã hết lease) ghi đè kết quả của worker đã nhận lại job.
+func (r *GormJobRepo) finish(ctx context.Context, j *models.Job, values map[string]any) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", j.ID, models.JobRunning, j.Attempts).
+		Updates(values).Error
+}
+
+func (r *GormJobRepo) Complete(ctx context.Context, j *models.Job, result string, at time.Time) error {
+	return r.finish(ctx, j, map[string]any{
+		"status":       models.JobSucceeded,
+		"result":       result,
+		"last_error":   "",
+		"locked_until": nil,
+		"finished_at":  at,
+	})
+}
+
+func (r *GormJobRepo) Retry(ctx context.Context, j *models.Job, next time.Time, lastErr string) error {
+	return r.finish(ctx, j, map[string]any{
+		"status":       models.JobQueued,
+		"run_at":       next,
+		"last_error":   lastErr,
+		"locked_until": nil,
+	})
+}
+
+func (r *GormJobRepo) Fail(ctx context.Context, j *models.Job, lastErr string, at time.Time) error {
+	return r.finish(ctx, j, map[string]any{
+		"status":       models.JobFailed,
+		"last_error":   lastErr,
+		"locked_until": nil,
+		"finished_at":  at,
+	})
+}
+
+// Release trả job về hàng đợi khi worker dừng giữa chừng (shutdown), không tính là một lần thử
+func (r *GormJobRepo) Release(ctx context.Context, j *models.Job) error {
+	return r.finish(ctx, j, map[string]any{
+		"status":       models.JobQueued,
+		"attempts":     j.Attempts - 1,
+		"locked_until": nil,
+	})
+}
+
+// PurgeFinished xoá các job đã thành công trước thời điểm before (job failed được giữ lại để điều tra)
+func (r *GormJobRepo) PurgeFinished(ctx context.Context, before time.Time) (int64, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).
+		Where("status = ? AND finished_at < ?", models.JobSucceeded, before).
+		Delete(&models.Job{})
+	return res.RowsAffected, res.Error
+}
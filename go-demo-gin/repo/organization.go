@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormOrganizationRepo struct{ db *gorm.DB }
+
+func NewGormOrganizationRepo(db *gorm.DB) *GormOrganizationRepo {
+	return &GormOrganizationRepo{db: db}
+}
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormOrganizationRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormOrganizationRepo) Create(ctx context.Context, o *models.Organization) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(o).Error
+}
+
+func (r *GormOrganizationRepo) FindByID(ctx context.Context, id uint) (*models.Organization, error) {
+	var o models.Organization
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&o, id).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *GormOrganizationRepo) Update(ctx context.Context, o *models.Organization) error {
+	return r.dbFrom(ctx).WithContext(ctx).Updates(o).Error
+}
+
+// Delete xoá organization cùng team, thành viên và lời mời; nên gọi trong transaction
+func (r *GormOrganizationRepo) Delete(ctx context.Context, id uint) error {
+	db := r.dbFrom(ctx).WithContext(ctx)
+	teams := db.Session(&gorm.Session{NewDB: true}).Model(&models.Team{}).Select("id").Where("organization_id = ?", id)
+	if err := db.Where("team_id IN (?)", teams).Delete(&models.TeamMember{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("organization_id = ?", id).Delete(&models.Team{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("organization_id = ?", id).Delete(&models.Invitation{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(&models.Organization{}, id).Error
+}
+
+// List: mọi organization (trong tenant); userID khác 0 => chỉ các organization mà user là thành viên
+func (r *GormOrganizationRepo) List(ctx context.Context, userID uint, pag *pkg.Pagination) ([]models.Organization, int64, error) {
+	db := r.dbFrom(ctx).WithContext(ctx)
+	q := db.Model(&models.Organization{})
+	if userID != 0 {
+		mine := db.Session(&gorm.Session{NewDB: true}).Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = ?", userID)
+		q = q.Where("id IN (?)", mine)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var orgs []models.Organization
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&orgs).Error; err != nil {
+		return nil, 0, err
+	}
+	return orgs, total, nil
+}
+
+func (r *GormOrganizationRepo) AddMember(ctx context.Context, m *models.OrganizationMember) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(m).Error
+}
+
+func (r *GormOrganizationRepo) FindMember(ctx context.Context, orgID, userID uint) (*models.OrganizationMember, error) {
+	var m models.OrganizationMember
+	if err := r.dbFrom(ctx).WithContext(ctx).Preload("User").
+		Where("organization_id = ? AND user_id = ?", orgID, userID).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// MemberRoles: quyền của user trong từng organization (organization không tham gia => không có trong map)
+func (r *GormOrganizationRepo) MemberRoles(ctx context.Context, userID uint, orgIDs []uint) (map[uint]models.MembershipRole, error) {
+	var members []models.OrganizationMember
+	if err := r.dbFrom(ctx).WithContext(ctx).
+		Where("user_id = ? AND organization_id IN ?", userID, orgIDs).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	roles := make(map[uint]models.MembershipRole, len(members))
+	for _, m := range members {
+		roles[m.OrganizationID] = m.Role
+	}
+	return roles, nil
+}
+
+func (r *GormOrganizationRepo) UpdateMemberRole(ctx context.Context, orgID, userID uint, role models.MembershipRole) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Update("role", role).Error
+}
+
+// RemoveMember xoá thành viên khỏi organization và mọi team của organization; nên gọi trong transaction
+func (r *GormOrganizationRepo) RemoveMember(ctx context.Context, orgID, userID uint) error {
+	db := r.dbFrom(ctx).WithContext(ctx)
+	teams := db.Session(&gorm.Session{NewDB: true}).Model(&models.Team{}).Select("id").Where("organization_id = ?", orgID)
+	if err := db.Where("user_id = ? AND team_id IN (?)", userID, teams).Delete(&models.TeamMember{}).Error; err != nil {
+		return err
+	}
+	return db.Where("organization_id = ? AND user_id = ?", orgID, userID).Delete(&models.OrganizationMember{}).Error
+}
+
+func (r *GormOrganizationRepo) ListMembers(ctx context.Context, orgID uint, pag *pkg.Pagination) ([]models.OrganizationMember, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.OrganizationMember{}).Where("organization_id = ?", orgID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var members []models.OrganizationMember
+	if err := q.Scopes(utils.Paginate(pag, q)).Preload("User").Find(&members).Error; err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
+
+// CountOwners khoá các dòng owner (SELECT ... FOR UPDATE, gọi trong transaction) rồi mới đếm:
+// 2 owner hạ quyền/xoá nhau cùng lúc => request sau chờ request trước commit và thấy số owner mới.
+// COUNT không khoá được dòng (Postgres không cho FOR UPDATE với hàm tổng hợp). SQLite bỏ qua mệnh đề khoá.
+func (r *GormOrganizationRepo) CountOwners(ctx context.Context, orgID uint) (int64, error) {
+	var ids []uint
+	err := r.dbFrom(ctx).WithContext(ctx).Model(&models.OrganizationMember{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", orgID, models.MemberOwner).Pluck("id", &ids).Error
+	return int64(len(ids)), err
+}
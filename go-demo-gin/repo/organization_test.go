@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// CountOwners phải khoá các dòng owner (FOR UPDATE) để 2 owner không cùng hạ quyền nhau
+func TestCountOwners_LocksOwnerRows(t *testing.T) {
+	// DryRun: chỉ sinh SQL theo dialect Postgres, không cần kết nối
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(db *gorm.DB) {
+		sql = db.Statement.SQL.String()
+	}))
+
+	_, err = NewGormOrganizationRepo(db).CountOwners(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "organization_members")
+	assert.Contains(t, sql, "FOR UPDATE")
+}
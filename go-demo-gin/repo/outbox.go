@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormOutboxRepo struct{ db *gorm.DB }
+
+func NewGormOutboxRepo(db *gorm.DB) *GormOutboxRepo { return &GormOutboxRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormOutboxRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormOutboxRepo) Create(ctx context.Context, e *models.OutboxEvent) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(e).Error
+}
+
+// ClaimDue lấy các event đến hạn và "giữ chỗ" bằng cách đẩy next_attempt_at thêm lease.
+// Cập nhật có điều kiện (optimistic) nên chạy được cả Postgres lẫn SQLite và
+// nhiều instance không nhận trùng cùng một event trong thời gian lease.
+func (r *GormOutboxRepo) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	db := r.dbFrom(ctx).WithContext(ctx)
+
+	var candidates []models.OutboxEvent
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+		Order("id asc").Limit(limit).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.OutboxEvent, 0, len(candidates))
+	for _, e := range candidates {
+		res := db.Model(&models.OutboxEvent{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", e.ID, models.OutboxPending, e.NextAttemptAt).
+			Update("next_attempt_at", now.Add(lease))
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			claimed = append(claimed, e)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *GormOutboxRepo) MarkDelivered(ctx context.Context, id uint, sinks string, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          models.OutboxDelivered,
+			"delivered_sinks": sinks,
+			"delivered_at":    at,
+			"last_error":      "",
+		}).Error
+}
+
+func (r *GormOutboxRepo) MarkFailed(ctx context.Context, id uint, attempts int, sinks string, next time.Time, lastErr string) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":        attempts,
+			"delivered_sinks": sinks,
+			"next_attempt_at": next,
+			"last_error":      lastErr,
+		}).Error
+}
+
+func (r *GormOutboxRepo) MarkDead(ctx context.Context, id uint, attempts int, sinks string, lastErr string) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"status":          models.OutboxDead,
+			"attempts":        attempts,
+			"delivered_sinks": sinks,
+			"last_error":      lastErr,
+		}).Error
+}
+
+// PurgeDelivered xoá các event đã gửi xong trước thời điểm before (dead-letter được giữ lại để điều tra)
+func (r *GormOutboxRepo) PurgeDelivered(ctx context.Context, before time.Time) (int64, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).
+		Where("status = ? AND delivered_at < ?", models.OutboxDelivered, before).
+		Delete(&models.OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
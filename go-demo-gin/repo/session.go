@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormSessionRepo struct{ db *gorm.DB }
+
+func NewGormSessionRepo(db *gorm.DB) *GormSessionRepo { return &GormSessionRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormSessionRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormSessionRepo) Create(ctx context.Context, s *models.Session) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(s).Error
+}
+
+func (r *GormSessionRepo) FindByID(ctx context.Context, id uint) (*models.Session, error) {
+	var s models.Session
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListActiveByUser: session chưa thu hồi, chưa hết hạn; mới dùng gần nhất lên đầu
+func (r *GormSessionRepo) ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var list []models.Session
+	err := r.dbFrom(ctx).WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at desc, id desc").
+		Find(&list).Error
+	return list, err
+}
+
+// Revoke chỉ thu hồi session chưa bị thu hồi (giữ nguyên thời điểm thu hồi đầu tiên)
+func (r *GormSessionRepo) Revoke(ctx context.Context, id uint, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *GormSessionRepo) TouchLastSeen(ctx context.Context, id uint, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.Session{}).
+		Where("id = ?", id).
+		Update("last_seen_at", at).Error
+}
+
+// Extend: refresh token => session sống theo hạn của token mới
+func (r *GormSessionRepo) Extend(ctx context.Context, id uint, expiresAt, at time.Time) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{"expires_at": expiresAt, "last_seen_at": at}).Error
+}
+
+// PurgeExpired xoá session đã hết hạn hoặc bị thu hồi trước mốc before
+func (r *GormSessionRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).
+		Where("expires_at < ? OR revoked_at < ?", before, before).
+		Delete(&models.Session{})
+	return res.RowsAffected, res.Error
+}
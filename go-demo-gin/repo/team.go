@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormTeamRepo struct{ db *gorm.DB }
+
+func NewGormTeamRepo(db *gorm.DB) *GormTeamRepo { return &GormTeamRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormTeamRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormTeamRepo) Create(ctx context.Context, t *models.Team) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(t).Error
+}
+
+func (r *GormTeamRepo) FindByID(ctx context.Context, id uint) (*models.Team, error) {
+	var t models.Team
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *GormTeamRepo) Update(ctx context.Context, t *models.Team) error {
+	return r.dbFrom(ctx).WithContext(ctx).Updates(t).Error
+}
+
+// Delete xoá team cùng thành viên và lời mời vào team; nên gọi trong transaction
+func (r *GormTeamRepo) Delete(ctx context.Context, id uint) error {
+	db := r.dbFrom(ctx).WithContext(ctx)
+	if err := db.Where("team_id = ?", id).Delete(&models.TeamMember{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("team_id = ?", id).Delete(&models.Invitation{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(&models.Team{}, id).Error
+}
+
+func (r *GormTeamRepo) ListByOrganization(ctx context.Context, orgID uint, pag *pkg.Pagination) ([]models.Team, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.Team{}).Where("organization_id = ?", orgID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var teams []models.Team
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&teams).Error; err != nil {
+		return nil, 0, err
+	}
+	return teams, total, nil
+}
+
+func (r *GormTeamRepo) FindMember(ctx context.Context, teamID, userID uint) (*models.TeamMember, error) {
+	var m models.TeamMember
+	if err := r.dbFrom(ctx).WithContext(ctx).Preload("User").
+		Where("team_id = ? AND user_id = ?", teamID, userID).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// SaveMember thêm thành viên vào team, đã có => đổi quyền
+func (r *GormTeamRepo) SaveMember(ctx context.Context, m *models.TeamMember) error {
+	return r.dbFrom(ctx).WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(m).Error
+}
+
+func (r *GormTeamRepo) RemoveMember(ctx context.Context, teamID, userID uint) error {
+	return r.dbFrom(ctx).WithContext(ctx).Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.TeamMember{}).Error
+}
+
+func (r *GormTeamRepo) ListMembers(ctx context.Context, teamID uint, pag *pkg.Pagination) ([]models.TeamMember, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.TeamMember{}).Where("team_id = ?", teamID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var members []models.TeamMember
+	if err := q.Scopes(utils.Paginate(pag, q)).Preload("User").Find(&members).Error; err != nil {
+		return nil, 0, err
+	}
+	return members, total, nil
+}
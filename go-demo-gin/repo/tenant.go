@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+)
+
+type GormTenantRepo struct{ db *gorm.DB }
+
+func NewGormTenantRepo(db *gorm.DB) *GormTenantRepo { return &GormTenantRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormTenantRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormTenantRepo) Create(ctx context.Context, t *models.Tenant) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(t).Error
+}
+
+func (r *GormTenantRepo) FindByID(ctx context.Context, id uint) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&t, id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *GormTenantRepo) FindBySlug(ctx context.Context, slug string) (*models.Tenant, error) {
+	var t models.Tenant
+	if err := r.dbFrom(ctx).WithContext(ctx).Where("slug = ?", slug).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *GormTenantRepo) List(ctx context.Context, pag *pkg.Pagination) ([]models.Tenant, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.Tenant{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var tenants []models.Tenant
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&tenants).Error; err != nil {
+		return nil, 0, err
+	}
+	return tenants, total, nil
+}
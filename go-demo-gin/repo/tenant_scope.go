@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"reflect"
+
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// RegisterTenantScope gắn callback GORM cho mọi model có trường TenantID: truy vấn/sửa/xoá được
+// thêm điều kiện tenant_id, bản ghi tạo mới được gán tenant_id theo tenant trong context (utils.WithTenant).
+// Context không có tenant (job hệ thống, migrate) hoặc utils.AllTenants => không lọc.
+// SQL viết tay (Raw/Exec) không đi qua các callback này. Gọi lại nhiều lần trên cùng db => bỏ qua.
+func RegisterTenantScope(db *gorm.DB) error {
+	cb := db.Callback()
+	if cb.Query().Get("tenant:query") != nil {
+		return nil
+	}
+	if err := cb.Create().Before("gorm:create").Register("tenant:create", tenantAssign); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:query", tenantWhere); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:row", tenantWhere); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:update", tenantWhere); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("tenant:delete", tenantWhere)
+}
+
+// Trường TenantID của model và tenant của request; ok=false => không cần lọc
+func tenantField(db *gorm.DB) (*schema.Field, uint, bool) {
+	if db.Statement.Schema == nil {
+		return nil, 0, false
+	}
+	field := db.Statement.Schema.LookUpField("TenantID")
+	if field == nil {
+		return nil, 0, false
+	}
+	id, ok := utils.TenantIDFrom(db.Statement.Context)
+	return field, id, ok
+}
+
+func tenantWhere(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: id},
+	}})
+}
+
+// Bản ghi mới luôn thuộc tenant của request (không tin giá trị client gửi lên)
+func tenantAssign(db *gorm.DB) {
+	field, id, ok := tenantField(db)
+	if !ok {
+		return
+	}
+	ctx := db.Statement.Context
+	switch rv := db.Statement.ReflectValue; rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			_ = field.Set(ctx, reflect.Indirect(rv.Index(i)), id)
+		}
+	case reflect.Struct:
+		_ = field.Set(ctx, rv, id)
+	}
+}
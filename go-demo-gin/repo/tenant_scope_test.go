@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func tenantCtx(id uint) context.Context {
+	return utils.WithTenant(context.Background(), utils.TenantScope{ID: id})
+}
+
+func setupTenantDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, RegisterTenantScope(db))
+	// Gọi lại không lỗi, không đăng ký trùng
+	require.NoError(t, RegisterTenantScope(db))
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+	return db
+}
+
+func TestTenantScope_AssignsAndIsolates(t *testing.T) {
+	db := setupTenantDB(t)
+	ur := NewGormUserRepo(db)
+
+	// Tenant lấy từ context, giá trị client gửi lên bị ghi đè
+	alice := &models.User{Username: "alice", Password: "x", Role: models.RoleStaff, TenantID: 1}
+	require.NoError(t, ur.Create(tenantCtx(2), alice))
+	assert.Equal(t, uint(2), alice.TenantID)
+	batch := []models.User{{Username: "bob", Password: "x"}, {Username: "carol", Password: "x"}}
+	require.NoError(t, db.WithContext(tenantCtx(3)).Create(&batch).Error)
+	assert.Equal(t, uint(3), batch[0].TenantID)
+	assert.Equal(t, uint(3), batch[1].TenantID)
+
+	// Tenant khác không đọc/sửa/xoá được
+	_, err := ur.FindByID(tenantCtx(3), alice.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = ur.FindByUsername(tenantCtx(3), "alice")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	res := db.WithContext(tenantCtx(3)).Model(&models.User{}).Where("id = ?", alice.ID).Update("name", "X")
+	require.NoError(t, res.Error)
+	assert.Zero(t, res.RowsAffected)
+	res = db.WithContext(tenantCtx(3)).Delete(&models.User{}, alice.ID)
+	require.NoError(t, res.Error)
+	assert.Zero(t, res.RowsAffected)
+
+	var n int64
+	require.NoError(t, db.WithContext(tenantCtx(3)).Model(&models.User{}).Count(&n).Error)
+	assert.Equal(t, int64(2), n)
+
+	// Đúng tenant => thấy
+	got, err := ur.FindByID(tenantCtx(2), alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", got.Username)
+
+	// Không có tenant (job hệ thống) hoặc AllTenants => không lọc
+	require.NoError(t, db.WithContext(context.Background()).Model(&models.User{}).Count(&n).Error)
+	assert.Equal(t, int64(3), n)
+	require.NoError(t, db.WithContext(utils.AllTenants(tenantCtx(3))).Model(&models.User{}).Count(&n).Error)
+	assert.Equal(t, int64(3), n)
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"time"
 
 	"go-demo-gin/models"
 	"go-demo-gin/pkg"
@@ -42,16 +43,62 @@ func (r *GormUserRepo) Delete(ctx context.Context, id uint) error {
 	return r.dbFrom(ctx).WithContext(ctx).Delete(&models.User{}, id).Error
 }
 
-func (r *GormUserRepo) List(ctx context.Context, pag *pkg.Pagination, search string) ([]models.User, int64, error) {
-	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.User{})
-	if search != "" {
-		// Lưu ý: ILIKE là của Postgres; nếu test bằng SQLite thì đổi sang LOWER(...) LIKE ...
-		q = q.Where("name ILIKE ? OR username ILIKE ?", "%"+search+"%", "%"+search+"%")
+// UpdateStatus ghi trạng thái mới (Updates(map) để xoá được lý do cũ khi reason rỗng)
+func (r *GormUserRepo) UpdateStatus(ctx context.Context, u *models.User) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(u).Updates(map[string]any{
+		"status":            u.Status,
+		"status_reason":     u.StatusReason,
+		"status_changed_at": u.StatusChangedAt,
+	}).Error
+}
+
+// UpdatePasswordHash thay hash (cùng mật khẩu, thuật toán/tham số mới); UpdateColumn => không đổi updated_at
+func (r *GormUserRepo) UpdatePasswordHash(ctx context.Context, u *models.User) error {
+	return r.dbFrom(ctx).WithContext(ctx).Model(u).UpdateColumn("password", u.Password).Error
+}
+
+// AddPasswordHistory lưu hash mật khẩu vừa đặt và chỉ giữ lại keep bản gần nhất
+func (r *GormUserRepo) AddPasswordHistory(ctx context.Context, userID uint, hash string, keep int) error {
+	db := r.dbFrom(ctx).WithContext(ctx)
+	if err := db.Create(&models.PasswordHistory{UserID: userID, Hash: hash}).Error; err != nil {
+		return err
+	}
+	var ids []uint
+	if err := db.Model(&models.PasswordHistory{}).Where("user_id = ?", userID).
+		Order("id desc").Offset(keep).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Where("id IN ?", ids).Delete(&models.PasswordHistory{}).Error
+}
+
+// FindDeletedByID tìm user đã bị xoá mềm (user chưa xoá => ErrRecordNotFound)
+func (r *GormUserRepo) FindDeletedByID(ctx context.Context, id uint) (*models.User, error) {
+	var u models.User
+	if err := r.dbFrom(ctx).WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&u, id).Error; err != nil {
+		return nil, err
 	}
+	return &u, nil
+}
+
+// Restore khôi phục user đã bị xoá mềm
+func (r *GormUserRepo) Restore(ctx context.Context, id uint) error {
+	return r.dbFrom(ctx).WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *GormUserRepo) List(ctx context.Context, pag *pkg.Pagination, search string) ([]models.User, int64, error) {
+	q := r.searchQuery(ctx, search)
 	var total int64
 	if err := q.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
+	pag.TotalRows = total // Paginate tính TotalPages từ TotalRows
 	var users []models.User
 	if err := q.Scopes(utils.Paginate(pag, q)).Find(&users).Error; err != nil {
 		return nil, 0, err
@@ -59,6 +106,55 @@ func (r *GormUserRepo) List(ctx context.Context, pag *pkg.Pagination, search str
 	return users, total, nil
 }
 
+// Stream đọc users theo cùng bộ lọc search/sort với List bằng cursor của DB (không nạp cả bảng vào bộ nhớ),
+// gọi fn với từng lô tối đa batchSize bản ghi. fn trả lỗi => dừng đọc.
+func (r *GormUserRepo) Stream(ctx context.Context, search, sort string, batchSize int, fn func([]models.User) error) error {
+	pag := pkg.Pagination{Sort: sort}
+	rows, err := r.searchQuery(ctx, search).Order(pag.GetSort()).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	db := r.dbFrom(ctx)
+	batch := make([]models.User, 0, batchSize)
+	for rows.Next() {
+		var u models.User
+		if err := db.ScanRows(rows, &u); err != nil {
+			return err
+		}
+		batch = append(batch, u)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// Query users có lọc theo search (dùng chung cho List và Stream)
+func (r *GormUserRepo) searchQuery(ctx context.Context, search string) *gorm.DB {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.User{})
+	if search != "" {
+		// Lưu ý: ILIKE là của Postgres; nếu test bằng SQLite thì đổi sang LOWER(...) LIKE ...
+		q = q.Where("name ILIKE ? OR username ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	return q
+}
+
+// FindCredentials đọc user kèm hash mật khẩu để đăng nhập (không qua cache)
+func (r *GormUserRepo) FindCredentials(ctx context.Context, username string) (*models.User, error) {
+	return r.FindByUsername(ctx, username)
+}
+
 func (r *GormUserRepo) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var u models.User
 	if err := r.dbFrom(ctx).WithContext(ctx).
@@ -68,3 +164,43 @@ func (r *GormUserRepo) FindByUsername(ctx context.Context, username string) (*mo
 	}
 	return &u, nil
 }
+
+// FindByEmail không phân biệt hoa thường
+func (r *GormUserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var u models.User
+	if err := r.dbFrom(ctx).WithContext(ctx).
+		Where("LOWER(email) = LOWER(?)", email).
+		First(&u).Error; err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// MarkVerificationSent ghi thời điểm gửi email xác thực nếu lần gửi trước đã cũ hơn notAfter.
+// Điều kiện nằm trong câu UPDATE => 2 request đồng thời chỉ 1 request được gửi; false = bị chặn.
+func (r *GormUserRepo) MarkVerificationSent(ctx context.Context, id uint, at, notAfter time.Time) (bool, error) {
+	res := r.dbFrom(ctx).WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at <= ?)", id, notAfter).
+		Update("verification_sent_at", at)
+	return res.RowsAffected > 0, res.Error
+}
+
+// Thời điểm thay đổi gần nhất của bảng users (tạo/sửa => updated_at, xoá mềm => deleted_at).
+// Dùng ORDER BY ... LIMIT 1 thay vì MAX() để driver giữ đúng kiểu thời gian (SQLite trả MAX() dạng text).
+func (r *GormUserRepo) LastModified(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, column := range []string{"updated_at", "deleted_at"} {
+		var ts []time.Time
+		if err := r.dbFrom(ctx).WithContext(ctx).Unscoped().Model(&models.User{}).
+			Where(column+" IS NOT NULL").
+			Order(column+" DESC").
+			Limit(1).
+			Pluck(column, &ts).Error; err != nil {
+			return time.Time{}, err
+		}
+		if len(ts) > 0 && ts[0].After(latest) {
+			latest = ts[0]
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-demo-gin/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGormUserRepo_LastModified(t *testing.T) {
+	ctx := context.Background()
+	_, inner, _, u := setupCachedUserRepo(t)
+	r := inner.GormUserRepo
+
+	created, err := r.LastModified(ctx)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, u.UpdatedAt, created, 0)
+
+	// Xoá mềm chỉ đổi deleted_at nhưng vẫn phải làm Last-Modified tăng
+	assert.NoError(t, r.Delete(ctx, u.ID))
+	deleted, err := r.LastModified(ctx)
+	assert.NoError(t, err)
+	assert.True(t, deleted.After(created))
+}
+
+func TestGormUserRepo_Stream(t *testing.T) {
+	ctx := context.Background()
+	db, inner, _, alice := setupCachedUserRepo(t)
+	r := inner.GormUserRepo
+	for _, name := range []string{"bob", "carol", "dave"} {
+		assert.NoError(t, db.Create(&models.User{Username: name, Password: "x", Role: models.RoleCustomer}).Error)
+	}
+	assert.NoError(t, r.Delete(ctx, alice.ID)) // bản ghi xoá mềm không được export
+
+	var batches [][]string
+	err := r.Stream(ctx, "", "id desc", 2, func(users []models.User) error {
+		var names []string
+		for _, u := range users {
+			names = append(names, u.Username)
+		}
+		batches = append(batches, names)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"dave", "carol"}, {"bob"}}, batches)
+
+	// fn trả lỗi => dừng đọc
+	stop := errors.New("stop")
+	calls := 0
+	err = r.Stream(ctx, "", "", 1, func([]models.User) error { calls++; return stop })
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
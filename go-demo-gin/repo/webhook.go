@@ -0,0 +1,120 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormWebhookRepo struct{ db *gorm.DB }
+
+func NewGormWebhookRepo(db *gorm.DB) *GormWebhookRepo { return &GormWebhookRepo{db: db} }
+
+// Lấy DB/Tx từ context nếu có, ngược lại dùng db gốc
+func (r *GormWebhookRepo) dbFrom(ctx context.Context) *gorm.DB {
+	if tx, ok := utils.TxFrom(ctx); ok && tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+func (r *GormWebhookRepo) CreateEndpoint(ctx context.Context, e *models.WebhookEndpoint) error {
+	return r.dbFrom(ctx).WithContext(ctx).Create(e).Error
+}
+
+func (r *GormWebhookRepo) FindEndpointByID(ctx context.Context, id uint) (*models.WebhookEndpoint, error) {
+	var e models.WebhookEndpoint
+	if err := r.dbFrom(ctx).WithContext(ctx).First(&e, id).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// SaveEndpoint lưu toàn bộ các trường (kể cả zero-value như Active=false, FailureCount=0)
+func (r *GormWebhookRepo) SaveEndpoint(ctx context.Context, e *models.WebhookEndpoint) error {
+	return r.dbFrom(ctx).WithContext(ctx).Save(e).Error
+}
+
+func (r *GormWebhookRepo) DeleteEndpoint(ctx context.Context, id uint) error {
+	return r.dbFrom(ctx).WithContext(ctx).Delete(&models.WebhookEndpoint{}, id).Error
+}
+
+func (r *GormWebhookRepo) ListEndpoints(ctx context.Context, pag *pkg.Pagination) ([]models.WebhookEndpoint, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.WebhookEndpoint{})
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var endpoints []models.WebhookEndpoint
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&endpoints).Error; err != nil {
+		return nil, 0, err
+	}
+	return endpoints, total, nil
+}
+
+func (r *GormWebhookRepo) ListActiveEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := r.dbFrom(ctx).WithContext(ctx).Where("active = ?", true).Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// CreateDelivery bỏ qua nếu event đã được xếp hàng cho endpoint này (dispatcher có thể gửi lại)
+func (r *GormWebhookRepo) CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.dbFrom(ctx).WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "endpoint_id"}, {Name: "event_id"}}, DoNothing: true}).
+		Create(d).Error
+}
+
+func (r *GormWebhookRepo) SaveDelivery(ctx context.Context, d *models.WebhookDelivery) error {
+	return r.dbFrom(ctx).WithContext(ctx).Save(d).Error
+}
+
+func (r *GormWebhookRepo) ListDeliveries(ctx context.Context, endpointID uint, pag *pkg.Pagination) ([]models.WebhookDelivery, int64, error) {
+	q := r.dbFrom(ctx).WithContext(ctx).Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	pag.TotalRows = total
+	var deliveries []models.WebhookDelivery
+	if err := q.Scopes(utils.Paginate(pag, q)).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// ClaimDueDeliveries: giữ chỗ các delivery đến hạn bằng cập nhật có điều kiện (giống outbox)
+func (r *GormWebhookRepo) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	db := r.dbFrom(ctx).WithContext(ctx)
+
+	var candidates []models.WebhookDelivery
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("id asc").Limit(limit).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]models.WebhookDelivery, 0, len(candidates))
+	for _, d := range candidates {
+		next := now.Add(lease)
+		res := db.Model(&models.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", d.ID, models.WebhookDeliveryPending, d.NextAttemptAt).
+			Update("next_attempt_at", next)
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			d.NextAttemptAt = next
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
@@ -0,0 +1,7 @@
+package apikey
+
+type APIKeyCreate struct {
+	Name          string   `json:"name" validate:"required,max=100" example:"CI pipeline"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write audit:read webhooks:read webhooks:write jobs:read orgs:read orgs:write" example:"users:read"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365" example:"90"` // bỏ trống => 90 ngày
+}
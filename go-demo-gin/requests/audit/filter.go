@@ -0,0 +1,13 @@
+package audit
+
+import "time"
+
+type AuditFilter struct {
+	ActorID        uint      `form:"actor_id"`
+	ImpersonatorID uint      `form:"impersonator_id"`
+	Action         string    `form:"action"`
+	TargetType     string    `form:"target_type"`
+	TargetID       uint      `form:"target_id"`
+	From           time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To             time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
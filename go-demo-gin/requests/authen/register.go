@@ -0,0 +1,37 @@
+package authen
+
+import (
+	"go-demo-gin/pkg/password"
+	"time"
+)
+
+// RegisterForm: khách tự đăng ký, luôn là customer và ở trạng thái pending cho tới khi xác thực email
+type RegisterForm struct {
+	Username string `json:"username" validate:"required,username,duplicateUsername"`
+	Pass     string `json:"password" validate:"required,password,notBreached,notUsername"`
+	Mail     string `json:"email" validate:"required,email,max=255,duplicateEmail" example:"user@example.com"`
+	Name     string `json:"full_name"`
+	Date     string `json:"birthday" validate:"omitempty,birthday" example:"2006-01-02"`
+}
+
+func (r *RegisterForm) Password() string {
+	hash, _ := password.Hash(r.Pass)
+	return hash
+}
+
+func (r *RegisterForm) Email() *string {
+	return &r.Mail
+}
+
+// Birthday: nil nếu không nhập
+func (r *RegisterForm) Birthday() *time.Time {
+	birthday, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return nil
+	}
+	return &birthday
+}
+
+type ResendVerificationForm struct {
+	Mail string `json:"email" validate:"required,email" example:"user@example.com"`
+}
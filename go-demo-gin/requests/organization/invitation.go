@@ -0,0 +1,12 @@
+package organization
+
+type InvitationCreate struct {
+	Email string `json:"email" validate:"required,email,max=255" example:"bob@example.com"`
+	// Quyền trong team nếu có team_id, ngược lại trong organization
+	Role   string `json:"role" validate:"required,oneof=owner manager member" example:"member"`
+	TeamID *uint  `json:"team_id" example:"3"`
+}
+
+type InvitationAccept struct {
+	Token string `json:"token" validate:"required"`
+}
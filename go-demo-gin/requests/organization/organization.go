@@ -0,0 +1,14 @@
+package organization
+
+type OrganizationCreate struct {
+	Name string `json:"name" validate:"required,max=100" example:"Engineering"`
+}
+
+type OrganizationUpdate struct {
+	Name string `json:"name" validate:"required,max=100" example:"Engineering"`
+}
+
+// MemberUpdate: quyền của thành viên trong organization/team
+type MemberUpdate struct {
+	Role string `json:"role" validate:"required,oneof=owner manager member" example:"member"`
+}
@@ -0,0 +1,9 @@
+package organization
+
+type TeamCreate struct {
+	Name string `json:"name" validate:"required,max=100" example:"Backend"`
+}
+
+type TeamUpdate struct {
+	Name string `json:"name" validate:"required,max=100" example:"Backend"`
+}
@@ -0,0 +1,6 @@
+package tenant
+
+type TenantCreate struct {
+	Slug string `json:"slug" validate:"required,tenantSlug" example:"acme"` // header X-Tenant / subdomain
+	Name string `json:"name" validate:"required,max=255" example:"Acme Corp"`
+}
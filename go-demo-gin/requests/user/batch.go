@@ -0,0 +1,27 @@
+package user
+
+// Số thao tác tối đa trong 1 request batch
+const MaxBatchOperations = 100
+
+// UserBatch: danh sách thao tác trên nhiều user
+type UserBatch struct {
+	Mode       string               `json:"mode" validate:"omitempty,oneof=atomic partial" default:"atomic"` // mặc định atomic
+	Operations []UserBatchOperation `json:"operations" validate:"required,min=1,max=100"`
+}
+
+// UserBatchOperation: một thao tác; role chỉ dùng cho set_role, reason cho các thao tác đổi trạng thái
+type UserBatchOperation struct {
+	Op     string `json:"op" validate:"required,oneof=delete restore set_role disable activate lock unlock" example:"set_role"`
+	ID     uint   `json:"id" validate:"required" example:"2"`
+	Role   string `json:"role,omitempty" validate:"required_if=Op set_role,omitempty,role" example:"staff"`
+	Reason string `json:"reason,omitempty" validate:"required_if=Op disable,max=255"`
+}
+
+const (
+	BatchAtomic  = "atomic"  // 1 thao tác lỗi => rollback toàn bộ
+	BatchPartial = "partial" // thao tác lỗi bị bỏ qua, các thao tác khác vẫn được áp dụng
+
+	BatchDelete  = "delete"
+	BatchRestore = "restore"
+	BatchSetRole = "set_role" // Thao tác đổi trạng thái dùng tên action trong status.go (disable, activate, lock, unlock)
+)
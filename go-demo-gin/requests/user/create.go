@@ -1,22 +1,30 @@
 package user
 
 import (
+	"go-demo-gin/pkg/password"
 	"time"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserCreate struct {
 	Username string `json:"username" validate:"required,username,duplicateUsername"`
-	Pass     string `json:"password" validate:"required,password,hashed" default:"12345678"`
+	Pass     string `json:"password" validate:"required,password,notBreached,notUsername" default:"12345678"`
 	Name     string `json:"full_name"`
+	Mail     string `json:"email" validate:"omitempty,email,duplicateEmail" example:"user@example.com"`
 	Role     string `json:"role" validate:"required,role" default:"customer"`
 	Date     string `json:"birthday" validate:"birthday" default:"2006-01-02"`
 }
 
 func (u *UserCreate) Password() string {
-	hash, _ := bcrypt.GenerateFromPassword([]byte(u.Pass), bcrypt.DefaultCost)
-	return string(hash)
+	hash, _ := password.Hash(u.Pass)
+	return hash
+}
+
+// Email rỗng => NULL (cột unique)
+func (u *UserCreate) Email() *string {
+	if u.Mail == "" {
+		return nil
+	}
+	return &u.Mail
 }
 
 func (u *UserCreate) Birthday() *time.Time {
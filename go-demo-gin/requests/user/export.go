@@ -0,0 +1,8 @@
+package user
+
+// UserExport: tuỳ chọn khi export user (query string), search/sort giống GET /users
+type UserExport struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl xlsx"` // mặc định csv
+	Search string `form:"search"`
+	Sort   string `form:"sort"`
+}
@@ -0,0 +1,13 @@
+package user
+
+// UserImport: tuỳ chọn khi import user từ file (query string)
+type UserImport struct {
+	DryRun bool   `form:"dry_run"`                                                  // chỉ validate, không ghi DB
+	Mode   string `form:"mode" binding:"omitempty,oneof=atomic best_effort"`        // mặc định atomic
+	Report string `form:"report" binding:"omitempty,oneof=json csv" default:"json"` // csv => tải file báo cáo
+}
+
+const (
+	ImportAtomic     = "atomic"      // 1 dòng lỗi => không tạo dòng nào
+	ImportBestEffort = "best_effort" // tạo các dòng hợp lệ, bỏ qua dòng lỗi
+)
@@ -0,0 +1,15 @@
+package user
+
+// Các action đổi trạng thái tài khoản (xem models.UserStatus.Transition)
+const (
+	StatusActivate = "activate"
+	StatusDisable  = "disable"
+	StatusLock     = "lock"
+	StatusUnlock   = "unlock"
+)
+
+// UserStatusChange: body (tuỳ chọn) của các endpoint đổi trạng thái; disable bắt buộc có lý do
+type UserStatusChange struct {
+	Action string `json:"-" validate:"required,oneof=activate disable lock unlock"` // lấy từ route
+	Reason string `json:"reason" validate:"required_if=Action disable,max=255" example:"Spam"`
+}
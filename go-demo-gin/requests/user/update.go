@@ -1,13 +1,12 @@
 package user
 
 import (
+	"go-demo-gin/pkg/password"
 	"time"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUpdate struct {
-	Pass string `json:"password" validate:"omitempty,password,hashed" default:"12345678"`
+	Pass string `json:"password" validate:"omitempty,password,notBreached,notUsername,notReused" default:"12345678"`
 	Name string `json:"full_name"`
 	Role string `json:"role" validate:"required,role" default:"customer"`
 	Date string `json:"birthday" validate:"birthday" default:"2006-01-02"`
@@ -17,9 +16,8 @@ func (u *UserUpdate) Password() *string {
 	if u.Pass == "" {
 		return nil // Không hash, giữ nguyên password cũ
 	}
-	hash, _ := bcrypt.GenerateFromPassword([]byte(u.Pass), bcrypt.DefaultCost)
-	result := string(hash)
-	return &result
+	hash, _ := password.Hash(u.Pass)
+	return &hash
 }
 
 func (u *UserUpdate) Birthday() *time.Time {
@@ -0,0 +1,8 @@
+package webhook
+
+type WebhookCreate struct {
+	URL         string   `json:"url" validate:"required,url"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,oneof=user.created user.updated user.deleted user.role_changed user.status_changed"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=128"`
+	Description string   `json:"description"`
+}
@@ -0,0 +1,9 @@
+package webhook
+
+type WebhookUpdate struct {
+	URL         string   `json:"url" validate:"omitempty,url"`
+	EventTypes  []string `json:"event_types" validate:"omitempty,min=1,dive,oneof=user.created user.updated user.deleted user.role_changed user.status_changed"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=128"`
+	Description string   `json:"description"`
+	Active      *bool    `json:"active"` // bật lại endpoint đã bị tắt tự động => reset số lần lỗi
+}
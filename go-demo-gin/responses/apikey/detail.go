@@ -0,0 +1,15 @@
+package apikey
+
+import "time"
+
+type APIKeyDetail struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"` // phần đầu của key, để nhận biết key trong danh sách
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Key        string     `json:"key,omitempty"` // chỉ trả về khi tạo mới
+	CreatedAt  time.Time  `json:"created_at"`
+}
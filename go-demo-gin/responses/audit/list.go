@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEvent struct {
+	ID               uint            `json:"id"`
+	CreatedAt        time.Time       `json:"created_at"`
+	ActorID          *uint           `json:"actor_id"`
+	ActorName        string          `json:"actor_name"`
+	ImpersonatorID   *uint           `json:"impersonator_id,omitempty"`
+	ImpersonatorName string          `json:"impersonator_name,omitempty"`
+	Action           string          `json:"action"`
+	TargetType       string          `json:"target_type"`
+	TargetID         uint            `json:"target_id"`
+	Changes          json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID        string          `json:"request_id"`
+	IP               string          `json:"ip"`
+}
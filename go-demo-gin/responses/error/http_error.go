@@ -1,21 +1,19 @@
 package error
 
-import "encoding/json"
-
-type Error struct {
-	Error map[string]string `json:"error"`
-}
+import "github.com/nicksnyder/go-i18n/v2/i18n"
 
+// HTTPError: lỗi gắn vào gin.Context (c.Error) để ErrorHandler render thành problem+json.
+// Code là i18n message (ID dùng làm mã lỗi ổn định), chỉ được dịch khi render.
 type HTTPError struct {
 	StatusCode int
-	Message    Error
+	Code       *i18n.Message
+	Fields     map[string]*i18n.Message // lỗi theo field (validation)
 }
 
 // Đảm bảo implement interface `error`
 func (e *HTTPError) Error() string {
-	bytes, err := json.Marshal(e.Message.Error)
-	if err != nil {
+	if e.Code == nil {
 		return "Internal Error"
 	}
-	return string(bytes)
+	return e.Code.ID
 }
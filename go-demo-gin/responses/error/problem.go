@@ -0,0 +1,23 @@
+package error
+
+// Content type của response lỗi (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem: body lỗi chuẩn RFC 7807 dùng chung cho mọi middleware và controller
+type Problem struct {
+	Type      string       `json:"type" example:"/problems/not-found"`
+	Title     string       `json:"title" example:"Not Found"`
+	Status    int          `json:"status" example:"404"`
+	Detail    string       `json:"detail,omitempty" example:"Not found item"`
+	Instance  string       `json:"instance,omitempty" example:"/api/v1/users/42"`
+	Code      string       `json:"code" example:"NOT_FOUND"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty" example:"c0a8012e-3b7c-4d0e-9f1a-2b6f0b7e6d51"`
+}
+
+// FieldError: lỗi validation của một field
+type FieldError struct {
+	Field  string `json:"field" example:"username"`
+	Code   string `json:"code" example:"DUPLICATE_USERNAME"`
+	Detail string `json:"detail" example:"Username is already taken"`
+}
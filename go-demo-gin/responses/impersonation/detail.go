@@ -0,0 +1,10 @@
+package impersonation
+
+import "time"
+
+type ImpersonationDetail struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserID    uint      `json:"user_id"` // user bị impersonate
+	Username  string    `json:"username"`
+}
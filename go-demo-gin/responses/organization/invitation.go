@@ -0,0 +1,15 @@
+package organization
+
+import "time"
+
+type InvitationDetail struct {
+	ID             uint       `json:"id"`
+	OrganizationID uint       `json:"organization_id"`
+	TeamID         *uint      `json:"team_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,19 @@
+package organization
+
+import "time"
+
+type OrganizationDetail struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"` // quyền của user đang đăng nhập (rỗng => không là thành viên)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MemberDetail: thành viên của organization hoặc team
+type MemberDetail struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
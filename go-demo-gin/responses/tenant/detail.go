@@ -0,0 +1,11 @@
+package tenant
+
+import "time"
+
+type TenantDetail struct {
+	ID         uint       `json:"id"`
+	Slug       string     `json:"slug"`
+	Name       string     `json:"name"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package user
+
+import errorResponse "go-demo-gin/responses/error"
+
+// Kết quả của từng thao tác; Status là HTTP status tương ứng (200, 400, 403, 404, 409, 424...)
+type BatchItemResult struct {
+	Index  int                        `json:"index" example:"0"` // vị trí trong danh sách operations
+	Op     string                     `json:"op" example:"set_role"`
+	ID     uint                       `json:"id" example:"2"`
+	Status int                        `json:"status" example:"200"`
+	Code   string                     `json:"code,omitempty" example:"NOT_FOUND"`
+	Detail string                     `json:"detail,omitempty"`
+	Errors []errorResponse.FieldError `json:"errors,omitempty"`
+}
+
+type BatchResult struct {
+	Mode      string            `json:"mode" example:"atomic"`
+	Succeeded int               `json:"succeeded" example:"2"`
+	Failed    int               `json:"failed" example:"0"`
+	Results   []BatchItemResult `json:"results"`
+}
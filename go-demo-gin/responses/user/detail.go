@@ -5,11 +5,14 @@ import (
 )
 
 type UserDetail struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Name      string    `json:"full_name"`
-	Role      string    `json:"role"`
-	Birthday  time.Time `json:"birthday"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id"`
+	Username     string    `json:"username"`
+	Name         string    `json:"full_name"`
+	Role         string    `json:"role"`
+	Email        string    `json:"email,omitempty"`
+	Birthday     time.Time `json:"birthday"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Status       string    `json:"status" example:"active"`
+	StatusReason string    `json:"status_reason,omitempty"`
 }
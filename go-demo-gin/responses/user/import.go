@@ -0,0 +1,27 @@
+package user
+
+import errorResponse "go-demo-gin/responses/error"
+
+// Trạng thái của từng dòng trong báo cáo import
+const (
+	ImportRowValid   = "valid"   // dry-run: dòng hợp lệ
+	ImportRowCreated = "created" // đã tạo user
+	ImportRowFailed  = "failed"  // dòng lỗi (xem errors)
+	ImportRowSkipped = "skipped" // hợp lệ nhưng không tạo vì chế độ atomic có dòng khác lỗi
+)
+
+type ImportRow struct {
+	Row      int                        `json:"row" example:"2"` // số dòng trong file (dòng 1 là header)
+	Username string                     `json:"username" example:"john.doe"`
+	Status   string                     `json:"status" example:"created"`
+	Errors   []errorResponse.FieldError `json:"errors,omitempty"`
+}
+
+type ImportResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Mode    string      `json:"mode" example:"atomic"`
+	Total   int         `json:"total" example:"3"`
+	Created int         `json:"created" example:"2"`
+	Failed  int         `json:"failed" example:"1"`
+	Rows    []ImportRow `json:"rows"`
+}
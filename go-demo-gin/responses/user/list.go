@@ -8,4 +8,5 @@ type UserList struct {
 	Name     string    `json:"full_name"`
 	Role     string    `json:"role"`
 	Birthday time.Time `json:"birthday"`
+	Status   string    `json:"status"`
 }
@@ -0,0 +1,16 @@
+package webhook
+
+import "time"
+
+type WebhookDetail struct {
+	ID           uint       `json:"id"`
+	URL          string     `json:"url"`
+	EventTypes   []string   `json:"event_types"`
+	Description  string     `json:"description"`
+	Active       bool       `json:"active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at"`
+	Secret       string     `json:"secret,omitempty"` // chỉ trả về khi tạo mới
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,254 @@
+package routes
+
+import (
+	"go-demo-gin/cache"
+	"go-demo-gin/initializers"
+	"go-demo-gin/jobs"
+	"go-demo-gin/middlewares"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/mailer"
+	"go-demo-gin/pkg/password"
+	"go-demo-gin/repo"
+	"go-demo-gin/services"
+	"go-demo-gin/utils"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Container: các dependency tạo 1 lần, dùng chung giữa HTTP (Gin) và gRPC
+// => cache, audit, outbox... hoạt động giống nhau dù request đến từ transport nào.
+type Container struct {
+	Validator       *utils.Validator
+	UserCache       cache.Cache
+	UserCacheTTL    time.Duration
+	UserRepo        *repo.CachedUserRepo
+	UsersListCache  *middlewares.ResponseCache
+	UserSvc         *services.UserService
+	AuditSvc        *services.AuditService
+	OutboxRepo      *repo.GormOutboxRepo
+	WebhookSvc      *services.WebhookService
+	AuthSvc         *services.AuthService
+	Mailer          services.MailSender
+	RegistrationSvc *services.RegistrationService
+	OIDCSvc         *services.OIDCService
+	APIKeySvc       *services.APIKeyService
+	SessionRepo     *repo.GormSessionRepo
+	SessionSvc      *services.SessionService
+	ImpersonateSvc  *services.ImpersonationService
+	TenantSvc       *services.TenantService
+	OrgSvc          *services.OrganizationService
+	TeamSvc         *services.TeamService
+	InvitationSvc   *services.InvitationService
+	JobRepo         *repo.GormJobRepo
+	Jobs            *jobs.Pool
+	JobSvc          *services.JobService
+}
+
+func NewContainer(db *gorm.DB) *Container {
+	// Multi-tenant: mọi truy vấn qua GORM được lọc theo tenant của request
+	if err := repo.RegisterTenantScope(db); err != nil {
+		logrus.WithField("source", "system").WithError(err).Fatal("Failed to register tenant scope")
+	}
+
+	// Cache user lookups: dùng Redis nếu có (chia sẻ giữa các instance), ngược lại dùng LRU trong bộ nhớ
+	var userCache cache.Cache = cache.NewLRU(10000)
+	if initializers.Redis != nil {
+		userCache = cache.NewRedis(initializers.Redis, "cache:")
+	}
+	userCacheTTL, err := time.ParseDuration(os.Getenv("USER_CACHE_TTL"))
+	if err != nil || userCacheTTL <= 0 {
+		userCacheTTL = 5 * time.Minute
+	}
+	gur := repo.NewGormUserRepo(db)
+	ur := repo.NewCachedUserRepo(gur, userCache, userCacheTTL)
+
+	// Dependency Injection (DI) - constructor injection
+	// Create a validator (tạo 1 lần, tái dùng)
+	v := utils.NewValidator(db)
+	// Chính sách + thuật toán hash mật khẩu (hasher dùng chung cho request DTO và đăng nhập)
+	hashCfg, policy := passwordConfigFromEnv()
+	password.SetDefault(password.NewHasher(hashCfg))
+	v.SetPasswordPolicy(policy)
+
+	// User service
+	ar := repo.NewGormAuditRepo(db)
+	or := repo.NewGormOutboxRepo(db)
+	userSvc := services.NewUserService(db, ur, ar, or)
+
+	// Cache response cho list users (ETag/Last-Modified theo max updated_at), xoá khi user thay đổi
+	usersListCache := middlewares.NewResponseCache(userCache, middlewares.ResponseCacheConfig{
+		Name:         "users",
+		TTL:          userCacheTTL,
+		VaryQuery:    []string{"limit", "page", "sort", "search"},
+		LastModified: gur.LastModified,
+	})
+	userSvc.InvalidateOnChange(usersListCache)
+
+	// Authen service
+	// Read JWT secret from environment variable
+	cfg := services.AuthConfig{
+		JWTKey:    []byte(os.Getenv("SECRET")),
+		Issuer:    "go-demo-gin",
+		AccessTTL: time.Hour * 24 * 30,
+	}
+
+	// Đăng ký tài khoản: gửi email qua SMTP nếu có cấu hình, ngược lại chỉ ghi log
+	var ms services.MailSender = mailer.Log{}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		ms = &mailer.SMTP{Addr: addr, From: os.Getenv("SMTP_FROM"), Username: os.Getenv("SMTP_USERNAME"), Password: os.Getenv("SMTP_PASSWORD")}
+	}
+	// APP_URL: địa chỉ public của API, dùng cho link xác thực email và OIDC callback
+	appURL := strings.TrimRight(os.Getenv("APP_URL"), "/")
+	if appURL == "" {
+		appURL = "http://localhost:8080"
+	}
+	regCfg := services.DefaultRegistrationConfig()
+	regCfg.Key = cfg.JWTKey
+	regCfg.VerifyURL = appURL + "/api/v1/authen/verify"
+
+	// Đăng nhập qua IdP ngoài (OIDC) cấp JWT giống AuthService; state lưu trong cache dùng chung với user cache
+	// Mỗi lần đăng nhập là 1 session (claim "sid"); kết quả kiểm tra session cache trong user cache
+	sr := repo.NewGormSessionRepo(db)
+	sessionSvc := services.NewSessionService(sr, userCache)
+	authSvc := services.NewAuthService(db, cfg, ur, sessionSvc)
+	// Admin đăng nhập dưới danh nghĩa user khác (IMPERSONATION_TTL, mặc định 15 phút)
+	impersonationTTL, _ := time.ParseDuration(os.Getenv("IMPERSONATION_TTL"))
+
+	// Organization/team: quyền theo membership (owner, manager, member); lời mời gửi qua email, link tới INVITATION_URL
+	orgRepo := repo.NewGormOrganizationRepo(db)
+	teamRepo := repo.NewGormTeamRepo(db)
+	authz := services.NewMembershipAuthorizer(orgRepo, teamRepo)
+	inviteCfg := services.DefaultInvitationConfig()
+	inviteCfg.AcceptURL = appURL + "/invitations/accept"
+	if u := os.Getenv("INVITATION_URL"); u != "" {
+		inviteCfg.AcceptURL = u
+	}
+
+	// Hàng đợi job chạy nền (handler được đăng ký và Run ở main)
+	jobCfg := jobs.DefaultConfig()
+	if n, err := strconv.Atoi(os.Getenv("JOB_CONCURRENCY")); err == nil && n > 0 {
+		jobCfg.Concurrency = n
+	}
+	jr := repo.NewGormJobRepo(db)
+
+	return &Container{
+		Validator:       v,
+		UserCache:       userCache,
+		UserCacheTTL:    userCacheTTL,
+		UserRepo:        ur,
+		UsersListCache:  usersListCache,
+		UserSvc:         userSvc,
+		AuditSvc:        services.NewAuditService(db, ar),
+		OutboxRepo:      or,
+		WebhookSvc:      services.NewWebhookService(db, repo.NewGormWebhookRepo(db), services.DefaultWebhookConfig()),
+		AuthSvc:         authSvc,
+		Mailer:          ms,
+		RegistrationSvc: services.NewRegistrationService(db, regCfg, userSvc, ms),
+		OIDCSvc:         services.NewOIDCService(db, oidcConfigFromEnv(appURL), userSvc, authSvc, repo.NewGormIdentityRepo(db), userCache),
+		APIKeySvc:       services.NewAPIKeyService(repo.NewGormAPIKeyRepo(db), ur),
+		SessionRepo:     sr,
+		SessionSvc:      sessionSvc,
+		ImpersonateSvc:  services.NewImpersonationService(authSvc, ur, ar, impersonationTTL),
+		TenantSvc:       services.NewTenantService(repo.NewGormTenantRepo(db), userCache),
+		OrgSvc:          services.NewOrganizationService(db, orgRepo, authz),
+		TeamSvc:         services.NewTeamService(db, teamRepo, orgRepo, authz),
+		InvitationSvc:   services.NewInvitationService(db, inviteCfg, repo.NewGormInvitationRepo(db), orgRepo, teamRepo, ur, authz, ms),
+		JobRepo:         jr,
+		Jobs:            jobs.NewPool(jr, jobCfg),
+		JobSvc:          services.NewJobService(jr),
+	}
+}
+
+// PASSWORD_HASH=argon2id|bcrypt, ARGON2_MEMORY (KiB), ARGON2_ITERATIONS, ARGON2_PARALLELISM, BCRYPT_COST;
+// PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_REQUIRE=upper,lower,digit,symbol,
+// PASSWORD_BREACHED_FILE (mỗi dòng 1 mật khẩu), PASSWORD_ALLOW_USERNAME, PASSWORD_HISTORY
+func passwordConfigFromEnv() (password.HashConfig, password.Policy) {
+	hc := password.DefaultHashConfig()
+	if alg := password.Algorithm(os.Getenv("PASSWORD_HASH")); alg == password.Bcrypt || alg == password.Argon2id {
+		hc.Algorithm = alg
+	}
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_MEMORY"), 10, 32); err == nil && n > 0 {
+		hc.Argon2.Memory = uint32(n)
+	}
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_ITERATIONS"), 10, 32); err == nil && n > 0 {
+		hc.Argon2.Iterations = uint32(n)
+	}
+	if n, err := strconv.ParseUint(os.Getenv("ARGON2_PARALLELISM"), 10, 8); err == nil && n > 0 {
+		hc.Argon2.Parallelism = uint8(n)
+	}
+	if n, err := strconv.Atoi(os.Getenv("BCRYPT_COST")); err == nil && n >= bcrypt.MinCost && n <= bcrypt.MaxCost {
+		hc.BcryptCost = n
+	}
+
+	p := password.DefaultPolicy()
+	if n, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && n > 0 {
+		p.MinLength = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("PASSWORD_MAX_LENGTH")); err == nil && n >= p.MinLength {
+		p.MaxLength = n
+	}
+	if hc.Algorithm == password.Bcrypt {
+		p.MaxBytes = 72 // bcrypt bỏ qua phần sau 72 byte
+	}
+	for _, class := range strings.Split(os.Getenv("PASSWORD_REQUIRE"), ",") {
+		switch strings.TrimSpace(class) {
+		case "upper":
+			p.RequireUpper = true
+		case "lower":
+			p.RequireLower = true
+		case "digit":
+			p.RequireDigit = true
+		case "symbol":
+			p.RequireSymbol = true
+		}
+	}
+	if allow, err := strconv.ParseBool(os.Getenv("PASSWORD_ALLOW_USERNAME")); err == nil {
+		p.DisallowUsername = !allow
+	}
+	if n, err := strconv.Atoi(os.Getenv("PASSWORD_HISTORY")); err == nil && n >= 0 {
+		p.History = n
+	}
+	if path := os.Getenv("PASSWORD_BREACHED_FILE"); path != "" {
+		if err := p.LoadBreached(path); err != nil {
+			logrus.WithField("source", "system").WithError(err).Fatal("Failed to load PASSWORD_BREACHED_FILE")
+		}
+	}
+	return hc, p
+}
+
+// OIDC_PROVIDERS=acme,google; mỗi provider đọc OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _REDIRECT_URL, _SCOPES, _DEFAULT_ROLE (đặt rỗng => không tự tạo user), _LINK_BY_EMAIL
+func oidcConfigFromEnv(appURL string) services.OIDCConfig {
+	var cfg services.OIDCConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		p := services.OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+			DefaultRole:  models.RoleCustomer,
+		}
+		if p.RedirectURL == "" {
+			p.RedirectURL = appURL + "/api/v1/authen/oidc/" + name + "/callback"
+		}
+		if role, ok := os.LookupEnv(prefix + "DEFAULT_ROLE"); ok {
+			p.DefaultRole = models.Role(role)
+		}
+		p.LinkByEmail, _ = strconv.ParseBool(os.Getenv(prefix + "LINK_BY_EMAIL"))
+		cfg.Providers = append(cfg.Providers, p)
+	}
+	return cfg
+}
@@ -1,22 +1,32 @@
 package routes
 
 import (
+	"expvar"
 	"go-demo-gin/controllers"
+	"go-demo-gin/graph"
+	"go-demo-gin/initializers"
 	"go-demo-gin/middlewares"
 	"go-demo-gin/models"
-	"go-demo-gin/repo"
-	"go-demo-gin/services"
-	"go-demo-gin/utils"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 )
 
 func SetupRoutes(r *gin.Engine, db *gorm.DB) {
+	RegisterRoutes(r, NewContainer(db))
+}
+
+// RegisterRoutes gắn middleware và route với các dependency đã tạo sẵn (dùng chung với gRPC)
+func RegisterRoutes(r *gin.Engine, c *Container) {
+
+	// Gắn middleware recovery đầu tiên để bắt cả panic trong các middleware phía sau;
+	// lỗi 500 vẫn được dịch và có request ID nếu I18n/AccessLogger đã chạy trước khi panic
+	r.Use(middlewares.Recovery())
 
 	// Use ginSwagger middleware to serve the API docs
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -30,30 +40,103 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 	// Gắn middleware i18n
 	r.Use(middlewares.I18n())
 
+	// Rate limit: dùng Redis nếu có (chia sẻ giữa các instance), ngược lại dùng bộ nhớ.
+	// Mọi policy đọc lại giới hạn từ env (RATE_LIMIT_<NAME>[_<ROLE>]=<limit>/<period>)
+	var rlStore middlewares.RateLimitStore = middlewares.NewMemoryRateLimitStore()
+	if initializers.Redis != nil {
+		rlStore = middlewares.NewRedisRateLimitStore(initializers.Redis)
+	}
+	Limit := middlewares.RateLimiter(rlStore)
+
+	// Gắn middleware giới hạn theo IP trước khi tra tenant/xác thực (chặn dò token, API key)
+	r.Use(Limit(middlewares.RatePolicy{
+		Name:    "ip",
+		Default: middlewares.RateLimit{Limit: 300, Period: time.Minute},
+		ByIP:    true,
+	}.FromEnv()))
+
+	// Gắn middleware xác định tenant (header X-Tenant hoặc subdomain của TENANT_BASE_DOMAIN)
+	r.Use(middlewares.ResolveTenant(c.TenantSvc, os.Getenv("TENANT_BASE_DOMAIN")))
+
+	SUPER_ADMIN := models.RoleSuperAdmin
 	ADMIN := models.RoleAdmin
 	STAFF := models.RoleStaff
 	CUSTOMER := models.RoleCustomer
-	RequireRoles := middlewares.Authentication(db)
-
-	// Dependency Injection (DI) - constructor injection
-	// Create a validator (tạo 1 lần, tái dùng)
-	v := utils.NewValidator(db)
-
-	// Create services and controllers
-	// User service and controller
-	ur := repo.NewGormUserRepo(db)
-	userSvc := services.NewUserService(db, ur)
-	uc := controllers.NewUserController(v, userSvc)
-
-	// Authen service and controller
-	// Read JWT secret from environment variable
-	cfg := services.AuthConfig{
-		JWTKey:    []byte(os.Getenv("SECRET")),
-		Issuer:    "go-demo-gin",
-		AccessTTL: time.Hour * 24 * 30,
+
+	RequireRoles := middlewares.Authentication(c.UserRepo, c.APIKeySvc, c.SessionSvc)
+	Scope := middlewares.RequireScope
+	usersListCache := c.UsersListCache
+
+	// Create controllers
+	uc := controllers.NewUserController(c.Validator, c.UserSvc)
+	auc := controllers.NewAuditController(c.AuditSvc)
+	wc := controllers.NewWebhookController(c.Validator, c.WebhookSvc)
+	ac := controllers.NewAuthController(c.AuthSvc)
+	rc := controllers.NewRegistrationController(c.Validator, c.RegistrationSvc)
+	oc := controllers.NewOIDCController(c.OIDCSvc)
+	jc := controllers.NewJobController(c.JobSvc)
+	kc := controllers.NewAPIKeyController(c.Validator, c.APIKeySvc)
+	sc := controllers.NewSessionController(c.SessionSvc)
+	ic := controllers.NewImpersonationController(c.ImpersonateSvc)
+	tc := controllers.NewTenantController(c.Validator, c.TenantSvc)
+	orc := controllers.NewOrganizationController(c.Validator, c.OrgSvc)
+	tmc := controllers.NewTeamController(c.Validator, c.TeamSvc)
+	ivc := controllers.NewInvitationController(c.Validator, c.InvitationSvc)
+
+	// GraphQL: cùng UserService, phân quyền theo field trong resolver
+	schema, schemaErr := graph.NewSchema(c.Validator, c.UserSvc)
+	if schemaErr != nil {
+		logrus.WithField("source", "system").WithError(schemaErr).Fatal("Failed to build GraphQL schema")
+	}
+	gc := controllers.NewGraphQLController(schema, graph.Limits{MaxDepth: 5, MaxComplexity: 1000})
+
+	// Idempotency-Key cho các endpoint POST (client retry khi mạng chập chờn)
+	idemTTL, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil || idemTTL <= 0 {
+		idemTTL = 24 * time.Hour
+	}
+	// Dùng Redis nếu có để response được lưu chung giữa các instance
+	var idemStore middlewares.IdempotencyStore = middlewares.NewMemoryIdempotencyStore()
+	if initializers.Redis != nil {
+		idemStore = middlewares.NewRedisIdempotencyStore(initializers.Redis)
+	}
+	Idempotent := middlewares.Idempotency(idemStore, idemTTL)
+
+	usersRead := middlewares.RatePolicy{
+		Name:    "users:read",
+		Default: middlewares.RateLimit{Limit: 60, Period: time.Minute},
+		Roles: map[models.Role]middlewares.RateLimit{
+			ADMIN:    {Limit: 600, Period: time.Minute},
+			STAFF:    {Limit: 300, Period: time.Minute},
+			CUSTOMER: {Limit: 60, Period: time.Minute},
+		},
+	}.FromEnv()
+	usersWrite := middlewares.RatePolicy{
+		Name:    "users:write",
+		Default: middlewares.RateLimit{Limit: 20, Period: time.Minute},
+		Roles: map[models.Role]middlewares.RateLimit{
+			ADMIN: {Limit: 120, Period: time.Minute},
+			STAFF: {Limit: 60, Period: time.Minute},
+		},
+	}.FromEnv()
+	// Login dùng bcrypt (tốn CPU) => giới hạn chặt theo IP
+	authenLogin := middlewares.RatePolicy{
+		Name:    "authen:login",
+		Default: middlewares.RateLimit{Limit: 10, Period: time.Minute},
+	}.FromEnv()
+
+	// Đăng ký/gửi lại email xác thực là endpoint public => giới hạn theo IP
+	authenRegister := middlewares.RatePolicy{
+		Name:    "authen:register",
+		Default: middlewares.RateLimit{Limit: 5, Period: time.Minute},
+	}.FromEnv()
+
+	// Endpoint GraphQL: mỗi field tự kiểm tra role và scope (query cần users:read, mutation cần users:write nếu dùng API key);
+	// rate limit theo loại operation giống REST. Playground chỉ bật khi không chạy release mode
+	r.POST("/graphql", RequireRoles(ADMIN, STAFF, CUSTOMER), gc.ByOperation(Limit(usersRead), Limit(usersWrite)), gc.GraphQL)
+	if gin.Mode() != gin.ReleaseMode {
+		r.GET("/graphql", gc.Playground)
 	}
-	authenSvc := services.NewAuthService(db, cfg, ur)
-	ac := controllers.NewAuthController(authenSvc)
 
 	api := r.Group("/api")
 	{
@@ -61,15 +144,92 @@ func SetupRoutes(r *gin.Engine, db *gorm.DB) {
 		{
 			users := v1.Group("/users")
 			{
-				users.POST("", RequireRoles(ADMIN, STAFF), uc.UsersCreate)
-				users.GET("", RequireRoles(ADMIN, STAFF, CUSTOMER), uc.UsersIndex)
-				users.GET("/:id", RequireRoles(ADMIN, STAFF, CUSTOMER), uc.UsersShow)
-				users.PUT("/:id", RequireRoles(ADMIN, STAFF, CUSTOMER), uc.UsersUpdate)
-				users.DELETE("/:id", RequireRoles(ADMIN, STAFF), uc.UsersDelete)
+				users.POST("", RequireRoles(ADMIN, STAFF), Scope(models.ScopeUsersWrite), Limit(usersWrite), Idempotent, uc.UsersCreate)
+				users.POST("/import", RequireRoles(ADMIN, STAFF), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersImport)
+				users.GET("/export", RequireRoles(ADMIN), Scope(models.ScopeUsersRead), Limit(usersRead), uc.UsersExport)
+				users.POST("/batch", RequireRoles(ADMIN), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersBatch)
+				users.GET("", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeUsersRead), Limit(usersRead), usersListCache.Handler(), uc.UsersIndex)
+				users.GET("/:id", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeUsersRead), Limit(usersRead), uc.UsersShow)
+				users.PUT("/:id", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersUpdate)
+				users.DELETE("/:id", RequireRoles(ADMIN, STAFF), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersDelete)
+				users.POST("/:id/activate", RequireRoles(ADMIN), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersActivate)
+				users.POST("/:id/disable", RequireRoles(ADMIN), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersDisable)
+				users.POST("/:id/lock", RequireRoles(ADMIN), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersLock)
+				users.POST("/:id/unlock", RequireRoles(ADMIN), Scope(models.ScopeUsersWrite), Limit(usersWrite), uc.UsersUnlock)
 			}
 			authen := v1.Group("/authen")
 			{
-				authen.POST("/login", ac.Login)
+				authen.POST("/login", Limit(authenLogin), ac.Login)
+				authen.POST("/register", Limit(authenRegister), rc.Register)
+				authen.GET("/verify", Limit(authenLogin), rc.VerifyEmail)
+				authen.POST("/verify/resend", Limit(authenRegister), rc.ResendVerification)
+				authen.GET("/oidc/:provider/login", Limit(authenLogin), oc.OIDCLogin)
+				authen.GET("/oidc/:provider/callback", Limit(authenLogin), oc.OIDCCallback)
+				// Hỗ trợ khách hàng: admin đăng nhập dưới danh nghĩa user khác (chỉ dùng JWT)
+				authen.POST("/impersonate/:id", RequireRoles(ADMIN), Scope(models.ScopeSession), ic.Impersonate)
+			}
+			v1.GET("/audit", RequireRoles(ADMIN), Scope(models.ScopeAuditRead), auc.AuditIndex)
+			// Metrics runtime (expvar): hit ratio của cache, ...
+			v1.GET("/metrics", RequireRoles(ADMIN), Scope(models.ScopeSession), gin.WrapH(expvar.Handler()))
+			v1.GET("/jobs/:id", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeJobsRead), jc.JobsShow)
+			// Thiết bị đang đăng nhập của chính user (chỉ dùng JWT)
+			me := v1.Group("/me", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeSession))
+			{
+				me.GET("/sessions", sc.SessionsIndex)
+				me.DELETE("/sessions/:id", sc.SessionsRevoke)
+			}
+			// API key: quản lý bằng JWT (key không tự tạo/thu hồi key khác được)
+			apiKeys := v1.Group("/api-keys", RequireRoles(ADMIN, STAFF, CUSTOMER), Scope(models.ScopeSession))
+			{
+				apiKeys.POST("", kc.APIKeysCreate)
+				apiKeys.GET("", kc.APIKeysIndex)
+				apiKeys.DELETE("/:id", kc.APIKeysRevoke)
+			}
+			// Quản lý tenant (công ty khách hàng): chỉ super admin
+			tenants := v1.Group("/tenants", RequireRoles(SUPER_ADMIN), Scope(models.ScopeSession))
+			{
+				tenants.POST("", tc.TenantsCreate)
+				tenants.GET("", tc.TenantsIndex)
+			}
+			// Organization/team: quyền chi tiết theo membership được kiểm tra trong service
+			orgs := v1.Group("/orgs", RequireRoles(ADMIN, STAFF, CUSTOMER))
+			{
+				orgs.POST("", Scope(models.ScopeOrgsWrite), orc.OrganizationsCreate)
+				orgs.GET("", Scope(models.ScopeOrgsRead), orc.OrganizationsIndex)
+				orgs.GET("/:id", Scope(models.ScopeOrgsRead), orc.OrganizationsShow)
+				orgs.PUT("/:id", Scope(models.ScopeOrgsWrite), orc.OrganizationsUpdate)
+				orgs.DELETE("/:id", Scope(models.ScopeOrgsWrite), orc.OrganizationsDelete)
+				orgs.GET("/:id/members", Scope(models.ScopeOrgsRead), orc.OrganizationMembersIndex)
+				orgs.PUT("/:id/members/:userId", Scope(models.ScopeOrgsWrite), orc.OrganizationMembersUpdate)
+				orgs.DELETE("/:id/members/:userId", Scope(models.ScopeOrgsWrite), orc.OrganizationMembersDelete)
+				orgs.POST("/:id/teams", Scope(models.ScopeOrgsWrite), tmc.TeamsCreate)
+				orgs.GET("/:id/teams", Scope(models.ScopeOrgsRead), tmc.TeamsIndex)
+				orgs.POST("/:id/invitations", Scope(models.ScopeOrgsWrite), ivc.InvitationsCreate)
+				orgs.GET("/:id/invitations", Scope(models.ScopeOrgsRead), ivc.InvitationsIndex)
+			}
+			teams := v1.Group("/teams", RequireRoles(ADMIN, STAFF, CUSTOMER))
+			{
+				teams.PUT("/:id", Scope(models.ScopeOrgsWrite), tmc.TeamsUpdate)
+				teams.DELETE("/:id", Scope(models.ScopeOrgsWrite), tmc.TeamsDelete)
+				teams.GET("/:id/members", Scope(models.ScopeOrgsRead), tmc.TeamMembersIndex)
+				teams.PUT("/:id/members/:userId", Scope(models.ScopeOrgsWrite), tmc.TeamMembersUpdate)
+				teams.DELETE("/:id/members/:userId", Scope(models.ScopeOrgsWrite), tmc.TeamMembersDelete)
+			}
+			invitations := v1.Group("/invitations", RequireRoles(ADMIN, STAFF, CUSTOMER))
+			{
+				// Chấp nhận lời mời chỉ dùng JWT (key không tự tham gia organization)
+				invitations.POST("/accept", Scope(models.ScopeSession), ivc.InvitationsAccept)
+				invitations.DELETE("/:id", Scope(models.ScopeOrgsWrite), ivc.InvitationsRevoke)
+			}
+			webhooks := v1.Group("/webhooks")
+			{
+				webhooks.POST("", RequireRoles(ADMIN), Scope(models.ScopeWebhooksWrite), wc.WebhooksCreate)
+				webhooks.GET("", RequireRoles(ADMIN), Scope(models.ScopeWebhooksRead), wc.WebhooksIndex)
+				webhooks.GET("/:id", RequireRoles(ADMIN), Scope(models.ScopeWebhooksRead), wc.WebhooksShow)
+				webhooks.PUT("/:id", RequireRoles(ADMIN), Scope(models.ScopeWebhooksWrite), wc.WebhooksUpdate)
+				webhooks.DELETE("/:id", RequireRoles(ADMIN), Scope(models.ScopeWebhooksWrite), wc.WebhooksDelete)
+				webhooks.GET("/:id/deliveries", RequireRoles(ADMIN), Scope(models.ScopeWebhooksRead), wc.WebhooksDeliveries)
+				webhooks.POST("/:id/test", RequireRoles(ADMIN), Scope(models.ScopeWebhooksWrite), wc.WebhooksTest)
 			}
 		}
 	}
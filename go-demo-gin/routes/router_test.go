@@ -4,6 +4,7 @@ import (
 	"go-demo-gin/initializers"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +34,8 @@ func routeSet(rs []gin.RouteInfo) map[string]bool {
 
 func TestSetupRoutes(t *testing.T) {
 	t.Setenv("SECRET", "test-secret") // tránh lỗi thiếu SECRET khi khởi tạo AuthService
+	// Access log ghi vào thư mục tạm, không ghi vào thư mục mã nguồn
+	t.Setenv("ACCESS_LOG_FILE", filepath.Join(t.TempDir(), "access.log"))
 	gin.SetMode(gin.TestMode)
 
 	r := gin.New()
@@ -45,13 +48,71 @@ func TestSetupRoutes(t *testing.T) {
 	expected := []string{
 		"GET /swagger/*any",
 
+		"POST /graphql",
+		"GET /graphql",
+
 		"POST /api/v1/users",
+		"POST /api/v1/users/import",
+		"GET /api/v1/users/export",
+		"POST /api/v1/users/batch",
 		"GET /api/v1/users",
 		"GET /api/v1/users/:id",
 		"PUT /api/v1/users/:id",
 		"DELETE /api/v1/users/:id",
+		"POST /api/v1/users/:id/activate",
+		"POST /api/v1/users/:id/disable",
+		"POST /api/v1/users/:id/lock",
+		"POST /api/v1/users/:id/unlock",
 
 		"POST /api/v1/authen/login",
+		"POST /api/v1/authen/register",
+		"GET /api/v1/authen/verify",
+		"POST /api/v1/authen/verify/resend",
+		"GET /api/v1/authen/oidc/:provider/login",
+		"GET /api/v1/authen/oidc/:provider/callback",
+		"POST /api/v1/authen/impersonate/:id",
+
+		"GET /api/v1/audit",
+		"GET /api/v1/metrics",
+		"GET /api/v1/jobs/:id",
+
+		"GET /api/v1/me/sessions",
+		"DELETE /api/v1/me/sessions/:id",
+
+		"POST /api/v1/api-keys",
+		"GET /api/v1/api-keys",
+		"DELETE /api/v1/api-keys/:id",
+
+		"POST /api/v1/tenants",
+		"GET /api/v1/tenants",
+
+		"POST /api/v1/orgs",
+		"GET /api/v1/orgs",
+		"GET /api/v1/orgs/:id",
+		"PUT /api/v1/orgs/:id",
+		"DELETE /api/v1/orgs/:id",
+		"GET /api/v1/orgs/:id/members",
+		"PUT /api/v1/orgs/:id/members/:userId",
+		"DELETE /api/v1/orgs/:id/members/:userId",
+		"POST /api/v1/orgs/:id/teams",
+		"GET /api/v1/orgs/:id/teams",
+		"POST /api/v1/orgs/:id/invitations",
+		"GET /api/v1/orgs/:id/invitations",
+		"PUT /api/v1/teams/:id",
+		"DELETE /api/v1/teams/:id",
+		"GET /api/v1/teams/:id/members",
+		"PUT /api/v1/teams/:id/members/:userId",
+		"DELETE /api/v1/teams/:id/members/:userId",
+		"POST /api/v1/invitations/accept",
+		"DELETE /api/v1/invitations/:id",
+
+		"POST /api/v1/webhooks",
+		"GET /api/v1/webhooks",
+		"GET /api/v1/webhooks/:id",
+		"PUT /api/v1/webhooks/:id",
+		"DELETE /api/v1/webhooks/:id",
+		"GET /api/v1/webhooks/:id/deliveries",
+		"POST /api/v1/webhooks/:id/test",
 	}
 
 	for _, ep := range expected {
@@ -61,6 +122,7 @@ func TestSetupRoutes(t *testing.T) {
 
 func TestProtectedRoutes(t *testing.T) {
 	t.Setenv("SECRET", "test-secret")
+	t.Setenv("ACCESS_LOG_FILE", filepath.Join(t.TempDir(), "access.log"))
 	gin.SetMode(gin.TestMode)
 
 	r := gin.New()
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	apiKeyRequest "go-demo-gin/requests/apikey"
+	apiKeyResponse "go-demo-gin/responses/apikey"
+	"go-demo-gin/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	apiKeyPrefix         = "gdg_"
+	defaultAPIKeyTTLDays = 90
+	apiKeyTouchInterval  = time.Minute // last_used_at chỉ cập nhật tối đa 1 lần/phút cho mỗi key
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *models.APIKey) error
+	FindByID(ctx context.Context, id uint) (*models.APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	ListByUser(ctx context.Context, userID uint, pag *pkg.Pagination) ([]models.APIKey, int64, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastUsed(ctx context.Context, id uint, at, notAfter time.Time) error
+}
+
+// KeyOwnerFinder: tra cứu user sở hữu key (thường là repo.CachedUserRepo)
+type KeyOwnerFinder interface {
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+}
+
+type APIKeyService struct {
+	keys  APIKeyRepository
+	users KeyOwnerFinder
+}
+
+func NewAPIKeyService(kr APIKeyRepository, users KeyOwnerFinder) *APIKeyService {
+	return &APIKeyService{keys: kr, users: users}
+}
+
+// CreateKey tạo key cho user đang đăng nhập; key đầy đủ chỉ trả về 1 lần trong response
+func (s *APIKeyService) CreateKey(ctx context.Context, in *apiKeyRequest.APIKeyCreate) (*apiKeyResponse.APIKeyDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create api key service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	// Key tạo khi impersonate sẽ sống lâu hơn token impersonation => không cho tạo
+	if err := forbidWhileImpersonating(ctx); err != nil {
+		return nil, err
+	}
+	days := in.ExpiresInDays
+	if days == 0 {
+		days = defaultAPIKeyTTLDays
+	}
+
+	prefix, raw := newAPIKey()
+	key := models.APIKey{
+		UserID:    me.ID,
+		Name:      in.Name,
+		Prefix:    prefix,
+		Hash:      hashAPIKey(raw),
+		Scopes:    strings.Join(in.Scopes, " "),
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := s.keys.Create(ctx, &key); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+
+	detail := toAPIKeyDetail(&key)
+	detail.Key = raw
+	return &detail, nil
+}
+
+// GetKeyList: các key của user đang đăng nhập
+func (s *APIKeyService) GetKeyList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of api keys service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	keys, total, err := s.keys.ListByUser(ctx, me.ID, pag)
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	list := make([]apiKeyResponse.APIKeyDetail, 0, len(keys))
+	for i := range keys {
+		list = append(list, toAPIKeyDetail(&keys[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// RevokeKey thu hồi key của chính mình (admin thu hồi được key của bất kỳ ai trong tenant); thu hồi lại => không lỗi
+func (s *APIKeyService) RevokeKey(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke api key service", nil)
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	key, err := s.keys.FindByID(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	// Key của người khác => báo không tồn tại (không lộ ID)
+	me := utils.InformationFrom(ctx)
+	if me == nil || (key.UserID != me.ID && !me.Role.IsAdmin()) {
+		return apperror.NotFound(utils.NOT_FOUND, nil)
+	}
+	// Admin chỉ thấy key của user cùng tenant (FindByID lọc theo tenant của request)
+	if key.UserID != me.ID {
+		if _, err := s.users.FindByID(ctx, key.UserID); err != nil {
+			return apperror.NotFound(utils.NOT_FOUND, err)
+		}
+	}
+
+	if err := s.keys.Revoke(ctx, key.ID, time.Now()); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+// AuthenticateKey xác thực key đầy đủ, trả về user sở hữu và key (để kiểm tra scope)
+func (s *APIKeyService) AuthenticateKey(ctx context.Context, raw string) (*models.User, *models.APIKey, error) {
+	prefix, ok := parseAPIKey(raw)
+	if !ok {
+		return nil, nil, apperror.Unauthorized(utils.INVALID_API_KEY, nil)
+	}
+	key, err := s.keys.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperror.Unauthorized(utils.INVALID_API_KEY, err)
+		}
+		return nil, nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKey(raw))) != 1 || key.RevokedAt != nil {
+		return nil, nil, apperror.Unauthorized(utils.INVALID_API_KEY, nil)
+	}
+	now := time.Now()
+	if now.After(key.ExpiresAt) {
+		return nil, nil, apperror.Unauthorized(utils.API_KEY_EXPIRED, nil)
+	}
+
+	// Chưa biết tenant trước khi có chủ của key => tìm trên mọi tenant (middleware giới hạn theo tenant của user sau đó)
+	user, err := s.users.FindByID(utils.AllTenants(ctx), key.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) { // user đã bị xoá
+			return nil, nil, apperror.Unauthorized(utils.INVALID_API_KEY, err)
+		}
+		return nil, nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	// Ghi lần dùng gần nhất; lỗi không chặn request
+	if err := s.keys.TouchLastUsed(context.WithoutCancel(ctx), key.ID, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Update api key last used failed: "+err.Error(), logrus.Fields{"api_key_id": key.ID})
+	}
+	return user, key, nil
+}
+
+// Key dạng "gdg_<16 hex>_<bí mật>"; prefix "gdg_<16 hex>" lưu nguyên văn để tra cứu
+func newAPIKey() (prefix, raw string) {
+	id := make([]byte, 8)
+	secret := make([]byte, 32)
+	_, _ = rand.Read(id)
+	_, _ = rand.Read(secret)
+	prefix = apiKeyPrefix + hex.EncodeToString(id)
+	return prefix, prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+}
+
+func parseAPIKey(raw string) (string, bool) {
+	n := len(apiKeyPrefix) + 16
+	if len(raw) <= n+1 || !strings.HasPrefix(raw, apiKeyPrefix) || raw[n] != '_' {
+		return "", false
+	}
+	return raw[:n], true
+}
+
+// Key có entropy cao => SHA-256 là đủ (không cần bcrypt, vốn tốn CPU cho mỗi request)
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKeyDetail(k *models.APIKey) apiKeyResponse.APIKeyDetail {
+	return apiKeyResponse.APIKeyDetail{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     strings.Fields(k.Scopes),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
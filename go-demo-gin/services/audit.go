@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	auditRequest "go-demo-gin/requests/audit"
+	auditResponse "go-demo-gin/responses/audit"
+	"go-demo-gin/utils"
+	"regexp"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Giá trị thay thế cho các trường nhạy cảm (password) trong audit
+const maskedValue = "******"
+
+// Sort cho list audit: chỉ các cột cho phép + [asc|desc] (được đưa thẳng vào ORDER BY)
+var auditSortPattern = regexp.MustCompile(`(?i)^(id|created_at|actor_id|impersonator_id|action|target_type|target_id)( (asc|desc))?$`)
+
+type AuditRepository interface {
+	Create(ctx context.Context, e *models.AuditEvent) error
+	List(ctx context.Context, pag *pkg.Pagination, f *auditRequest.AuditFilter) ([]models.AuditEvent, int64, error)
+}
+
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+type AuditService struct {
+	db        *gorm.DB
+	auditRepo AuditRepository
+}
+
+func NewAuditService(db *gorm.DB, ar AuditRepository) *AuditService {
+	return &AuditService{db: db, auditRepo: ar}
+}
+
+func (s *AuditService) GetAuditList(ctx context.Context, pag *pkg.Pagination, f *auditRequest.AuditFilter) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of audit events service", nil)
+
+	if pag.Sort != "" && !auditSortPattern.MatchString(pag.Sort) {
+		return nil, apperror.Validation(utils.VALIDATION_FAILED, map[string]*i18n.Message{"sort": utils.INVALID_VALUE})
+	}
+
+	// Query
+	events, total, err := s.auditRepo.List(ctx, pag, f)
+	if err != nil {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on audit list: "+err.Error(), nil)
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	// Mapper
+	list := make([]auditResponse.AuditEvent, 0, len(events))
+	for _, e := range events {
+		list = append(list, auditResponse.AuditEvent{
+			ID:               e.ID,
+			CreatedAt:        e.CreatedAt,
+			ActorID:          e.ActorID,
+			ActorName:        e.ActorName,
+			ImpersonatorID:   e.ImpersonatorID,
+			ImpersonatorName: e.ImpersonatorName,
+			Action:           string(e.Action),
+			TargetType:       e.TargetType,
+			TargetID:         e.TargetID,
+			Changes:          json.RawMessage(e.Changes),
+			RequestID:        e.RequestID,
+			IP:               e.IP,
+		})
+	}
+
+	// Assign results to Pagination Struct
+	pag.TotalRows = total
+	pag.Result = list
+
+	return pag, nil
+}
+
+// recordUserAudit ghi audit cho user trong cùng transaction (ctx phải chứa tx qua utils.WithTx)
+func recordUserAudit(ctx context.Context, ar AuditRepository, action models.AuditAction, targetID uint, before, after *models.User) error {
+	changes, err := json.Marshal(diffUser(before, after))
+	if err != nil {
+		return err
+	}
+
+	event := models.AuditEvent{
+		Action:     action,
+		TargetType: "user",
+		TargetID:   targetID,
+		Changes:    string(changes),
+	}
+
+	// Người thực hiện (actor) lấy từ user đã xác thực
+	if actor := utils.InformationFrom(ctx); actor != nil {
+		event.ActorID = &actor.ID
+		event.ActorName = actor.Username
+	}
+	// Thao tác khi đang impersonate => ghi thêm admin thực sự đứng sau
+	if imp := utils.ImpersonatorFrom(ctx); imp != nil {
+		event.ImpersonatorID = &imp.ID
+		event.ImpersonatorName = imp.Username
+	}
+	// Request ID và IP lấy từ access log
+	if req := utils.RequestInfoFrom(ctx); req != nil {
+		event.RequestID = req.ID
+		event.IP = req.IP
+	}
+
+	return ar.Create(ctx, &event)
+}
+
+// Chụp lại các trường của user dùng cho việc so sánh trước/sau
+func userSnapshot(u *models.User) map[string]any {
+	m := map[string]any{
+		"username":      nil,
+		"email":         nil,
+		"password":      nil,
+		"full_name":     nil,
+		"birthday":      nil,
+		"role":          nil,
+		"status":        nil,
+		"status_reason": nil,
+	}
+	if u == nil {
+		return m
+	}
+	m["username"] = u.Username
+	if u.Email != nil {
+		m["email"] = *u.Email
+	}
+	m["password"] = u.Password
+	m["role"] = string(u.Role)
+	if u.Name.Valid {
+		m["full_name"] = u.Name.String
+	}
+	if u.Birthday != nil {
+		m["birthday"] = u.Birthday.Format("2006-01-02")
+	}
+	if u.Status != "" {
+		m["status"] = string(u.Status)
+	}
+	if u.StatusReason != "" {
+		m["status_reason"] = u.StatusReason
+	}
+	return m
+}
+
+// diffUser trả về các trường bị thay đổi; password luôn được che giá trị
+func diffUser(before, after *models.User) map[string]AuditChange {
+	b, a := userSnapshot(before), userSnapshot(after)
+	changes := make(map[string]AuditChange)
+	for field := range b {
+		if b[field] == a[field] {
+			continue
+		}
+		change := AuditChange{Before: b[field], After: a[field]}
+		if field == "password" {
+			if change.Before != nil {
+				change.Before = maskedValue
+			}
+			if change.After != nil {
+				change.After = maskedValue
+			}
+		}
+		changes[field] = change
+	}
+	return changes
+}
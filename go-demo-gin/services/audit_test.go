@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"go-demo-gin/models"
+	userRequest "go-demo-gin/requests/user"
+	"go-demo-gin/utils"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffUser_OnlyChangedFieldsAndMaskedPassword(t *testing.T) {
+	oldEmail, newEmail := "alice@example.com", "alice@corp.example.com"
+	before := &models.User{Username: "alice", Password: "hash-1", Role: models.RoleStaff, Email: &oldEmail}
+	after := &models.User{Username: "alice", Password: "hash-2", Role: models.RoleAdmin, Email: &newEmail}
+
+	changes := diffUser(before, after)
+
+	assert.Len(t, changes, 3)
+	assert.Equal(t, AuditChange{Before: "staff", After: "admin"}, changes["role"])
+	assert.Equal(t, AuditChange{Before: oldEmail, After: newEmail}, changes["email"])
+	assert.Equal(t, AuditChange{Before: maskedValue, After: maskedValue}, changes["password"])
+
+	// Tạo mới: before rỗng => before của mọi trường là nil, password vẫn bị che
+	created := diffUser(nil, after)
+	assert.Equal(t, AuditChange{Before: nil, After: maskedValue}, created["password"])
+	assert.Equal(t, AuditChange{Before: nil, After: "alice"}, created["username"])
+	assert.NotContains(t, created, "full_name") // nil -> nil không phải thay đổi
+}
+
+func TestUserService_UpdateRecordsAuditDiff(t *testing.T) {
+	db, s := setupUserService(t)
+	admin := models.User{Username: "admin", Password: "x", Role: models.RoleAdmin}
+	require.NoError(t, db.Create(&admin).Error)
+	target := models.User{Username: "alice", Password: "old-hash", Role: models.RoleStaff}
+	require.NoError(t, db.Create(&target).Error)
+
+	ctx := utils.WithInformation(context.Background(), &admin)
+	ctx = utils.WithRequestInfo(ctx, &utils.RequestInfo{ID: "req-1", IP: "10.0.0.1"})
+	_, err := s.UpdateUser(ctx, &userRequest.UserUpdate{Pass: "n3w.Secret!", Name: "Alice", Role: "admin", Date: "2000-01-02"}, strconv.Itoa(int(target.ID)))
+	require.NoError(t, err)
+
+	var event models.AuditEvent
+	require.NoError(t, db.Where("target_id = ?", target.ID).First(&event).Error)
+	assert.Equal(t, models.AuditUserRoleChange, event.Action)
+	assert.Equal(t, admin.ID, *event.ActorID)
+	assert.Equal(t, "admin", event.ActorName)
+	assert.Equal(t, "req-1", event.RequestID)
+	assert.Equal(t, "10.0.0.1", event.IP)
+
+	var changes map[string]AuditChange
+	require.NoError(t, json.Unmarshal([]byte(event.Changes), &changes))
+	assert.Equal(t, AuditChange{Before: "staff", After: "admin"}, changes["role"])
+	assert.Equal(t, AuditChange{Before: nil, After: "Alice"}, changes["full_name"])
+	assert.Equal(t, AuditChange{Before: nil, After: "2000-01-02"}, changes["birthday"])
+	assert.Equal(t, AuditChange{Before: maskedValue, After: maskedValue}, changes["password"])
+	assert.NotContains(t, changes, "username")
+
+	// Hash mật khẩu (cũ lẫn mới) không bao giờ xuất hiện trong audit
+	var updated models.User
+	require.NoError(t, db.First(&updated, target.ID).Error)
+	assert.False(t, strings.Contains(event.Changes, "old-hash"))
+	assert.False(t, strings.Contains(event.Changes, updated.Password))
+}
@@ -3,15 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/password"
 	authenRequest "go-demo-gin/requests/authen"
 	"go-demo-gin/utils"
-	"net/http"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -25,54 +25,226 @@ type AuthService struct {
 	db       *gorm.DB
 	cfg      AuthConfig
 	userRepo UserRepository
+	sessions *SessionService
 }
 
-func NewAuthService(db *gorm.DB, cfg AuthConfig, ur UserRepository) *AuthService {
+func NewAuthService(db *gorm.DB, cfg AuthConfig, ur UserRepository, ss *SessionService) *AuthService {
 	return &AuthService{
 		db:       db,
 		cfg:      cfg,
 		userRepo: ur,
+		sessions: ss,
 	}
 }
 
-func (s *AuthService) Authenticate(ctx context.Context, in *authenRequest.LoginForm) (*string, int, string) {
+func (s *AuthService) Authenticate(ctx context.Context, in *authenRequest.LoginForm) (*string, error) {
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the login service", nil)
 
-	// Lấy localizer cho i18n
-	localizer := utils.LocalizerFrom(ctx)
-
 	// Look up requested user
-	// lấy user qua repo (context-aware)
+	// lấy user kèm hash mật khẩu qua repo (context-aware, không qua cache)
 	ctxTx := utils.WithTx(ctx, nil)
-	user, err := s.userRepo.FindByUsername(ctxTx, in.Username)
+	user, err := s.userRepo.FindCredentials(ctxTx, in.Username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, http.StatusUnauthorized, utils.LoadI18nMessage(localizer, utils.INVALID_USERNAME_PASSWORD, nil)
+			return nil, apperror.Unauthorized(utils.INVALID_USERNAME_PASSWORD, err)
 		}
 		utils.LogCtx(ctx, logrus.InfoLevel, "DB error on login", nil)
-		return nil, http.StatusInternalServerError, utils.LoadI18nMessage(localizer, utils.INTERNAL_ERROR, nil)
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	// Compare sent in pass with saved user pass hash (bcrypt hoặc Argon2id)
+	match, needsRehash, err := password.Verify(user.Password, in.Password)
+	if err != nil || !match {
+		return nil, apperror.Unauthorized(utils.INVALID_USERNAME_PASSWORD, err)
+	}
+	// Chỉ báo trạng thái tài khoản khi đã đúng mật khẩu
+	if code := utils.AccountStatusError(user.Status); code != nil {
+		return nil, apperror.Forbidden(code, nil)
+	}
+	// Hash cũ (bcrypt, tham số Argon2 cũ) => hash lại bằng cấu hình hiện tại; lỗi không chặn đăng nhập
+	if needsRehash {
+		s.rehash(ctx, user, in.Password)
 	}
 
-	// Compare sent in pass with saved user pass hash
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(in.Password))
+	return s.issueToken(ctx, user)
+}
+
+func (s *AuthService) rehash(ctx context.Context, user *models.User, plain string) {
+	hash, err := password.Hash(plain)
+	if err == nil {
+		u := *user
+		u.Password = hash
+		err = s.userRepo.UpdatePasswordHash(ctx, &u)
+	}
 	if err != nil {
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.INVALID_USERNAME_PASSWORD, nil)
+		utils.LogCtx(ctx, logrus.WarnLevel, "Rehash password failed: "+err.Error(), logrus.Fields{"user_id": user.ID})
 	}
+}
 
-	// Generate a jwt token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+// Refresh đổi token còn hạn lấy token mới (user phải còn tồn tại và đang active);
+// token mới thuộc cùng session (session bị thu hồi => không refresh được)
+func (s *AuthService) Refresh(ctx context.Context, tokenStr string) (*string, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the refresh token service", nil)
+
+	claims, err := s.parseToken(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+	// Token impersonation có hạn ngắn cố định, không gia hạn
+	if isImpersonation(claims) {
+		return nil, apperror.Forbidden(utils.IMPERSONATION_FORBIDDEN, nil)
+	}
+	username, _ := claims["sub"].(string)
+
+	ctxTx := utils.WithTx(TenantCtxFromClaims(ctx, claims), nil)
+	user, err := s.userRepo.FindByUsername(ctxTx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	// Username đã bị xoá rồi tạo lại (user khác) => token cũ không còn hiệu lực
+	if id, ok := UserIDFromClaims(claims); !ok || id != user.ID {
+		return nil, apperror.Unauthorized(utils.INVALID_CLAIM, nil)
+	}
+	if code := utils.AccountStatusError(user.Status); code != nil {
+		return nil, apperror.Forbidden(code, nil)
+	}
+
+	sid, ok := SessionIDFromClaims(claims)
+	if !ok || s.sessions == nil {
+		// Token cũ (trước khi có session) => cấp token mới kèm session mới
+		return s.issueToken(ctx, user)
+	}
+	if err := s.sessions.Validate(ctx, sid, user.ID); err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(s.cfg.AccessTTL)
+	if err := s.sessions.Extend(ctx, sid, expiresAt); err != nil {
+		return nil, err
+	}
+	return s.signToken(user, sid, expiresAt)
+}
+
+// VerifyToken kiểm tra chữ ký/hạn của JWT, session (nếu token có "sid") và trả về username (claim "sub"),
+// ID của user (claim "id") cùng tenant của user (claim "tid", token cũ => tenant mặc định).
+// Caller phải đối chiếu ID với user tìm được theo username.
+func (s *AuthService) VerifyToken(ctx context.Context, tokenStr string) (string, uint, uint, error) {
+	claims, err := s.parseToken(tokenStr)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	// gRPC không mang được danh tính admin (claim "act") => không nhận token impersonation
+	if isImpersonation(claims) {
+		return "", 0, 0, apperror.Forbidden(utils.IMPERSONATION_FORBIDDEN, nil)
+	}
+	username, _ := claims["sub"].(string)
+	id, ok := UserIDFromClaims(claims)
+	if !ok {
+		return "", 0, 0, apperror.Unauthorized(utils.INVALID_CLAIM, nil)
+	}
+	if sid, ok := SessionIDFromClaims(claims); ok && s.sessions != nil {
+		if err := s.sessions.Validate(ctx, sid, id); err != nil {
+			return "", 0, 0, err
+		}
+	}
+	return username, id, TenantIDFromClaims(claims), nil
+}
+
+func (s *AuthService) parseToken(tokenStr string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
+		return s.cfg.JWTKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, apperror.Unauthorized(utils.INVALID_TOKEN, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, apperror.Unauthorized(utils.INVALID_CLAIM, nil)
+	}
+	if username, _ := claims["sub"].(string); username == "" {
+		return nil, apperror.Unauthorized(utils.INVALID_CLAIM, nil)
+	}
+	return claims, nil
+}
+
+// TenantCtxFromClaims: context giới hạn trong tenant của token (claim "tid").
+// Không bao giờ dùng tenant của request: token cũ (trước multi-tenancy) thuộc tenant mặc định.
+func TenantCtxFromClaims(ctx context.Context, claims jwt.MapClaims) context.Context {
+	return utils.WithTenant(ctx, utils.TenantScope{ID: TenantIDFromClaims(claims)})
+}
+
+// TenantIDFromClaims: claim "tid"; token cũ không có claim này => models.DefaultTenantID
+func TenantIDFromClaims(claims map[string]any) uint {
+	if tid, ok := claims["tid"].(float64); ok && tid > 0 {
+		return uint(tid)
+	}
+	return models.DefaultTenantID
+}
+
+// UserIDFromClaims: claim "id" (ID của user trong "sub")
+func UserIDFromClaims(claims map[string]any) (uint, bool) {
+	id, ok := claims["id"].(float64)
+	if !ok || id <= 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// SessionIDFromClaims: claim "sid" (ID của models.Session); token cũ không có claim này
+func SessionIDFromClaims(claims jwt.MapClaims) (uint, bool) {
+	sid, ok := claims["sid"].(float64)
+	if !ok || sid <= 0 {
+		return 0, false
+	}
+	return uint(sid), true
+}
+
+// issueToken: mỗi lần đăng nhập (mật khẩu, OIDC) là 1 session mới
+func (s *AuthService) issueToken(ctx context.Context, user *models.User) (*string, error) {
+	expiresAt := time.Now().Add(s.cfg.AccessTTL)
+	var sid uint
+	if s.sessions != nil {
+		sess, err := s.sessions.Start(ctx, user.ID, expiresAt)
+		if err != nil {
+			return nil, err
+		}
+		sid = sess.ID
+	}
+	return s.signToken(user, sid, expiresAt)
+}
+
+func (s *AuthService) signToken(user *models.User, sid uint, expiresAt time.Time) (*string, error) {
+	return s.sign(s.baseClaims(user, sid, expiresAt))
+}
+
+func (s *AuthService) baseClaims(user *models.User, sid uint, expiresAt time.Time) jwt.MapClaims {
+	claims := jwt.MapClaims{
 		"sub": user.Username,
 		"id":  user.ID,
-		"exp": time.Now().Add(s.cfg.AccessTTL).Unix(),
+		"tid": user.TenantID,
+		"exp": expiresAt.Unix(),
 		"iss": s.cfg.Issuer,
-	})
+	}
+	if sid != 0 {
+		claims["sid"] = sid
+	}
+	return claims
+}
+
+func (s *AuthService) sign(claims jwt.MapClaims) (*string, error) {
+	// Generate a jwt token
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign and get the complete encoded token as a string using the secret
-	tokenString, err := token.SignedString([]byte(os.Getenv("SECRET")))
+	tokenString, err := token.SignedString(s.cfg.JWTKey)
 	if err != nil {
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.FAIL_CREATE_TOKEN, nil)
+		return nil, apperror.Internal(utils.FAIL_CREATE_TOKEN, err)
 	}
 
-	return &tokenString, http.StatusOK, ""
+	return &tokenString, nil
 }
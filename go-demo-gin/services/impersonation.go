@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	impersonationResponse "go-demo-gin/responses/impersonation"
+	"go-demo-gin/utils"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Thời hạn mặc định của token impersonation (ngắn, không refresh được)
+const DefaultImpersonationTTL = 15 * time.Minute
+
+// ImpersonationService: admin (hỗ trợ khách hàng) đăng nhập dưới danh nghĩa user khác.
+// Token mang cả 2 danh tính: "sub"/"id" là user bị impersonate, "act" là admin thực sự;
+// token dùng chung session ("sid") của admin => admin đăng xuất thì token cũng hết hiệu lực.
+type ImpersonationService struct {
+	auth      *AuthService
+	userRepo  UserRepository
+	auditRepo AuditRepository
+	ttl       time.Duration
+}
+
+func NewImpersonationService(auth *AuthService, ur UserRepository, ar AuditRepository, ttl time.Duration) *ImpersonationService {
+	if ttl <= 0 {
+		ttl = DefaultImpersonationTTL
+	}
+	return &ImpersonationService{auth: auth, userRepo: ur, auditRepo: ar, ttl: ttl}
+}
+
+func (s *ImpersonationService) Impersonate(ctx context.Context, idStr string) (*impersonationResponse.ImpersonationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the impersonate service", nil)
+
+	actor := utils.InformationFrom(ctx)
+	if actor == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	// Không impersonate lồng nhau
+	if err := forbidWhileImpersonating(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	target, err := s.userRepo.FindByID(utils.WithTx(ctx, nil), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	// Chỉ impersonate user thường đang active (không phải chính mình, không phải admin khác)
+	if target.ID == actor.ID || target.Role.IsAdmin() || utils.AccountStatusError(target.Status) != nil {
+		return nil, apperror.Forbidden(utils.CANNOT_IMPERSONATE, nil)
+	}
+
+	// Ghi audit trước khi cấp token: không ghi được => không cấp
+	if err := recordUserAudit(ctx, s.auditRepo, models.AuditUserImpersonate, target.ID, target, target); err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	expiresAt := time.Now().Add(s.ttl)
+	claims := s.auth.baseClaims(target, utils.SessionIDFrom(ctx), expiresAt)
+	claims["act"] = map[string]any{"sub": actor.Username, "id": actor.ID, "tid": actor.TenantID}
+	token, err := s.auth.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogCtx(ctx, logrus.WarnLevel, "Impersonation started", logrus.Fields{
+		"impersonator_id": actor.ID,
+		"target_id":       target.ID,
+		"expires_at":      expiresAt,
+	})
+	return &impersonationResponse.ImpersonationDetail{
+		Token:     *token,
+		ExpiresAt: expiresAt,
+		UserID:    target.ID,
+		Username:  target.Username,
+	}, nil
+}
+
+// Token impersonation (có claim "act") không được refresh và không dùng cho gRPC
+func isImpersonation(claims jwt.MapClaims) bool {
+	_, ok := claims["act"]
+	return ok
+}
+
+// Thao tác nhạy cảm (đổi mật khẩu, xoá tài khoản, tạo API key...) không được làm khi đang impersonate
+func forbidWhileImpersonating(ctx context.Context) error {
+	if utils.ImpersonatorFrom(ctx) != nil {
+		return apperror.Forbidden(utils.IMPERSONATION_FORBIDDEN, nil)
+	}
+	return nil
+}
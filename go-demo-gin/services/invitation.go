@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/pkg/mailer"
+	organizationRequest "go-demo-gin/requests/organization"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, inv *models.Invitation) error
+	FindByID(ctx context.Context, id uint) (*models.Invitation, error)
+	FindByTokenHash(ctx context.Context, hash string) (*models.Invitation, error)
+	ListPending(ctx context.Context, orgID uint, now time.Time, pag *pkg.Pagination) ([]models.Invitation, int64, error)
+	MarkAccepted(ctx context.Context, id, userID uint, at time.Time) (bool, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+}
+
+// InviteeFinder: tra cứu user theo email để biết người được mời đã là thành viên chưa
+type InviteeFinder interface {
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+}
+
+type InvitationConfig struct {
+	AcceptURL string        // link trong email; token (mang tenant của lời mời) được gắn vào query "token"
+	TTL       time.Duration // hạn của lời mời
+}
+
+func DefaultInvitationConfig() InvitationConfig {
+	return InvitationConfig{
+		AcceptURL: "http://localhost:8080/invitations/accept",
+		TTL:       7 * 24 * time.Hour,
+	}
+}
+
+// InvitationService: mời user (theo email) vào organization/team; người nhận đăng nhập
+// bằng tài khoản có đúng email đó và gửi token để chấp nhận.
+type InvitationService struct {
+	db      *gorm.DB
+	cfg     InvitationConfig
+	invites InvitationRepository
+	orgs    OrganizationRepository
+	teams   TeamRepository
+	users   InviteeFinder
+	authz   *MembershipAuthorizer
+	mailer  MailSender
+}
+
+func NewInvitationService(db *gorm.DB, cfg InvitationConfig, ir InvitationRepository, or OrganizationRepository, tr TeamRepository, users InviteeFinder, authz *MembershipAuthorizer, ms MailSender) *InvitationService {
+	return &InvitationService{db: db, cfg: cfg, invites: ir, orgs: or, teams: tr, users: users, authz: authz, mailer: ms}
+}
+
+// CreateInvitation: owner/manager của organization mời vào organization hoặc team bất kỳ;
+// owner/manager của team chỉ mời vào team của mình. Quyền owner chỉ owner mới mời được.
+// Gửi email lỗi không làm hỏng lời mời (có thể thu hồi và mời lại).
+func (s *InvitationService) CreateInvitation(ctx context.Context, in *organizationRequest.InvitationCreate, orgIDStr string) (*organizationResponse.InvitationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create invitation service", nil)
+
+	orgID, err := parseID(orgIDStr)
+	if err != nil {
+		return nil, err
+	}
+	org, actor, err := s.authz.RequireOrgMember(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if in.TeamID != nil {
+		team, err := s.teams.FindByID(ctx, *in.TeamID)
+		if err != nil || team.OrganizationID != orgID {
+			return nil, apperror.Validation(utils.VALIDATION_FAILED, map[string]*i18n.Message{"team_id": utils.INVALID_VALUE})
+		}
+		if actor, err = s.authz.TeamRole(ctx, team); err != nil {
+			return nil, err
+		}
+	}
+	role := models.MembershipRole(in.Role)
+	if err := RequireAssign(actor, "", role); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotMember(ctx, in.Email, orgID, in.TeamID); err != nil {
+		return nil, err
+	}
+
+	me := utils.InformationFrom(ctx)
+	raw := newInvitationToken(org.TenantID)
+	inv := models.Invitation{
+		OrganizationID: orgID,
+		TeamID:         in.TeamID,
+		Email:          strings.ToLower(in.Email),
+		Role:           role,
+		TokenHash:      hashInvitationToken(raw),
+		InvitedByID:    me.ID,
+		ExpiresAt:      time.Now().Add(s.cfg.TTL),
+	}
+	if err := s.invites.Create(ctx, &inv); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+	if err := s.sendInvitation(ctx, &inv, org, me, raw); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Send invitation email failed: "+err.Error(), logrus.Fields{"invitation_id": inv.ID})
+	}
+
+	d := toInvitationDetail(&inv)
+	return &d, nil
+}
+
+// Người được mời đã là thành viên (của team nếu mời vào team) => Conflict
+func (s *InvitationService) checkNotMember(ctx context.Context, email string, orgID uint, teamID *uint) error {
+	u, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if teamID != nil {
+		_, err = s.teams.FindMember(ctx, *teamID, u.ID)
+	} else {
+		_, err = s.orgs.FindMember(ctx, orgID, u.ID)
+	}
+	if err == nil {
+		return apperror.Conflict(utils.ALREADY_MEMBER, nil)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return nil
+}
+
+// GetInvitationList: lời mời đang chờ của organization (owner/manager)
+func (s *InvitationService) GetInvitationList(ctx context.Context, orgIDStr string, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of invitations service", nil)
+
+	orgID, err := parseID(orgIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgManager(ctx, orgID); err != nil {
+		return nil, err
+	}
+	invites, total, err := s.invites.ListPending(ctx, orgID, time.Now(), pag)
+	if err != nil {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on invitation list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+
+	list := make([]organizationResponse.InvitationDetail, 0, len(invites))
+	for i := range invites {
+		list = append(list, toInvitationDetail(&invites[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// RevokeInvitation: người có quyền mời (organization hoặc team) thu hồi lời mời chưa dùng; thu hồi lại => không lỗi
+func (s *InvitationService) RevokeInvitation(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke invitation service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return err
+	}
+	inv, err := s.invites.FindByID(ctx, id)
+	if err != nil {
+		return notFoundOr(err)
+	}
+	_, actor, err := s.authz.RequireOrgMember(ctx, inv.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if inv.TeamID != nil {
+		if team, err := s.teams.FindByID(ctx, *inv.TeamID); err == nil {
+			if actor, err = s.authz.TeamRole(ctx, team); err != nil {
+				return err
+			}
+		}
+	}
+	if err := RequireAssign(actor, "", inv.Role); err != nil {
+		return err
+	}
+	if err := s.invites.Revoke(ctx, inv.ID, time.Now()); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+// AcceptInvitation: user đang đăng nhập (đúng email được mời, đã xác thực) tham gia organization/team.
+// Đã là thành viên => giữ nguyên quyền hiện tại; lời mời vào team => tham gia organization với quyền member.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, in *organizationRequest.InvitationAccept) (*organizationResponse.OrganizationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the accept invitation service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	// Chấp nhận thay người khác khi impersonate => không cho
+	if err := forbidWhileImpersonating(ctx); err != nil {
+		return nil, err
+	}
+	// Lời mời tìm trong tenant ghi trong token, không theo tenant của request; user phải cùng tenant đó
+	tenantID := invitationTenant(in.Token)
+	if me.TenantID != tenantID {
+		return nil, apperror.Validation(utils.INVALID_INVITATION, nil)
+	}
+	ctx = utils.WithTenant(ctx, utils.TenantScope{ID: tenantID})
+	inv, err := s.invites.FindByTokenHash(ctx, hashInvitationToken(in.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.Validation(utils.INVALID_INVITATION, nil)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	now := time.Now()
+	if !inv.Pending(now) {
+		return nil, apperror.Validation(utils.INVALID_INVITATION, nil)
+	}
+	if me.Email == nil || !strings.EqualFold(*me.Email, inv.Email) {
+		return nil, apperror.Forbidden(utils.INVITATION_EMAIL_MISMATCH, nil)
+	}
+	// Email chưa xác thực => chưa chứng minh được sở hữu địa chỉ được mời
+	if me.EmailVerifiedAt == nil {
+		return nil, apperror.Forbidden(utils.EMAIL_NOT_VERIFIED, nil)
+	}
+
+	var org *models.Organization
+	var role models.MembershipRole
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		// Đánh dấu trước => 2 request cùng token chỉ 1 request thành công
+		ok, err := s.invites.MarkAccepted(ctxTx, inv.ID, me.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return apperror.Validation(utils.INVALID_INVITATION, nil)
+		}
+		if org, err = s.orgs.FindByID(ctxTx, inv.OrganizationID); err != nil {
+			return err
+		}
+
+		role = inv.Role
+		if inv.TeamID != nil {
+			role = models.MemberMember
+		}
+		if m, err := s.orgs.FindMember(ctxTx, inv.OrganizationID, me.ID); err == nil {
+			role = m.Role
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		} else if err := s.orgs.AddMember(ctxTx, &models.OrganizationMember{OrganizationID: inv.OrganizationID, UserID: me.ID, Role: role}); err != nil {
+			return err
+		}
+
+		if inv.TeamID == nil {
+			return nil
+		}
+		if _, err := s.teams.FindMember(ctxTx, *inv.TeamID, me.ID); err == nil {
+			return nil
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return s.teams.SaveMember(ctxTx, &models.TeamMember{TeamID: *inv.TeamID, UserID: me.ID, Role: inv.Role})
+	})
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+
+	d := toOrganizationDetail(org, role)
+	return &d, nil
+}
+
+// Gửi email chứa link chấp nhận, nội dung theo ngôn ngữ của request (người mời)
+func (s *InvitationService) sendInvitation(ctx context.Context, inv *models.Invitation, org *models.Organization, inviter *models.User, raw string) error {
+	link := s.cfg.AcceptURL + "?token=" + url.QueryEscape(raw)
+	data := map[string]any{
+		"Organization": org.Name,
+		"Inviter":      inviter.Username,
+		"Role":         string(inv.Role),
+		"Link":         link,
+		"ExpiresAt":    inv.ExpiresAt.UTC().Format(time.RFC1123),
+	}
+
+	localizer := utils.LocalizerFrom(ctx)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      inv.Email,
+		Subject: utils.LoadI18nMessage(localizer, utils.INVITATION_SUBJECT, data),
+		Body:    utils.LoadI18nMessage(localizer, utils.INVITATION_BODY, data),
+	})
+}
+
+// Token dạng "<tenant id>.<ngẫu nhiên>": link trong email mang theo tenant của lời mời.
+// Hash phủ cả tenant => sửa tenant trong token thì không tìm thấy lời mời.
+func newInvitationToken(tenantID uint) string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return strconv.FormatUint(uint64(tenantID), 10) + "." + base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Tenant ghi trong token; token cũ (không có tenant) => tenant mặc định
+func invitationTenant(raw string) uint {
+	prefix, _, ok := strings.Cut(raw, ".")
+	if !ok {
+		return models.DefaultTenantID
+	}
+	id, err := strconv.ParseUint(prefix, 10, 64)
+	if err != nil || id == 0 {
+		return models.DefaultTenantID
+	}
+	return uint(id)
+}
+
+// Token ngẫu nhiên, entropy cao => SHA-256 là đủ
+func hashInvitationToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func toInvitationDetail(i *models.Invitation) organizationResponse.InvitationDetail {
+	return organizationResponse.InvitationDetail{
+		ID:             i.ID,
+		OrganizationID: i.OrganizationID,
+		TeamID:         i.TeamID,
+		Email:          i.Email,
+		Role:           string(i.Role),
+		ExpiresAt:      i.ExpiresAt,
+		AcceptedAt:     i.AcceptedAt,
+		RevokedAt:      i.RevokedAt,
+		CreatedAt:      i.CreatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	jobResponse "go-demo-gin/responses/job"
+	"go-demo-gin/utils"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type JobRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.Job, error)
+}
+
+type JobService struct {
+	jobRepo JobRepository
+}
+
+func NewJobService(jr JobRepository) *JobService {
+	return &JobService{jobRepo: jr}
+}
+
+// GetJobById trả về trạng thái job. Admin xem được mọi job trong tenant của mình, user khác chỉ xem job do mình tạo.
+func (s *JobService) GetJobById(ctx context.Context, idStr string) (*jobResponse.JobDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get job by id service", nil)
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+
+	j, err := s.jobRepo.FindByID(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	// Không tiết lộ sự tồn tại của job của người khác => NotFound
+	if me := utils.InformationFrom(ctx); me == nil || (!me.Role.IsAdmin() && (j.CreatedBy == nil || *j.CreatedBy != me.ID)) {
+		return nil, apperror.NotFound(utils.NOT_FOUND, nil)
+	}
+
+	return toJobDetail(j), nil
+}
+
+func toJobDetail(j *models.Job) *jobResponse.JobDetail {
+	d := &jobResponse.JobDetail{
+		ID:          j.ID,
+		Type:        j.Type,
+		Status:      string(j.Status),
+		Attempts:    j.Attempts,
+		MaxAttempts: j.MaxAttempts,
+		RunAt:       j.RunAt,
+		StartedAt:   j.StartedAt,
+		FinishedAt:  j.FinishedAt,
+		LastError:   j.LastError,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+	}
+	if j.Result != "" && json.Valid([]byte(j.Result)) {
+		d.Result = json.RawMessage(j.Result)
+	}
+	return d
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	"go-demo-gin/utils"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestJobService_GetJobById_OwnerOrAdmin(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Job{}))
+	owner := uint(2)
+	j := &models.Job{Type: "users.import", Status: models.JobSucceeded, CreatedBy: &owner, Result: `{"created":3}`}
+	require.NoError(t, db.Create(j).Error)
+	svc := NewJobService(repo.NewGormJobRepo(db))
+	id := strconv.Itoa(int(j.ID))
+
+	as := func(u *models.User) context.Context { return utils.WithInformation(context.Background(), u) }
+
+	detail, err := svc.GetJobById(as(&models.User{Model: gorm.Model{ID: 2}, Role: models.RoleStaff}), id)
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", detail.Status)
+	assert.JSONEq(t, `{"created":3}`, string(detail.Result))
+
+	_, err = svc.GetJobById(as(&models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin}), id)
+	assert.NoError(t, err)
+
+	// Job của người khác => 404 (không tiết lộ sự tồn tại)
+	_, err = svc.GetJobById(as(&models.User{Model: gorm.Model{ID: 3}, Role: models.RoleStaff}), id)
+	appErr, ok := apperror.As(err)
+	require.True(t, ok)
+	assert.Equal(t, apperror.KindNotFound, appErr.Kind)
+}
+
+func TestJobService_GetJobById_TenantIsolated(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, repo.RegisterTenantScope(db))
+	require.NoError(t, db.AutoMigrate(&models.Job{}))
+	inTenant := func(id uint) context.Context {
+		ctx := utils.WithTenant(context.Background(), utils.TenantScope{ID: id})
+		return utils.WithInformation(ctx, &models.User{Model: gorm.Model{ID: 1}, Role: models.RoleAdmin})
+	}
+	j := &models.Job{Type: "users.purge", Status: models.JobQueued}
+	require.NoError(t, db.WithContext(inTenant(2)).Create(j).Error)
+	assert.Equal(t, uint(2), j.TenantID)
+	svc := NewJobService(repo.NewGormJobRepo(db))
+	id := strconv.Itoa(int(j.ID))
+
+	_, err = svc.GetJobById(inTenant(2), id)
+	assert.NoError(t, err)
+
+	// Admin của tenant khác => 404
+	_, err = svc.GetJobById(inTenant(models.DefaultTenantID), id)
+	appErr, ok := apperror.As(err)
+	require.True(t, ok)
+	assert.Equal(t, apperror.KindNotFound, appErr.Kind)
+}
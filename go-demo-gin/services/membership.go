@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, o *models.Organization) error
+	FindByID(ctx context.Context, id uint) (*models.Organization, error)
+	Update(ctx context.Context, o *models.Organization) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, userID uint, pag *pkg.Pagination) ([]models.Organization, int64, error)
+	AddMember(ctx context.Context, m *models.OrganizationMember) error
+	FindMember(ctx context.Context, orgID, userID uint) (*models.OrganizationMember, error)
+	MemberRoles(ctx context.Context, userID uint, orgIDs []uint) (map[uint]models.MembershipRole, error)
+	UpdateMemberRole(ctx context.Context, orgID, userID uint, role models.MembershipRole) error
+	RemoveMember(ctx context.Context, orgID, userID uint) error
+	ListMembers(ctx context.Context, orgID uint, pag *pkg.Pagination) ([]models.OrganizationMember, int64, error)
+	CountOwners(ctx context.Context, orgID uint) (int64, error)
+}
+
+type TeamRepository interface {
+	Create(ctx context.Context, t *models.Team) error
+	FindByID(ctx context.Context, id uint) (*models.Team, error)
+	Update(ctx context.Context, t *models.Team) error
+	Delete(ctx context.Context, id uint) error
+	ListByOrganization(ctx context.Context, orgID uint, pag *pkg.Pagination) ([]models.Team, int64, error)
+	FindMember(ctx context.Context, teamID, userID uint) (*models.TeamMember, error)
+	SaveMember(ctx context.Context, m *models.TeamMember) error
+	RemoveMember(ctx context.Context, teamID, userID uint) error
+	ListMembers(ctx context.Context, teamID uint, pag *pkg.Pagination) ([]models.TeamMember, int64, error)
+}
+
+// MembershipAuthorizer kiểm tra quyền của user đang đăng nhập theo membership:
+//   - admin của tenant có quyền owner trên mọi organization
+//   - owner/manager của organization quản lý organization và mọi team của nó
+//   - owner/manager của team chỉ quản lý thành viên của chính team đó
+//   - member chỉ được xem
+//
+// Không là thành viên => NotFound (không lộ organization/team tồn tại); là thành viên nhưng không đủ quyền => Forbidden.
+type MembershipAuthorizer struct {
+	orgs  OrganizationRepository
+	teams TeamRepository
+}
+
+func NewMembershipAuthorizer(or OrganizationRepository, tr TeamRepository) *MembershipAuthorizer {
+	return &MembershipAuthorizer{orgs: or, teams: tr}
+}
+
+// OrgRole: organization và quyền của user đang đăng nhập trong đó (rỗng => không là thành viên)
+func (a *MembershipAuthorizer) OrgRole(ctx context.Context, orgID uint) (*models.Organization, models.MembershipRole, error) {
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, "", apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	org, err := a.orgs.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, "", notFoundOr(err)
+	}
+	if me.Role.IsAdmin() {
+		return org, models.MemberOwner, nil
+	}
+	m, err := a.orgs.FindMember(ctx, orgID, me.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return org, "", nil
+		}
+		return nil, "", apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return org, m.Role, nil
+}
+
+// RequireOrgMember: thành viên (hoặc admin) mới được xem organization
+func (a *MembershipAuthorizer) RequireOrgMember(ctx context.Context, orgID uint) (*models.Organization, models.MembershipRole, error) {
+	org, role, err := a.OrgRole(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+	if role == "" {
+		return nil, "", apperror.NotFound(utils.NOT_FOUND, nil)
+	}
+	return org, role, nil
+}
+
+// RequireOrgManager: owner/manager của organization
+func (a *MembershipAuthorizer) RequireOrgManager(ctx context.Context, orgID uint) (*models.Organization, models.MembershipRole, error) {
+	org, role, err := a.RequireOrgMember(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !role.CanManage() {
+		return nil, "", apperror.Forbidden(utils.MEMBERSHIP_FORBIDDEN, nil)
+	}
+	return org, role, nil
+}
+
+// TeamRole: quyền trong team; owner/manager của organization giữ nguyên quyền đó trên mọi team.
+// Không là thành viên organization => NotFound; là thành viên organization nhưng không trong team => "".
+func (a *MembershipAuthorizer) TeamRole(ctx context.Context, team *models.Team) (models.MembershipRole, error) {
+	_, orgRole, err := a.RequireOrgMember(ctx, team.OrganizationID)
+	if err != nil {
+		return "", err
+	}
+	if orgRole.CanManage() {
+		return orgRole, nil
+	}
+	m, err := a.teams.FindMember(ctx, team.ID, utils.InformationFrom(ctx).ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return m.Role, nil
+}
+
+// RequireTeamManager: owner/manager của team (hoặc của organization chứa team)
+func (a *MembershipAuthorizer) RequireTeamManager(ctx context.Context, team *models.Team) (models.MembershipRole, error) {
+	role, err := a.TeamRole(ctx, team)
+	if err != nil {
+		return "", err
+	}
+	if !role.CanManage() {
+		return "", apperror.Forbidden(utils.MEMBERSHIP_FORBIDDEN, nil)
+	}
+	return role, nil
+}
+
+// RequireAssign: actor được đổi quyền của thành viên từ from sang to (from rỗng => thêm mới, to rỗng => xoá)
+func RequireAssign(actor, from, to models.MembershipRole) error {
+	if !actor.CanAssign(from, to) {
+		return apperror.Forbidden(utils.MEMBERSHIP_FORBIDDEN, nil)
+	}
+	return nil
+}
+
+// ID trên URL (organization, team, user...)
+func parseID(idStr string) (uint, error) {
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || id == 0 {
+		return 0, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	return uint(id), nil
+}
+
+// Lỗi tra cứu: không có bản ghi => NotFound, còn lại => Internal
+func notFoundOr(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.NotFound(utils.NOT_FOUND, err)
+	}
+	return apperror.Internal(utils.INTERNAL_ERROR, err)
+}
+
+func toOrgMemberDetail(m *models.OrganizationMember) organizationResponse.MemberDetail {
+	d := organizationResponse.MemberDetail{UserID: m.UserID, Role: string(m.Role), JoinedAt: m.CreatedAt}
+	if m.User != nil {
+		d.Username = m.User.Username
+	}
+	return d
+}
+
+func toTeamMemberDetail(m *models.TeamMember) organizationResponse.MemberDetail {
+	d := organizationResponse.MemberDetail{UserID: m.UserID, Role: string(m.Role), JoinedAt: m.CreatedAt}
+	if m.User != nil {
+		d.Username = m.User.Username
+	}
+	return d
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/utils"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository interface {
+	FindBySubject(ctx context.Context, provider, subject string) (*models.UserIdentity, error)
+	Create(ctx context.Context, i *models.UserIdentity) error
+	TouchLogin(ctx context.Context, id uint, at time.Time) error
+}
+
+// StateStore lưu state/nonce/PKCE verifier giữa 2 bước login và callback (cache.Cache: LRU hoặc Redis)
+type StateStore interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type OIDCProviderConfig struct {
+	Name         string // dùng trong URL: /authen/oidc/{name}/login
+	Issuer       string // discovery: {Issuer}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // URL callback đã đăng ký với IdP
+	Scopes       []string // mặc định: openid email profile
+	DefaultRole  models.Role
+	// DefaultRole rỗng => không tự tạo user (JIT); chỉ danh tính đã liên kết mới đăng nhập được
+	LinkByEmail bool // liên kết với user sẵn có cùng email (chỉ khi IdP báo email_verified)
+}
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration // thời gian tối đa từ lúc chuyển sang IdP tới lúc callback
+}
+
+type oidcProvider struct {
+	cfg      OIDCProviderConfig
+	oauth    oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// Dữ liệu gắn với state, chỉ dùng được 1 lần
+type oidcLoginState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// Claims cần dùng trong ID token
+type oidcClaims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	Name              string `json:"name"`
+}
+
+// OIDCService: đăng nhập qua IdP ngoài (authorization code + PKCE), liên kết danh tính với models.User
+// và cấp JWT giống AuthService.Authenticate.
+type OIDCService struct {
+	db         *gorm.DB
+	cfg        OIDCConfig
+	users      *UserService
+	auth       *AuthService
+	identities IdentityRepository
+	states     StateStore
+
+	mu        sync.Mutex
+	providers map[string]*oidcProvider // discovery lần đầu dùng tới, IdP lỗi lúc khởi động không làm hỏng app
+}
+
+func NewOIDCService(db *gorm.DB, cfg OIDCConfig, users *UserService, auth *AuthService, ir IdentityRepository, states StateStore) *OIDCService {
+	if cfg.StateTTL <= 0 {
+		cfg.StateTTL = 10 * time.Minute
+	}
+	return &OIDCService{db: db, cfg: cfg, users: users, auth: auth, identities: ir, states: states, providers: map[string]*oidcProvider{}}
+}
+
+// AuthURL tạo state, nonce, PKCE verifier và trả về URL chuyển hướng sang trang đăng nhập của IdP
+func (s *OIDCService) AuthURL(ctx context.Context, name string) (string, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the oidc auth url service", logrus.Fields{"provider": name})
+
+	p, err := s.provider(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	state, nonce := randomToken(), randomToken()
+	ls := oidcLoginState{Provider: name, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}
+	data, _ := json.Marshal(ls)
+	if err := s.states.Set(ctx, oidcStateKey(state), data, s.cfg.StateTTL); err != nil {
+		return "", apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return p.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(ls.Verifier)), nil
+}
+
+// Callback đổi code lấy token, kiểm tra ID token (chữ ký, issuer, audience, hạn, nonce),
+// tìm hoặc tạo user tương ứng và cấp JWT của hệ thống
+func (s *OIDCService) Callback(ctx context.Context, name, code, state string) (*string, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the oidc callback service", logrus.Fields{"provider": name})
+
+	// State dùng 1 lần: xoá ngay để callback bị gửi lại (replay) không dùng được
+	raw, ok, err := s.states.Get(ctx, oidcStateKey(state))
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	var ls oidcLoginState
+	if !ok || state == "" || json.Unmarshal(raw, &ls) != nil || ls.Provider != name {
+		return nil, apperror.Unauthorized(utils.OIDC_INVALID_STATE, nil)
+	}
+	_ = s.states.Delete(ctx, oidcStateKey(state))
+
+	p, err := s.provider(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	tok, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(ls.Verifier))
+	if err != nil {
+		return nil, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, err)
+	}
+	rawID, _ := tok.Extra("id_token").(string)
+	if rawID == "" {
+		return nil, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, errors.New("token response has no id_token"))
+	}
+	idToken, err := p.verifier.Verify(ctx, rawID)
+	if err != nil {
+		return nil, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, err)
+	}
+	if idToken.Nonce != ls.Nonce {
+		return nil, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, errors.New("nonce mismatch"))
+	}
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, apperror.Unauthorized(utils.OIDC_LOGIN_FAILED, err)
+	}
+
+	user, err := s.resolveUser(ctx, p.cfg, idToken.Subject, &claims)
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if code := utils.AccountStatusError(user.Status); code != nil {
+		return nil, apperror.Forbidden(code, nil)
+	}
+
+	return s.auth.issueToken(ctx, user)
+}
+
+// Tìm user đã liên kết; chưa có => liên kết theo email hoặc tạo mới (JIT) trong cùng transaction
+func (s *OIDCService) resolveUser(ctx context.Context, cfg OIDCProviderConfig, subject string, claims *oidcClaims) (*models.User, error) {
+	var user *models.User
+	created := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		now := time.Now()
+
+		identity, err := s.identities.FindBySubject(ctxTx, cfg.Name, subject)
+		if err == nil {
+			if user, err = s.users.userRepo.FindByID(ctxTx, identity.UserID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) { // user đã bị xoá
+					return apperror.Forbidden(utils.OIDC_NOT_PROVISIONED, err)
+				}
+				return err
+			}
+			return s.identities.TouchLogin(ctxTx, identity.ID, now)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var email *string
+		if claims.Email != "" {
+			email = &claims.Email
+			existing, err := s.users.userRepo.FindByEmail(ctxTx, claims.Email)
+			switch {
+			case err == nil && cfg.LinkByEmail && claims.EmailVerified:
+				user = existing
+			case err == nil:
+				// Không tự gộp vào tài khoản có sẵn khi email chưa được IdP xác thực
+				return apperror.Conflict(utils.DUPLICATE_EMAIL, nil)
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+		}
+
+		if user == nil {
+			if cfg.DefaultRole == "" {
+				return apperror.Forbidden(utils.OIDC_NOT_PROVISIONED, nil)
+			}
+			username, err := s.freeUsername(ctxTx, claims)
+			if err != nil {
+				return err
+			}
+			user = &models.User{
+				Username: username,
+				Password: "", // không có mật khẩu local => chỉ đăng nhập qua IdP
+				Role:     cfg.DefaultRole,
+				Status:   models.UserActive,
+				Email:    email,
+			}
+			user.Name.String, user.Name.Valid = claims.Name, claims.Name != ""
+			if claims.EmailVerified {
+				user.EmailVerifiedAt = &now
+			}
+			if err := s.users.createInTx(ctxTx, user); err != nil {
+				return err
+			}
+			created = true
+		}
+
+		return s.identities.Create(ctxTx, &models.UserIdentity{
+			UserID:      user.ID,
+			Provider:    cfg.Name,
+			Subject:     subject,
+			Email:       email,
+			LastLoginAt: &now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		s.users.invalidateUserCache(ctx, nil)
+	}
+	return user, nil
+}
+
+var invalidUsernameChars = regexp.MustCompile(`[^a-z0-9_.]+`)
+
+// Username từ preferred_username hoặc phần trước @ của email, thêm số nếu đã có người dùng
+func (s *OIDCService) freeUsername(ctxTx context.Context, claims *oidcClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = invalidUsernameChars.ReplaceAllString(strings.ToLower(base), "_")
+	if len(base) > 20 {
+		base = base[:20]
+	}
+	if len(base) < 3 {
+		base = "user" + base
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate += strconv.Itoa(i)
+		}
+		_, err := s.users.userRepo.FindByUsername(ctxTx, candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", apperror.Conflict(utils.DUPLICATE_USERNAME, nil)
+}
+
+// Discovery + tạo verifier cho provider (lưu lại sau lần thành công đầu tiên)
+func (s *OIDCService) provider(ctx context.Context, name string) (*oidcProvider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[name]; ok {
+		return p, nil
+	}
+
+	for _, cfg := range s.cfg.Providers {
+		if cfg.Name != name {
+			continue
+		}
+		discovered, err := oidc.NewProvider(ctx, cfg.Issuer)
+		if err != nil {
+			return nil, apperror.Internal(utils.OIDC_PROVIDER_UNAVAILABLE, err)
+		}
+		scopes := cfg.Scopes
+		if len(scopes) == 0 {
+			scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+		}
+		p := &oidcProvider{
+			cfg: cfg,
+			oauth: oauth2.Config{
+				ClientID:     cfg.ClientID,
+				ClientSecret: cfg.ClientSecret,
+				RedirectURL:  cfg.RedirectURL,
+				Endpoint:     discovered.Endpoint(),
+				Scopes:       scopes,
+			},
+			verifier: discovered.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		}
+		s.providers[name] = p
+		return p, nil
+	}
+	return nil, apperror.NotFound(utils.OIDC_PROVIDER_NOT_FOUND, nil)
+}
+
+func oidcStateKey(state string) string { return "oidc:state:" + state }
+
+func randomToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	organizationRequest "go-demo-gin/requests/organization"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// OrganizationService: organization và thành viên; quyền kiểm tra qua MembershipAuthorizer
+type OrganizationService struct {
+	db    *gorm.DB
+	orgs  OrganizationRepository
+	authz *MembershipAuthorizer
+}
+
+func NewOrganizationService(db *gorm.DB, or OrganizationRepository, authz *MembershipAuthorizer) *OrganizationService {
+	return &OrganizationService{db: db, orgs: or, authz: authz}
+}
+
+// CreateOrganization: người tạo trở thành owner
+func (s *OrganizationService) CreateOrganization(ctx context.Context, in *organizationRequest.OrganizationCreate) (*organizationResponse.OrganizationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create organization service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+
+	org := models.Organization{Name: in.Name}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		if err := s.orgs.Create(ctxTx, &org); err != nil {
+			return err
+		}
+		return s.orgs.AddMember(ctxTx, &models.OrganizationMember{OrganizationID: org.ID, UserID: me.ID, Role: models.MemberOwner})
+	}); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+
+	d := toOrganizationDetail(&org, models.MemberOwner)
+	return &d, nil
+}
+
+// GetOrganizationList: các organization mà user tham gia (admin: mọi organization của tenant)
+func (s *OrganizationService) GetOrganizationList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of organizations service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	var userID uint
+	if !me.Role.IsAdmin() {
+		userID = me.ID
+	}
+	orgs, total, err := s.orgs.List(ctx, userID, pag)
+	if err != nil {
+		// Thường do tham số sort không hợp lệ; lỗi gốc chỉ ghi log
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on organization list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	ids := make([]uint, 0, len(orgs))
+	for _, o := range orgs {
+		ids = append(ids, o.ID)
+	}
+	roles, err := s.orgs.MemberRoles(ctx, me.ID, ids)
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	list := make([]organizationResponse.OrganizationDetail, 0, len(orgs))
+	for i := range orgs {
+		list = append(list, toOrganizationDetail(&orgs[i], roles[orgs[i].ID]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+func (s *OrganizationService) GetOrganizationById(ctx context.Context, idStr string) (*organizationResponse.OrganizationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get organization by id service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+	org, role, err := s.authz.RequireOrgMember(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	d := toOrganizationDetail(org, role)
+	return &d, nil
+}
+
+// UpdateOrganization: owner/manager đổi tên organization
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, in *organizationRequest.OrganizationUpdate, idStr string) (*organizationResponse.OrganizationDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update organization service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+	org, role, err := s.authz.RequireOrgManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	org.Name = in.Name
+	if err := s.orgs.Update(ctx, org); err != nil {
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	d := toOrganizationDetail(org, role)
+	return &d, nil
+}
+
+// DeleteOrganization: chỉ owner; xoá luôn team, thành viên và lời mời
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete organization service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return err
+	}
+	_, role, err := s.authz.RequireOrgMember(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role != models.MemberOwner {
+		return apperror.Forbidden(utils.MEMBERSHIP_FORBIDDEN, nil)
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.orgs.Delete(utils.WithTx(ctx, tx), id)
+	}); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+// GetMemberList: thành viên của organization (thành viên nào cũng xem được)
+func (s *OrganizationService) GetMemberList(ctx context.Context, idStr string, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of organization members service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgMember(ctx, id); err != nil {
+		return nil, err
+	}
+	members, total, err := s.orgs.ListMembers(ctx, id, pag)
+	if err != nil {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on organization member list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+
+	list := make([]organizationResponse.MemberDetail, 0, len(members))
+	for i := range members {
+		list = append(list, toOrgMemberDetail(&members[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// UpdateMemberRole: owner/manager đổi quyền thành viên (chỉ owner cấp/gỡ quyền owner); organization luôn còn ít nhất 1 owner
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, in *organizationRequest.MemberUpdate, idStr, userIDStr string) (*organizationResponse.MemberDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update organization member service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseID(userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	_, actor, err := s.authz.RequireOrgManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	role := models.MembershipRole(in.Role)
+
+	var member *models.OrganizationMember
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		m, err := s.orgs.FindMember(ctxTx, id, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound(utils.NOT_ORG_MEMBER, err)
+			}
+			return err
+		}
+		if err := RequireAssign(actor, m.Role, role); err != nil {
+			return err
+		}
+		if m.Role == models.MemberOwner && role != models.MemberOwner {
+			if err := s.requireAnotherOwner(ctxTx, id); err != nil {
+				return err
+			}
+		}
+		if err := s.orgs.UpdateMemberRole(ctxTx, id, userID, role); err != nil {
+			return err
+		}
+		m.Role = role
+		member = m
+		return nil
+	})
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+
+	d := toOrgMemberDetail(member)
+	return &d, nil
+}
+
+// RemoveMember: owner/manager xoá thành viên (chỉ owner xoá được owner), hoặc thành viên tự rời organization.
+// Thành viên bị xoá khỏi mọi team của organization.
+func (s *OrganizationService) RemoveMember(ctx context.Context, idStr, userIDStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the remove organization member service", nil)
+
+	id, err := parseID(idStr)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID(userIDStr)
+	if err != nil {
+		return err
+	}
+	_, actor, err := s.authz.RequireOrgMember(ctx, id)
+	if err != nil {
+		return err
+	}
+	self := utils.InformationFrom(ctx).ID == userID
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		m, err := s.orgs.FindMember(ctxTx, id, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound(utils.NOT_ORG_MEMBER, err)
+			}
+			return err
+		}
+		if !self {
+			if err := RequireAssign(actor, m.Role, ""); err != nil {
+				return err
+			}
+		}
+		if m.Role == models.MemberOwner {
+			if err := s.requireAnotherOwner(ctxTx, id); err != nil {
+				return err
+			}
+		}
+		return s.orgs.RemoveMember(ctxTx, id, userID)
+	})
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return err
+		}
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+// Owner cuối cùng không được rời/bị hạ quyền
+func (s *OrganizationService) requireAnotherOwner(ctx context.Context, orgID uint) error {
+	n, err := s.orgs.CountOwners(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if n <= 1 {
+		return apperror.Conflict(utils.LAST_OWNER, nil)
+	}
+	return nil
+}
+
+func toOrganizationDetail(o *models.Organization, role models.MembershipRole) organizationResponse.OrganizationDetail {
+	return organizationResponse.OrganizationDetail{
+		ID:        o.ID,
+		Name:      o.Name,
+		Role:      string(role),
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package services
+
+import (
+	"context"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, e *models.OutboxEvent) error
+}
+
+// emitUserEvent ghi domain event vào outbox (ctx phải chứa tx qua utils.WithTx để cùng commit/rollback)
+func emitUserEvent(ctx context.Context, or OutboxRepository, eventType string, payload events.UserPayload) error {
+	e, err := events.NewOutboxEvent(eventType, "user", payload.ID, payload)
+	if err != nil {
+		return err
+	}
+	return or.Create(ctx, e)
+}
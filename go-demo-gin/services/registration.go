@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg/mailer"
+	authenRequest "go-demo-gin/requests/authen"
+	userRequest "go-demo-gin/requests/user"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type MailSender interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+type RegistrationConfig struct {
+	Key            []byte        // khoá ký link xác thực (HMAC-SHA256)
+	VerifyURL      string        // link trong email; token được gắn vào query "token"
+	TokenTTL       time.Duration // hạn của link xác thực
+	ResendInterval time.Duration // khoảng cách tối thiểu giữa 2 lần gửi email xác thực
+}
+
+func DefaultRegistrationConfig() RegistrationConfig {
+	return RegistrationConfig{
+		VerifyURL:      "http://localhost:8080/api/v1/authen/verify",
+		TokenTTL:       24 * time.Hour,
+		ResendInterval: time.Minute,
+	}
+}
+
+// RegistrationService: khách tự đăng ký tài khoản customer và xác thực email.
+// Tạo user/kích hoạt dùng lại UserService để có cùng audit, outbox event và xoá cache.
+type RegistrationService struct {
+	db     *gorm.DB
+	cfg    RegistrationConfig
+	users  *UserService
+	mailer MailSender
+}
+
+func NewRegistrationService(db *gorm.DB, cfg RegistrationConfig, users *UserService, ms MailSender) *RegistrationService {
+	return &RegistrationService{db: db, cfg: cfg, users: users, mailer: ms}
+}
+
+// Register tạo customer ở trạng thái pending và gửi link xác thực.
+// Gửi email lỗi không làm hỏng việc đăng ký (user có thể yêu cầu gửi lại).
+func (s *RegistrationService) Register(ctx context.Context, in *authenRequest.RegisterForm) (*userResponse.UserDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the register service", nil)
+
+	// Mapper
+	var user models.User
+	copier.Copy(&user, in)
+	now := time.Now()
+	user.Role = models.RoleCustomer
+	user.Status = models.UserPending
+	user.VerificationSentAt = &now
+
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.users.createInTx(utils.WithTx(ctx, tx), &user)
+	}); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+	s.users.invalidateUserCache(ctx, nil)
+
+	if err := s.sendVerification(ctx, &user, now); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Send verification email failed: "+err.Error(), logrus.Fields{"user_id": user.ID})
+	}
+
+	// Mapper
+	var detail userResponse.UserDetail
+	copier.Copy(&detail, &user)
+	return &detail, nil
+}
+
+// VerifyEmail kích hoạt tài khoản từ link xác thực; bấm lại link đã dùng => không làm gì.
+// Tài khoản đã bị admin vô hiệu hoá thì chỉ ghi nhận email, không kích hoạt.
+func (s *RegistrationService) VerifyEmail(ctx context.Context, token string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the verify email service", nil)
+
+	tenantID, id, exp, ok := s.parseToken(token)
+	if !ok {
+		return apperror.Validation(utils.INVALID_VERIFICATION_TOKEN, nil)
+	}
+	// Link mở từ email không mang tenant của request => tìm user trong tenant ghi trong token (đã ký)
+	ctx = utils.WithTenant(ctx, utils.TenantScope{ID: tenantID})
+
+	var verified *models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		u, err := s.users.userRepo.FindByID(ctxTx, id)
+		if err != nil {
+			return err
+		}
+		// Chữ ký gắn với email hiện tại => đổi email thì link cũ mất hiệu lực
+		if u.Email == nil || !hmac.Equal([]byte(token), []byte(s.signToken(u.TenantID, u.ID, *u.Email, exp))) {
+			return apperror.Validation(utils.INVALID_VERIFICATION_TOKEN, nil)
+		}
+		if u.EmailVerifiedAt != nil {
+			return nil
+		}
+		if time.Now().After(exp) {
+			return apperror.Validation(utils.VERIFICATION_EXPIRED, nil)
+		}
+
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+		if err := s.users.userRepo.Update(ctxTx, u); err != nil {
+			return err
+		}
+		verified = u
+		if u.Status != models.UserPending {
+			return nil
+		}
+		verified, err = s.users.changeStatusInTx(ctxTx, u.ID, userRequest.StatusActivate, "")
+		return err
+	})
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.Validation(utils.INVALID_VERIFICATION_TOKEN, nil)
+		}
+		return apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	if verified != nil {
+		s.users.invalidateUserCache(ctx, verified)
+	}
+	return nil
+}
+
+// ResendVerification gửi lại link xác thực. Email không tồn tại/đã xác thực => vẫn coi như thành công
+// để không lộ email nào đã đăng ký; gửi quá dày => TooManyRequests.
+func (s *RegistrationService) ResendVerification(ctx context.Context, email string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the resend verification service", nil)
+
+	ctxTx := utils.WithTx(ctx, nil)
+	u, err := s.users.userRepo.FindByEmail(ctxTx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if u.EmailVerifiedAt != nil || u.Status != models.UserPending {
+		return nil
+	}
+
+	now := time.Now()
+	sent, err := s.users.userRepo.MarkVerificationSent(ctxTx, u.ID, now, now.Add(-s.cfg.ResendInterval))
+	if err != nil {
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if !sent {
+		return apperror.TooManyRequests(utils.VERIFICATION_THROTTLED, nil)
+	}
+	if err := s.sendVerification(ctx, u, now); err != nil {
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return nil
+}
+
+// Gửi email chứa link xác thực, nội dung theo ngôn ngữ của request
+func (s *RegistrationService) sendVerification(ctx context.Context, u *models.User, now time.Time) error {
+	exp := now.Add(s.cfg.TokenTTL)
+	link := s.cfg.VerifyURL + "?token=" + url.QueryEscape(s.signToken(u.TenantID, u.ID, *u.Email, exp))
+
+	localizer := utils.LocalizerFrom(ctx)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      *u.Email,
+		Subject: utils.LoadI18nMessage(localizer, utils.VERIFY_EMAIL_SUBJECT, nil),
+		Body: utils.LoadI18nMessage(localizer, utils.VERIFY_EMAIL_BODY, map[string]any{
+			"Username":  u.Username,
+			"Link":      link,
+			"ExpiresAt": exp.UTC().Format(time.RFC1123),
+		}),
+	})
+}
+
+// Token dạng "<tenant id>.<id>.<exp unix>.<hmac>"; hmac ký cả email để link chỉ dùng cho đúng địa chỉ đã gửi
+func (s *RegistrationService) signToken(tenantID, id uint, email string, exp time.Time) string {
+	payload := fmt.Sprintf("%d.%d.%d", tenantID, id, exp.Unix())
+	mac := hmac.New(sha256.New, s.cfg.Key)
+	mac.Write([]byte("verify-email:" + payload + ":" + strings.ToLower(email)))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Tách tenant/id/hạn từ token (chưa kiểm tra chữ ký)
+func (s *RegistrationService) parseToken(token string) (uint, uint, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return 0, 0, time.Time{}, false
+	}
+	tenantID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || tenantID == 0 {
+		return 0, 0, time.Time{}, false
+	}
+	id, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, time.Time{}, false
+	}
+	return uint(tenantID), uint(id), time.Unix(exp, 0), true
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	sessionResponse "go-demo-gin/responses/session"
+	"go-demo-gin/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Trong khoảng này session đã kiểm tra được coi là hợp lệ (đọc từ cache, không ghi DB)
+// => last_seen_at cập nhật tối đa 1 lần/phút; thu hồi có hiệu lực ngay trên instance xử lý
+// (và mọi instance nếu cache là Redis)
+const sessionCheckInterval = time.Minute
+
+type SessionRepository interface {
+	Create(ctx context.Context, s *models.Session) error
+	FindByID(ctx context.Context, id uint) (*models.Session, error)
+	ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	Revoke(ctx context.Context, id uint, at time.Time) error
+	TouchLastSeen(ctx context.Context, id uint, at time.Time) error
+	Extend(ctx context.Context, id uint, expiresAt, at time.Time) error
+}
+
+type SessionService struct {
+	repo  SessionRepository
+	cache StateStore
+}
+
+func NewSessionService(sr SessionRepository, c StateStore) *SessionService {
+	return &SessionService{repo: sr, cache: c}
+}
+
+// Start ghi session mới cho lần đăng nhập (IP, User-Agent lấy từ request hiện tại)
+func (s *SessionService) Start(ctx context.Context, userID uint, expiresAt time.Time) (*models.Session, error) {
+	now := time.Now()
+	sess := models.Session{UserID: userID, LastSeenAt: now, ExpiresAt: expiresAt}
+	if info := utils.RequestInfoFrom(ctx); info != nil {
+		sess.IP = info.IP
+		sess.UserAgent = truncate(info.UserAgent, 255)
+	}
+	if err := s.repo.Create(ctx, &sess); err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return &sess, nil
+}
+
+// Validate: session còn hiệu lực và thuộc về user; đồng thời cập nhật last_seen_at
+func (s *SessionService) Validate(ctx context.Context, sid, userID uint) error {
+	key := sessionCacheKey(sid)
+	if v, ok, err := s.cache.Get(ctx, key); err == nil && ok && string(v) == strconv.FormatUint(uint64(userID), 10) {
+		return nil
+	}
+
+	now := time.Now()
+	sess, err := s.repo.FindByID(ctx, sid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.Unauthorized(utils.SESSION_REVOKED, err)
+		}
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if sess.UserID != userID || !sess.Active(now) {
+		return apperror.Unauthorized(utils.SESSION_REVOKED, nil)
+	}
+
+	// Lỗi ghi last_seen/cache không chặn request
+	if err := s.repo.TouchLastSeen(context.WithoutCancel(ctx), sid, now); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Update session last seen failed: "+err.Error(), logrus.Fields{"session_id": sid})
+	}
+	_ = s.cache.Set(ctx, key, []byte(strconv.FormatUint(uint64(userID), 10)), sessionCheckInterval)
+	return nil
+}
+
+// Extend: token được refresh => session sống theo hạn mới
+func (s *SessionService) Extend(ctx context.Context, sid uint, expiresAt time.Time) error {
+	if err := s.repo.Extend(ctx, sid, expiresAt, time.Now()); err != nil {
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return nil
+}
+
+// ListSessions: các session đang hoạt động của user đang đăng nhập
+func (s *SessionService) ListSessions(ctx context.Context) ([]sessionResponse.SessionDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of sessions service", nil)
+
+	me := utils.InformationFrom(ctx)
+	if me == nil {
+		return nil, apperror.Unauthorized(utils.AUTHEN_REQUIRE, nil)
+	}
+	sessions, err := s.repo.ListActiveByUser(ctx, me.ID, time.Now())
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	current := utils.SessionIDFrom(ctx)
+	list := make([]sessionResponse.SessionDetail, 0, len(sessions))
+	for _, sess := range sessions {
+		list = append(list, sessionResponse.SessionDetail{
+			ID:         sess.ID,
+			UserAgent:  sess.UserAgent,
+			IP:         sess.IP,
+			CreatedAt:  sess.CreatedAt,
+			LastSeenAt: sess.LastSeenAt,
+			ExpiresAt:  sess.ExpiresAt,
+			Current:    sess.ID == current,
+		})
+	}
+	return list, nil
+}
+
+// RevokeSession thu hồi 1 session của chính mình (kể cả session hiện tại = đăng xuất)
+func (s *SessionService) RevokeSession(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the revoke session service", nil)
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	sess, err := s.repo.FindByID(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	// Session của người khác => báo không tồn tại (không lộ ID)
+	if me := utils.InformationFrom(ctx); me == nil || sess.UserID != me.ID {
+		return apperror.NotFound(utils.NOT_FOUND, nil)
+	}
+
+	if err := s.repo.Revoke(ctx, sess.ID, time.Now()); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	if err := s.cache.Delete(ctx, sessionCacheKey(sess.ID)); err != nil {
+		utils.LogCtx(ctx, logrus.WarnLevel, "Invalidate session cache failed: "+err.Error(), logrus.Fields{"session_id": sess.ID})
+	}
+	return nil
+}
+
+func sessionCacheKey(sid uint) string {
+	return "session:" + strconv.FormatUint(uint64(sid), 10)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	organizationRequest "go-demo-gin/requests/organization"
+	organizationResponse "go-demo-gin/responses/organization"
+	"go-demo-gin/utils"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TeamService: team của organization và thành viên team.
+// Owner/manager của organization quản lý mọi team; owner/manager của team chỉ quản lý thành viên team đó.
+type TeamService struct {
+	db    *gorm.DB
+	teams TeamRepository
+	orgs  OrganizationRepository
+	authz *MembershipAuthorizer
+}
+
+func NewTeamService(db *gorm.DB, tr TeamRepository, or OrganizationRepository, authz *MembershipAuthorizer) *TeamService {
+	return &TeamService{db: db, teams: tr, orgs: or, authz: authz}
+}
+
+func (s *TeamService) CreateTeam(ctx context.Context, in *organizationRequest.TeamCreate, orgIDStr string) (*organizationResponse.TeamDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create team service", nil)
+
+	orgID, err := parseID(orgIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgManager(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	team := models.Team{OrganizationID: orgID, Name: in.Name}
+	if err := s.teams.Create(ctx, &team); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+	d := toTeamDetail(&team)
+	return &d, nil
+}
+
+func (s *TeamService) GetTeamList(ctx context.Context, orgIDStr string, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of teams service", nil)
+
+	orgID, err := parseID(orgIDStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgMember(ctx, orgID); err != nil {
+		return nil, err
+	}
+	teams, total, err := s.teams.ListByOrganization(ctx, orgID, pag)
+	if err != nil {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on team list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+
+	list := make([]organizationResponse.TeamDetail, 0, len(teams))
+	for i := range teams {
+		list = append(list, toTeamDetail(&teams[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// UpdateTeam: owner/manager của organization đổi tên team
+func (s *TeamService) UpdateTeam(ctx context.Context, in *organizationRequest.TeamUpdate, idStr string) (*organizationResponse.TeamDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update team service", nil)
+
+	team, err := s.findTeam(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgManager(ctx, team.OrganizationID); err != nil {
+		return nil, err
+	}
+	team.Name = in.Name
+	if err := s.teams.Update(ctx, team); err != nil {
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	d := toTeamDetail(team)
+	return &d, nil
+}
+
+// DeleteTeam: owner/manager của organization; xoá luôn thành viên và lời mời vào team
+func (s *TeamService) DeleteTeam(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete team service", nil)
+
+	team, err := s.findTeam(ctx, idStr)
+	if err != nil {
+		return err
+	}
+	if _, _, err := s.authz.RequireOrgManager(ctx, team.OrganizationID); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.teams.Delete(utils.WithTx(ctx, tx), team.ID)
+	}); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+// GetTeamMemberList: thành viên organization nào cũng xem được
+func (s *TeamService) GetTeamMemberList(ctx context.Context, idStr string, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of team members service", nil)
+
+	team, err := s.findTeam(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.authz.RequireOrgMember(ctx, team.OrganizationID); err != nil {
+		return nil, err
+	}
+	members, total, err := s.teams.ListMembers(ctx, team.ID, pag)
+	if err != nil {
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on team member list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+
+	list := make([]organizationResponse.MemberDetail, 0, len(members))
+	for i := range members {
+		list = append(list, toTeamMemberDetail(&members[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// SaveTeamMember thêm thành viên organization vào team hoặc đổi quyền trong team
+func (s *TeamService) SaveTeamMember(ctx context.Context, in *organizationRequest.MemberUpdate, idStr, userIDStr string) (*organizationResponse.MemberDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the save team member service", nil)
+
+	team, err := s.findTeam(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := parseID(userIDStr)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := s.authz.RequireTeamManager(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	role := models.MembershipRole(in.Role)
+
+	var member *models.TeamMember
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ctxTx := utils.WithTx(ctx, tx)
+		// Chỉ thành viên của organization mới vào được team
+		if _, err := s.orgs.FindMember(ctxTx, team.OrganizationID, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.Validation(utils.NOT_ORG_MEMBER, nil)
+			}
+			return err
+		}
+		var from models.MembershipRole
+		if m, err := s.teams.FindMember(ctxTx, team.ID, userID); err == nil {
+			from = m.Role
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := RequireAssign(actor, from, role); err != nil {
+			return err
+		}
+		if err := s.teams.SaveMember(ctxTx, &models.TeamMember{TeamID: team.ID, UserID: userID, Role: role}); err != nil {
+			return err
+		}
+		var err error
+		member, err = s.teams.FindMember(ctxTx, team.ID, userID)
+		return err
+	})
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+
+	d := toTeamMemberDetail(member)
+	return &d, nil
+}
+
+// RemoveTeamMember: owner/manager của team xoá thành viên (chỉ owner xoá được owner), hoặc thành viên tự rời team
+func (s *TeamService) RemoveTeamMember(ctx context.Context, idStr, userIDStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the remove team member service", nil)
+
+	team, err := s.findTeam(ctx, idStr)
+	if err != nil {
+		return err
+	}
+	userID, err := parseID(userIDStr)
+	if err != nil {
+		return err
+	}
+	actor, err := s.authz.TeamRole(ctx, team)
+	if err != nil {
+		return err
+	}
+	m, err := s.teams.FindMember(ctx, team.ID, userID)
+	if err != nil {
+		return notFoundOr(err)
+	}
+	if utils.InformationFrom(ctx).ID != userID {
+		if err := RequireAssign(actor, m.Role, ""); err != nil {
+			return err
+		}
+	}
+	if err := s.teams.RemoveMember(ctx, team.ID, userID); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+func (s *TeamService) findTeam(ctx context.Context, idStr string) (*models.Team, error) {
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+	team, err := s.teams.FindByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err)
+	}
+	return team, nil
+}
+
+func toTeamDetail(t *models.Team) organizationResponse.TeamDetail {
+	return organizationResponse.TeamDetail{
+		ID:             t.ID,
+		OrganizationID: t.OrganizationID,
+		Name:           t.Name,
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+}
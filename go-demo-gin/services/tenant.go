@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	tenantRequest "go-demo-gin/requests/tenant"
+	tenantResponse "go-demo-gin/responses/tenant"
+	"go-demo-gin/utils"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Tenant hầu như không đổi => kết quả tra cứu slug được cache ngắn (mỗi request đều cần)
+const tenantCacheTTL = time.Minute
+
+type TenantRepository interface {
+	Create(ctx context.Context, t *models.Tenant) error
+	FindBySlug(ctx context.Context, slug string) (*models.Tenant, error)
+	List(ctx context.Context, pag *pkg.Pagination) ([]models.Tenant, int64, error)
+}
+
+type TenantService struct {
+	repo  TenantRepository
+	cache StateStore
+}
+
+func NewTenantService(tr TenantRepository, c StateStore) *TenantService {
+	return &TenantService{repo: tr, cache: c}
+}
+
+// Resolve tìm tenant theo slug (header X-Tenant, subdomain); slug rỗng => tenant mặc định.
+// Tenant không tồn tại hoặc đã bị vô hiệu hoá => NotFound
+func (s *TenantService) Resolve(ctx context.Context, slug string) (*models.Tenant, error) {
+	if slug == "" {
+		return &models.Tenant{ID: models.DefaultTenantID}, nil
+	}
+
+	key := "tenant:" + slug
+	var t models.Tenant
+	if data, ok, err := s.cache.Get(ctx, key); err == nil && ok && json.Unmarshal(data, &t) == nil {
+		return tenantOrNotFound(&t)
+	}
+	found, err := s.repo.FindBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.UNKNOWN_TENANT, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	if data, err := json.Marshal(found); err == nil {
+		if err := s.cache.Set(ctx, key, data, tenantCacheTTL); err != nil {
+			utils.LogCtx(ctx, logrus.WarnLevel, "Tenant cache write failed: "+err.Error(), nil)
+		}
+	}
+	return tenantOrNotFound(found)
+}
+
+func tenantOrNotFound(t *models.Tenant) (*models.Tenant, error) {
+	if t.DisabledAt != nil {
+		return nil, apperror.NotFound(utils.UNKNOWN_TENANT, nil)
+	}
+	return t, nil
+}
+
+func (s *TenantService) CreateTenant(ctx context.Context, in *tenantRequest.TenantCreate) (*tenantResponse.TenantDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create tenant service", nil)
+
+	if _, err := s.repo.FindBySlug(ctx, in.Slug); err == nil {
+		return nil, apperror.Conflict(utils.DUPLICATE_TENANT_SLUG, nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	t := models.Tenant{Slug: in.Slug, Name: in.Name}
+	if err := s.repo.Create(ctx, &t); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+
+	d := toTenantDetail(&t)
+	return &d, nil
+}
+
+func (s *TenantService) GetTenantList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of tenants service", nil)
+
+	tenants, total, err := s.repo.List(ctx, pag)
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	list := make([]tenantResponse.TenantDetail, 0, len(tenants))
+	for i := range tenants {
+		list = append(list, toTenantDetail(&tenants[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+func toTenantDetail(t *models.Tenant) tenantResponse.TenantDetail {
+	return tenantResponse.TenantDetail{
+		ID:         t.ID,
+		Slug:       t.Slug,
+		Name:       t.Name,
+		DisabledAt: t.DisabledAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
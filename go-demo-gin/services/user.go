@@ -3,15 +3,20 @@ package services
 import (
 	"context"
 	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/events"
 	"go-demo-gin/models"
 	"go-demo-gin/pkg"
+	"go-demo-gin/pkg/password"
 	userRequest "go-demo-gin/requests/user"
 	userResponse "go-demo-gin/responses/user"
 	"go-demo-gin/utils"
-	"net/http"
+	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/jinzhu/copier"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -23,55 +28,147 @@ type UserRepository interface {
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, pag *pkg.Pagination, search string) ([]models.User, int64, error)
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	FindCredentials(ctx context.Context, username string) (*models.User, error)
+	Stream(ctx context.Context, search, sort string, batchSize int, fn func([]models.User) error) error
+	FindDeletedByID(ctx context.Context, id uint) (*models.User, error)
+	Restore(ctx context.Context, id uint) error
+	UpdateStatus(ctx context.Context, u *models.User) error
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	MarkVerificationSent(ctx context.Context, id uint, at, notAfter time.Time) (bool, error)
+	UpdatePasswordHash(ctx context.Context, u *models.User) error
+	AddPasswordHistory(ctx context.Context, userID uint, hash string, keep int) error
+}
+
+// UserCacheInvalidator được repo có cache (repo.CachedUserRepo) implement;
+// service gọi sau khi commit để xoá bản cache cũ.
+type UserCacheInvalidator interface {
+	Invalidate(ctx context.Context, u *models.User, ids ...uint)
+}
+
+// CacheInvalidator: cache phụ thuộc vào dữ liệu users (vd: middlewares.ResponseCache cho list)
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context)
 }
 
 type UserService struct {
-	db       *gorm.DB
-	userRepo UserRepository
+	db           *gorm.DB
+	userRepo     UserRepository
+	auditRepo    AuditRepository
+	outboxRepo   OutboxRepository
+	invalidators []CacheInvalidator
 }
 
-func NewUserService(db *gorm.DB, ur UserRepository) *UserService { // "constructor"
-	return &UserService{db: db, userRepo: ur}
+func NewUserService(db *gorm.DB, ur UserRepository, ar AuditRepository, or OutboxRepository) *UserService { // "constructor"
+	return &UserService{db: db, userRepo: ur, auditRepo: ar, outboxRepo: or}
 }
 
-func (s *UserService) CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, int, string) {
+// InvalidateOnChange đăng ký các cache cần xoá sau mỗi lần tạo/sửa/xoá user
+func (s *UserService) InvalidateOnChange(ci ...CacheInvalidator) {
+	s.invalidators = append(s.invalidators, ci...)
+}
+
+func (s *UserService) CreateUser(ctx context.Context, in *userRequest.UserCreate) (*userResponse.UserDetail, error) {
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create user service", nil)
 
-	// Lấy localizer cho i18n
-	localizer := utils.LocalizerFrom(ctx)
-
 	// Mapper
 	var user models.User
 	copier.Copy(&user, in)
 
 	// Transaction boundary
 	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1) Tạo user
-		ctxTx := utils.WithTx(ctx, tx)
-		if err := s.userRepo.Create(ctxTx, &user); err != nil {
-			return err // => auto ROLLBACK
-		}
-		// 2) (Ví dụ) gán role mặc định/ghi audit... (nếu thêm bước, vẫn trong tx)
-		return nil // => COMMIT
+		return s.createInTx(utils.WithTx(ctx, tx), &user) // lỗi => auto ROLLBACK
 	}); err != nil {
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.CREATE_FAIL, nil)
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
 	}
+	s.invalidateUserCache(ctx, nil)
 
 	// Mapper
 	var detail userResponse.UserDetail
 	copier.Copy(&detail, &user)
 
-	return &detail, http.StatusCreated, ""
+	return &detail, nil
 }
 
-func (s *UserService) GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, int, string) {
+// Tạo user + audit + outbox event; ctxTx phải mang transaction (utils.WithTx)
+func (s *UserService) createInTx(ctxTx context.Context, user *models.User) error {
+	if user.Status == "" {
+		user.Status = models.UserActive // user do admin/staff tạo được kích hoạt sẵn
+	}
+	// 1) Tạo user
+	if err := s.userRepo.Create(ctxTx, user); err != nil {
+		return err
+	}
+	if user.Password != "" {
+		if err := s.userRepo.AddPasswordHistory(ctxTx, user.ID, user.Password, password.MaxHistory); err != nil {
+			return err
+		}
+	}
+	// 2) Ghi audit (vẫn trong tx)
+	if err := recordUserAudit(ctxTx, s.auditRepo, models.AuditUserCreate, user.ID, nil, user); err != nil {
+		return err
+	}
+	// 3) Ghi domain event vào outbox (vẫn trong tx)
+	return emitUserEvent(ctxTx, s.outboxRepo, events.UserCreated, events.NewUserPayload(user))
+}
+
+// ImportUsers tạo nhiều user (đã validate) trong 1 transaction và trả về lỗi theo vị trí dòng.
+// atomic: dòng đầu tiên lỗi => rollback toàn bộ; ngược lại mỗi dòng chạy trong savepoint riêng,
+// dòng lỗi bị bỏ qua và các dòng còn lại vẫn được tạo.
+func (s *UserService) ImportUsers(ctx context.Context, rows []*userRequest.UserCreate, atomic bool) (map[int]error, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the import users service", logrus.Fields{"rows": len(rows), "atomic": atomic})
+
+	rowErrs := map[int]error{}
+	created := 0
+	rowFailed := false // atomic: rollback do 1 dòng lỗi (đã có trong rowErrs), không phải lỗi commit
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, in := range rows {
+			var user models.User
+			copier.Copy(&user, in)
+
+			if atomic {
+				if err := s.createInTx(utils.WithTx(ctx, tx), &user); err != nil {
+					rowErrs[i] = apperror.Internal(utils.CREATE_FAIL, err)
+					rowFailed = true
+					return err // => ROLLBACK toàn bộ
+				}
+				continue
+			}
+			// Transaction lồng nhau => SAVEPOINT: chỉ rollback dòng lỗi
+			if err := tx.Transaction(func(sp *gorm.DB) error {
+				return s.createInTx(utils.WithTx(ctx, sp), &user)
+			}); err != nil {
+				rowErrs[i] = apperror.Internal(utils.CREATE_FAIL, err)
+				continue
+			}
+			created++
+		}
+		return nil
+	})
+	// Commit lỗi => mọi dòng đã bị rollback, báo cáo theo dòng không còn đúng
+	if err != nil && !rowFailed {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+	if atomic && err == nil {
+		created = len(rows)
+	}
+	if created > 0 {
+		s.invalidateUserCache(ctx, nil)
+	}
+
+	return rowErrs, nil
+}
+
+func (s *UserService) GetUserList(ctx context.Context, pag *pkg.Pagination, search string) (*pkg.Pagination, error) {
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of users service", nil)
 	// Query
 	users, total, err := s.userRepo.List(ctx, pag, search)
 	if err != nil {
-		return nil, http.StatusBadRequest, err.Error()
+		// Thường do tham số sort/search không hợp lệ; lỗi gốc chỉ ghi log
+		utils.LogCtx(ctx, logrus.ErrorLevel, "DB error on user list: "+err.Error(), nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
 	}
 
 	// Mapper
@@ -82,44 +179,77 @@ func (s *UserService) GetUserList(ctx context.Context, pag *pkg.Pagination, sear
 	pag.TotalRows = total
 	pag.Result = list
 
-	return pag, http.StatusOK, ""
+	return pag, nil
 }
 
-func (s *UserService) GetUserById(ctx context.Context, idStr string) (*userResponse.UserDetail, int, string) {
+// Số bản ghi mỗi lô khi export
+const exportBatchSize = 500
+
+// Sort cho export: "<cột> [asc|desc]" (chặn chuỗi SQL tuỳ ý vì được đưa thẳng vào ORDER BY)
+var exportSortPattern = regexp.MustCompile(`(?i)^[a-z_]+( (asc|desc))?$`)
+
+// ExportUsers đọc users theo bộ lọc của list và gọi fn với từng lô (stream, không nạp cả bảng)
+func (s *UserService) ExportUsers(ctx context.Context, search, sort string, fn func([]userResponse.UserDetail) error) error {
 	// Logging
-	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get user by id service", nil)
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the export users service", nil)
+
+	if sort != "" && !exportSortPattern.MatchString(sort) {
+		return apperror.Validation(utils.VALIDATION_FAILED, map[string]*i18n.Message{"sort": utils.INVALID_VALUE})
+	}
+
+	err := s.userRepo.Stream(ctx, search, sort, exportBatchSize, func(users []models.User) error {
+		// Mapper
+		var details []userResponse.UserDetail
+		copier.Copy(&details, &users)
+		return fn(details)
+	})
+	if err != nil {
+		// Lỗi DB hoặc lỗi ghi ra client (fn)
+		return apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return nil
+}
 
-	// Lấy localizer cho i18n
-	localizer := utils.LocalizerFrom(ctx)
+func (s *UserService) GetUserById(ctx context.Context, idStr string) (*userResponse.UserDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get user by id service", nil)
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.INVALID_VALUE, nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
 	}
 
 	u, err := s.userRepo.FindByID(ctx, uint(id))
 	if err != nil {
-		return nil, http.StatusNotFound, utils.LoadI18nMessage(localizer, utils.NOT_FOUND, nil)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
 	}
 
 	var detail userResponse.UserDetail
 	_ = copier.Copy(&detail, u)
-	return &detail, http.StatusOK, ""
+	return &detail, nil
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, in *userRequest.UserUpdate, idStr string) (*userResponse.UserDetail, int, string) {
+func (s *UserService) UpdateUser(ctx context.Context, in *userRequest.UserUpdate, idStr string) (*userResponse.UserDetail, error) {
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update user service", nil)
 
-	// Lấy localizer cho i18n
-	localizer := utils.LocalizerFrom(ctx)
+	// Đổi mật khẩu phải do chính chủ thực hiện
+	if in.Pass != "" {
+		if err := forbidWhileImpersonating(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.INVALID_VALUE, nil)
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
 	}
 
 	var out *userResponse.UserDetail
+	var before models.User
 
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 1) Load hiện trạng
@@ -128,6 +258,11 @@ func (s *UserService) UpdateUser(ctx context.Context, in *userRequest.UserUpdate
 		if err != nil {
 			return err
 		}
+		before = *u
+		if u.Birthday != nil { // copier ghi đè trực tiếp vào con trỏ nên phải tách riêng
+			b := *u.Birthday
+			before.Birthday = &b
+		}
 
 		copier.Copy(&u, in)
 
@@ -136,11 +271,23 @@ func (s *UserService) UpdateUser(ctx context.Context, in *userRequest.UserUpdate
 			return err
 		}
 
+		// Đổi mật khẩu => lưu vào lịch sử (chặn dùng lại)
+		if u.Password != before.Password {
+			if err := s.userRepo.AddPasswordHistory(ctxTx, u.ID, u.Password, password.MaxHistory); err != nil {
+				return err
+			}
+		}
+
 		// 3) (khuyến nghị) reload để lấy DB-managed fields (UpdatedAt, v.v.)
 		if err := tx.First(u, u.ID).Error; err != nil {
 			return err
 		}
 
+		// 4) Ghi audit + domain event
+		if err := s.recordUpdateInTx(ctxTx, &before, u); err != nil {
+			return err
+		}
+
 		var d userResponse.UserDetail
 		copier.Copy(&d, u)
 		out = &d
@@ -148,42 +295,98 @@ func (s *UserService) UpdateUser(ctx context.Context, in *userRequest.UserUpdate
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, http.StatusNotFound, utils.LoadI18nMessage(localizer, utils.NOT_FOUND, nil)
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
 		}
-		return nil, http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.UPDATE_FAIL, nil)
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
 	}
+	s.invalidateUserCache(ctx, &before)
 
-	return out, http.StatusOK, ""
+	return out, nil
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, idStr string) (int, string) {
+// Ghi audit + outbox event cho thao tác sửa user; ctxTx phải mang transaction (utils.WithTx)
+func (s *UserService) recordUpdateInTx(ctxTx context.Context, before, after *models.User) error {
+	// 1) Ghi audit (đổi role được ghi thành action riêng để dễ lọc)
+	action := models.AuditUserUpdate
+	if before.Role != after.Role {
+		action = models.AuditUserRoleChange
+	}
+	if err := recordUserAudit(ctxTx, s.auditRepo, action, after.ID, before, after); err != nil {
+		return err
+	}
+
+	// 2) Ghi domain event vào outbox; đổi role phát thêm event riêng
+	if err := emitUserEvent(ctxTx, s.outboxRepo, events.UserUpdated, events.NewUserPayload(after)); err != nil {
+		return err
+	}
+	if before.Role != after.Role {
+		payload := events.NewUserPayload(after)
+		payload.PreviousRole = string(before.Role)
+		return emitUserEvent(ctxTx, s.outboxRepo, events.UserRoleChanged, payload)
+	}
+	return nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, idStr string) error {
 	// Logging
 	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete user service", nil)
 
-	// Lấy localizer cho i18n
-	localizer := utils.LocalizerFrom(ctx)
+	if err := forbidWhileImpersonating(ctx); err != nil {
+		return err
+	}
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		return http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.INVALID_VALUE, nil)
+		return apperror.Validation(utils.INVALID_VALUE, nil)
 	}
 
+	var deleted *models.User
+
 	// Transaction boundary
 	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Nếu có quan hệ phụ: xoá trước trong cùng tx (ví dụ)
 		// if err := s.userRoleRepo.DeleteByUserID(c.Request.Context(), tx, uint(id)); err != nil { return err }
 		// if err := s.noteRepo.DeleteByOwner(c.Request.Context(), tx, uint(id)); err != nil { return err }
 
-		// Xoá chính user
-		ctxTx := utils.WithTx(ctx, tx)
-		return s.userRepo.Delete(ctxTx, uint(id))
+		var err error
+		deleted, err = s.deleteInTx(utils.WithTx(ctx, tx), uint(id))
+		return err
 	}); err != nil {
 		// Phân loại lỗi: không tìm thấy vs lỗi khác
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return http.StatusNotFound, utils.LoadI18nMessage(localizer, utils.NOT_FOUND, nil)
+			return apperror.NotFound(utils.NOT_FOUND, err)
 		}
-		return http.StatusBadRequest, utils.LoadI18nMessage(localizer, utils.DELETE_FAIL, nil)
+		return apperror.Internal(utils.DELETE_FAIL, err)
 	}
+	s.invalidateUserCache(ctx, deleted)
+
+	return nil
+}
 
-	return http.StatusNoContent, ""
+// Xoá user + audit + outbox event; ctxTx phải mang transaction (utils.WithTx)
+func (s *UserService) deleteInTx(ctxTx context.Context, id uint) (*models.User, error) {
+	// Load hiện trạng để ghi audit
+	u, err := s.userRepo.FindByID(ctxTx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Xoá chính user
+	if err := s.userRepo.Delete(ctxTx, u.ID); err != nil {
+		return nil, err
+	}
+	if err := recordUserAudit(ctxTx, s.auditRepo, models.AuditUserDelete, u.ID, u, nil); err != nil {
+		return nil, err
+	}
+	return u, emitUserEvent(ctxTx, s.outboxRepo, events.UserDeleted, events.NewUserPayload(u))
+}
+
+// Xoá cache sau khi commit: cache user (nếu repo có cache) và các cache đã đăng ký
+func (s *UserService) invalidateUserCache(ctx context.Context, u *models.User) {
+	if inv, ok := s.userRepo.(UserCacheInvalidator); ok && u != nil {
+		inv.Invalidate(ctx, u)
+	}
+	for _, ci := range s.invalidators {
+		ci.Invalidate(ctx)
+	}
 }
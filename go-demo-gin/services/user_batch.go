@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+	userRequest "go-demo-gin/requests/user"
+	"go-demo-gin/utils"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// BatchUsers áp dụng lần lượt các thao tác (đã validate) trong 1 transaction và trả về lỗi theo vị trí.
+// atomic: thao tác đầu tiên lỗi => rollback toàn bộ; ngược lại mỗi thao tác chạy trong savepoint riêng,
+// thao tác lỗi bị bỏ qua và các thao tác còn lại vẫn được áp dụng.
+func (s *UserService) BatchUsers(ctx context.Context, ops []userRequest.UserBatchOperation, atomic bool) (map[int]error, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the batch users service", logrus.Fields{"operations": len(ops), "atomic": atomic})
+
+	opErrs := map[int]error{}
+	var changed []*models.User
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range ops {
+			if atomic {
+				u, err := s.applyBatchOp(utils.WithTx(ctx, tx), &ops[i])
+				if err != nil {
+					opErrs[i] = err
+					return err // => ROLLBACK toàn bộ
+				}
+				changed = append(changed, u)
+				continue
+			}
+			// Transaction lồng nhau => SAVEPOINT: chỉ rollback thao tác lỗi
+			var u *models.User
+			if err := tx.Transaction(func(sp *gorm.DB) error {
+				var err error
+				u, err = s.applyBatchOp(utils.WithTx(ctx, sp), &ops[i])
+				return err
+			}); err != nil {
+				opErrs[i] = err
+				continue
+			}
+			changed = append(changed, u)
+		}
+		return nil
+	})
+	if err != nil && len(opErrs) == 0 {
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	if err == nil {
+		for _, u := range changed {
+			s.invalidateUserCache(ctx, u)
+		}
+	}
+
+	return opErrs, nil
+}
+
+// Thực hiện 1 thao tác; lỗi trả về luôn là apperror để báo status theo từng phần tử
+func (s *UserService) applyBatchOp(ctxTx context.Context, op *userRequest.UserBatchOperation) (*models.User, error) {
+	// Không tự khoá mình khỏi hệ thống
+	if isSelf(ctxTx, op.ID) && op.Op != userRequest.BatchRestore && op.Op != userRequest.StatusActivate && op.Op != userRequest.StatusUnlock {
+		return nil, apperror.Forbidden(utils.CANNOT_MODIFY_SELF, nil)
+	}
+
+	var u *models.User
+	var err error
+	switch op.Op {
+	case userRequest.BatchDelete:
+		if err := forbidWhileImpersonating(ctxTx); err != nil {
+			return nil, err
+		}
+		u, err = s.deleteInTx(ctxTx, op.ID)
+	case userRequest.BatchRestore:
+		u, err = s.restoreInTx(ctxTx, op.ID)
+	case userRequest.BatchSetRole:
+		u, err = s.setRoleInTx(ctxTx, op.ID, models.Role(op.Role))
+	case userRequest.StatusDisable, userRequest.StatusActivate, userRequest.StatusLock, userRequest.StatusUnlock:
+		u, err = s.changeStatusInTx(ctxTx, op.ID, op.Op, op.Reason)
+	default:
+		return nil, apperror.Validation(utils.VALIDATION_FAILED, map[string]*i18n.Message{"op": utils.INVALID_BATCH_OP})
+	}
+	if err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	return u, nil
+}
+
+// Khôi phục user đã xoá mềm; username đã bị user khác dùng => Conflict
+func (s *UserService) restoreInTx(ctxTx context.Context, id uint) (*models.User, error) {
+	u, err := s.userRepo.FindDeletedByID(ctxTx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.FindByUsername(ctxTx, u.Username); err == nil {
+		return nil, apperror.Conflict(utils.DUPLICATE_USERNAME, nil)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.userRepo.Restore(ctxTx, u.ID); err != nil {
+		return nil, err
+	}
+	u.DeletedAt = gorm.DeletedAt{}
+	if err := recordUserAudit(ctxTx, s.auditRepo, models.AuditUserRestore, u.ID, nil, u); err != nil {
+		return nil, err
+	}
+	return u, emitUserEvent(ctxTx, s.outboxRepo, events.UserRestored, events.NewUserPayload(u))
+}
+
+// Đổi role; role không đổi => không làm gì
+func (s *UserService) setRoleInTx(ctxTx context.Context, id uint, role models.Role) (*models.User, error) {
+	u, err := s.userRepo.FindByID(ctxTx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Role == role {
+		return u, nil
+	}
+
+	before := *u
+	u.Role = role
+	if err := s.userRepo.Update(ctxTx, u); err != nil {
+		return nil, err
+	}
+	return u, s.recordUpdateInTx(ctxTx, &before, u)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+	userRequest "go-demo-gin/requests/user"
+	userResponse "go-demo-gin/responses/user"
+	"go-demo-gin/utils"
+	"strconv"
+	"time"
+
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ChangeUserStatus đổi trạng thái tài khoản theo action (activate, disable, lock, unlock).
+// Chuyển trạng thái không hợp lệ => Conflict; không tự vô hiệu hoá/khoá chính mình.
+func (s *UserService) ChangeUserStatus(ctx context.Context, idStr string, in *userRequest.UserStatusChange) (*userResponse.UserDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the change user status service", logrus.Fields{"action": in.Action})
+
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	if isSelf(ctx, uint(id)) && (in.Action == userRequest.StatusDisable || in.Action == userRequest.StatusLock) {
+		return nil, apperror.Forbidden(utils.CANNOT_MODIFY_SELF, nil)
+	}
+
+	var changed *models.User
+	if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		changed, err = s.changeStatusInTx(utils.WithTx(ctx, tx), uint(id), in.Action, in.Reason)
+		return err
+	}); err != nil {
+		if _, ok := apperror.As(err); ok {
+			return nil, err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	s.invalidateUserCache(ctx, changed)
+
+	var detail userResponse.UserDetail
+	copier.Copy(&detail, changed)
+	return &detail, nil
+}
+
+// Đổi trạng thái + audit + outbox event; ctxTx phải mang transaction (utils.WithTx)
+func (s *UserService) changeStatusInTx(ctxTx context.Context, id uint, action, reason string) (*models.User, error) {
+	u, err := s.userRepo.FindByID(ctxTx, id)
+	if err != nil {
+		return nil, err
+	}
+	current := u.Status
+	if current == "" {
+		current = models.UserActive // bản ghi cũ trước khi có cột status
+	}
+	next, ok := current.Transition(action)
+	if !ok {
+		return nil, apperror.Conflict(utils.INVALID_STATUS_TRANSITION, nil)
+	}
+
+	before := *u
+	now := time.Now()
+	u.Status = next
+	u.StatusReason = reason
+	u.StatusChangedAt = &now
+	if err := s.userRepo.UpdateStatus(ctxTx, u); err != nil {
+		return nil, err
+	}
+	if err := recordUserAudit(ctxTx, s.auditRepo, models.AuditUserStatus, u.ID, &before, u); err != nil {
+		return nil, err
+	}
+	payload := events.NewUserPayload(u)
+	payload.PreviousStatus = string(current)
+	payload.StatusReason = reason
+	return u, emitUserEvent(ctxTx, s.outboxRepo, events.UserStatusChanged, payload)
+}
+
+// isSelf: thao tác nhắm vào chính user đang đăng nhập
+func isSelf(ctx context.Context, id uint) bool {
+	me := utils.InformationFrom(ctx)
+	return me != nil && me.ID == id
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"go-demo-gin/apperror"
+	"go-demo-gin/models"
+	"go-demo-gin/repo"
+	userRequest "go-demo-gin/requests/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupUserService(t *testing.T) (*gorm.DB, *UserService) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite memory: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.AuditEvent{}, &models.OutboxEvent{}, &models.PasswordHistory{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, NewUserService(db, repo.NewGormUserRepo(db), repo.NewGormAuditRepo(db), repo.NewGormOutboxRepo(db))
+}
+
+func TestUserService_ErrorKinds(t *testing.T) {
+	_, s := setupUserService(t)
+	ctx := context.Background()
+
+	_, err := s.GetUserById(ctx, "abc")
+	assert.Equal(t, apperror.KindValidation, apperror.KindOf(err))
+
+	_, err = s.GetUserById(ctx, "42")
+	assert.Equal(t, apperror.KindNotFound, apperror.KindOf(err))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound) // nguyên nhân gốc vẫn được giữ
+
+	err = s.DeleteUser(ctx, "42")
+	assert.Equal(t, apperror.KindNotFound, apperror.KindOf(err))
+}
+
+// Commit lỗi (ràng buộc deferred chỉ kiểm tra lúc COMMIT) => lỗi Internal dù đã có dòng lỗi, không trả báo cáo theo dòng
+func TestUserService_ImportUsers_CommitFailure(t *testing.T) {
+	db, s := setupUserService(t)
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1) // PRAGMA gắn với connection
+	for _, stmt := range []string{
+		"PRAGMA foreign_keys = ON",
+		"CREATE TABLE parents (id INTEGER PRIMARY KEY)",
+		"CREATE TABLE guards (parent_id INTEGER REFERENCES parents(id) DEFERRABLE INITIALLY DEFERRED)",
+		"CREATE TRIGGER users_bad BEFORE INSERT ON users WHEN NEW.username = 'bad' BEGIN SELECT RAISE(ABORT, 'bad row'); END",
+		"CREATE TRIGGER users_poison AFTER INSERT ON users WHEN NEW.username = 'poison' BEGIN INSERT INTO guards VALUES (NEW.id); END",
+	} {
+		require.NoError(t, db.Exec(stmt).Error, stmt)
+	}
+	rows := []*userRequest.UserCreate{
+		{Username: "good", Pass: "secret.123", Role: "staff"},
+		{Username: "bad", Pass: "secret.123", Role: "staff"},
+		{Username: "poison", Pass: "secret.123", Role: "staff"},
+	}
+
+	rowErrs, err := s.ImportUsers(context.Background(), rows, false)
+	assert.Nil(t, rowErrs)
+	assert.Equal(t, apperror.KindInternal, apperror.KindOf(err))
+	var count int64
+	require.NoError(t, db.Model(&models.User{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,473 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"go-demo-gin/apperror"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	webhookRequest "go-demo-gin/requests/webhook"
+	webhookResponse "go-demo-gin/responses/webhook"
+	"go-demo-gin/utils"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Giới hạn độ dài response body lưu lại trong lịch sử gửi
+const maxWebhookResponseBody = 1024
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, e *models.WebhookEndpoint) error
+	FindEndpointByID(ctx context.Context, id uint) (*models.WebhookEndpoint, error)
+	SaveEndpoint(ctx context.Context, e *models.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, id uint) error
+	ListEndpoints(ctx context.Context, pag *pkg.Pagination) ([]models.WebhookEndpoint, int64, error)
+	ListActiveEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error)
+	CreateDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	SaveDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, endpointID uint, pag *pkg.Pagination) ([]models.WebhookDelivery, int64, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+}
+
+type WebhookConfig struct {
+	MaxAttempts  int           // số lần thử tối đa cho mỗi delivery
+	BaseBackoff  time.Duration // backoff = BaseBackoff * 2^(attempts-1), tối đa MaxBackoff
+	MaxBackoff   time.Duration
+	DisableAfter int           // số lần lỗi liên tiếp trước khi tự động tắt endpoint
+	Timeout      time.Duration // timeout cho mỗi request tới receiver
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+}
+
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   6 * time.Hour,
+		DisableAfter: 20,
+		Timeout:      10 * time.Second,
+		PollInterval: 2 * time.Second,
+		BatchSize:    50,
+		Lease:        time.Minute,
+	}
+}
+
+type WebhookService struct {
+	db     *gorm.DB
+	repo   WebhookRepository
+	cfg    WebhookConfig
+	client *http.Client
+	now    func() time.Time
+	log    *logrus.Entry
+}
+
+func NewWebhookService(db *gorm.DB, wr WebhookRepository, cfg WebhookConfig) *WebhookService {
+	return &WebhookService{
+		db:     db,
+		repo:   wr,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+		log:    logrus.WithField("source", "webhook"),
+	}
+}
+
+func (s *WebhookService) CreateEndpoint(ctx context.Context, in *webhookRequest.WebhookCreate) (*webhookResponse.WebhookDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the create webhook service", nil)
+
+	secret := in.Secret
+	if secret == "" {
+		secret = newWebhookSecret()
+	}
+	endpoint := models.WebhookEndpoint{
+		URL:         in.URL,
+		Secret:      secret,
+		EventTypes:  strings.Join(in.EventTypes, ","),
+		Description: in.Description,
+		Active:      true,
+	}
+	if err := s.repo.CreateEndpoint(ctx, &endpoint); err != nil {
+		return nil, apperror.Internal(utils.CREATE_FAIL, err)
+	}
+
+	// Secret chỉ hiển thị một lần khi tạo mới
+	detail := toWebhookDetail(&endpoint)
+	detail.Secret = endpoint.Secret
+	return &detail, nil
+}
+
+func (s *WebhookService) GetEndpointList(ctx context.Context, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of webhooks service", nil)
+
+	endpoints, total, err := s.repo.ListEndpoints(ctx, pag)
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	list := make([]webhookResponse.WebhookDetail, 0, len(endpoints))
+	for i := range endpoints {
+		list = append(list, toWebhookDetail(&endpoints[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+func (s *WebhookService) GetEndpointById(ctx context.Context, idStr string) (*webhookResponse.WebhookDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get webhook by id service", nil)
+
+	endpoint, err := s.findEndpoint(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	detail := toWebhookDetail(endpoint)
+	return &detail, nil
+}
+
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, in *webhookRequest.WebhookUpdate, idStr string) (*webhookResponse.WebhookDetail, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the update webhook service", nil)
+
+	endpoint, err := s.findEndpoint(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.URL != "" {
+		endpoint.URL = in.URL
+	}
+	if len(in.EventTypes) > 0 {
+		endpoint.EventTypes = strings.Join(in.EventTypes, ",")
+	}
+	if in.Secret != "" {
+		endpoint.Secret = in.Secret
+	}
+	if in.Description != "" {
+		endpoint.Description = in.Description
+	}
+	if in.Active != nil {
+		endpoint.Active = *in.Active
+		if endpoint.Active {
+			// Bật lại => xoá trạng thái tự động tắt
+			endpoint.FailureCount = 0
+			endpoint.DisabledAt = nil
+		}
+	}
+
+	if err := s.repo.SaveEndpoint(ctx, endpoint); err != nil {
+		return nil, apperror.Internal(utils.UPDATE_FAIL, err)
+	}
+	detail := toWebhookDetail(endpoint)
+	return &detail, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, idStr string) error {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the delete webhook service", nil)
+
+	endpoint, err := s.findEndpoint(ctx, idStr)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteEndpoint(ctx, endpoint.ID); err != nil {
+		return apperror.Internal(utils.DELETE_FAIL, err)
+	}
+	return nil
+}
+
+func (s *WebhookService) GetDeliveryList(ctx context.Context, idStr string, pag *pkg.Pagination) (*pkg.Pagination, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the get list of webhook deliveries service", nil)
+
+	endpoint, err := s.findEndpoint(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := s.repo.ListDeliveries(ctx, endpoint.ID, pag)
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	list := make([]webhookResponse.WebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		list = append(list, toWebhookDelivery(&deliveries[i]))
+	}
+	pag.TotalRows = total
+	pag.Result = list
+	return pag, nil
+}
+
+// SendTestEvent gửi ngay một event "webhook.test" (không retry, không tính vào số lần lỗi của endpoint)
+func (s *WebhookService) SendTestEvent(ctx context.Context, idStr string) (*webhookResponse.WebhookDelivery, error) {
+	// Logging
+	utils.LogCtx(ctx, logrus.InfoLevel, "Entering the send test webhook service", nil)
+
+	endpoint, err := s.findEndpoint(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _ := json.Marshal(map[string]any{"webhook_id": endpoint.ID, "message": "This is a test event"})
+	delivery, err := newWebhookDelivery(endpoint.ID, events.Event{
+		ID:            events.NewID(),
+		Type:          events.WebhookTest,
+		AggregateType: "webhook",
+		AggregateID:   endpoint.ID,
+		TenantID:      endpoint.TenantID,
+		OccurredAt:    s.now(),
+		Payload:       payload,
+	}, s.now())
+	if err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	if ok := s.attempt(ctx, endpoint, delivery); ok {
+		delivery.Status = models.WebhookDeliverySucceeded
+	} else {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+
+	detail := toWebhookDelivery(delivery)
+	return &detail, nil
+}
+
+// Name/Publish: WebhookService là một events.Sink; mỗi event được xếp hàng cho từng endpoint đăng ký
+func (s *WebhookService) Name() string { return "webhooks" }
+
+func (s *WebhookService) Publish(ctx context.Context, e events.Event) error {
+	// Event của tenant nào chỉ gửi tới endpoint của tenant đó
+	if e.TenantID != 0 {
+		ctx = utils.WithTenant(ctx, utils.TenantScope{ID: e.TenantID})
+	}
+	endpoints, err := s.repo.ListActiveEndpoints(ctx)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		if !slices.Contains(strings.Split(endpoint.EventTypes, ","), e.Type) {
+			continue
+		}
+		delivery, err := newWebhookDelivery(endpoint.ID, e, s.now())
+		if err != nil {
+			return err
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run gửi các delivery đến hạn theo chu kỳ cho tới khi ctx bị huỷ
+func (s *WebhookService) Run(ctx context.Context) {
+	s.log.Info("Webhook deliverer started")
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ProcessDue(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			s.log.WithError(err).Error("Webhook delivery failed")
+		}
+		select {
+		case <-ctx.Done():
+			s.log.Info("Webhook deliverer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue gửi một lô delivery đến hạn, trả về số delivery thành công
+func (s *WebhookService) ProcessDue(ctx context.Context) (int, error) {
+	batch, err := s.repo.ClaimDueDeliveries(ctx, s.now(), s.cfg.BatchSize, s.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	succeeded := 0
+	for i := range batch {
+		if ctx.Err() != nil {
+			return succeeded, ctx.Err()
+		}
+		ok, err := s.process(ctx, &batch[i])
+		if err != nil {
+			return succeeded, err
+		}
+		if ok {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+func (s *WebhookService) process(ctx context.Context, d *models.WebhookDelivery) (bool, error) {
+	endpoint, err := s.repo.FindEndpointByID(ctx, d.EndpointID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if endpoint == nil || !endpoint.Active {
+		// Endpoint đã bị xoá hoặc tắt => huỷ delivery
+		d.Status = models.WebhookDeliveryFailed
+		d.Error = "endpoint disabled or deleted"
+		return false, s.repo.SaveDelivery(ctx, d)
+	}
+
+	ok := s.attempt(ctx, endpoint, d)
+	now := s.now()
+	if ok {
+		d.Status = models.WebhookDeliverySucceeded
+		if endpoint.FailureCount > 0 {
+			endpoint.FailureCount = 0
+			if err := s.repo.SaveEndpoint(ctx, endpoint); err != nil {
+				return false, err
+			}
+		}
+		return true, s.repo.SaveDelivery(ctx, d)
+	}
+
+	if d.Attempts >= s.cfg.MaxAttempts {
+		d.Status = models.WebhookDeliveryFailed
+	} else {
+		d.NextAttemptAt = now.Add(events.Backoff(s.cfg.BaseBackoff, s.cfg.MaxBackoff, d.Attempts))
+	}
+
+	// Lỗi liên tiếp quá ngưỡng => tự động tắt endpoint
+	endpoint.FailureCount++
+	if endpoint.FailureCount >= s.cfg.DisableAfter {
+		endpoint.Active = false
+		endpoint.DisabledAt = &now
+		s.log.WithFields(logrus.Fields{"webhook_id": endpoint.ID, "failures": endpoint.FailureCount}).
+			Warn("Webhook endpoint disabled after repeated failures")
+	}
+	if err := s.repo.SaveEndpoint(ctx, endpoint); err != nil {
+		return false, err
+	}
+	return false, s.repo.SaveDelivery(ctx, d)
+}
+
+// attempt gửi delivery một lần và ghi lại kết quả vào d; trả về true nếu receiver trả 2xx
+func (s *WebhookService) attempt(ctx context.Context, endpoint *models.WebhookEndpoint, d *models.WebhookDelivery) bool {
+	d.Attempts++
+	d.Error = ""
+	d.ResponseCode = 0
+	d.ResponseBody = ""
+
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		d.Error = err.Error()
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-demo-gin-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", d.EventID)
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set(events.SignatureHeader, events.Sign(endpoint.Secret, s.now(), body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	d.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		d.Error = err.Error()
+		return false
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+	d.ResponseCode = resp.StatusCode
+	d.ResponseBody = string(respBody)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		d.Error = "unexpected status " + strconv.Itoa(resp.StatusCode)
+		return false
+	}
+	now := s.now()
+	d.DeliveredAt = &now
+	return true
+}
+
+func (s *WebhookService) findEndpoint(ctx context.Context, idStr string) (*models.WebhookEndpoint, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return nil, apperror.Validation(utils.INVALID_VALUE, nil)
+	}
+	endpoint, err := s.repo.FindEndpointByID(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound(utils.NOT_FOUND, err)
+		}
+		return nil, apperror.Internal(utils.INTERNAL_ERROR, err)
+	}
+	return endpoint, nil
+}
+
+// Payload lưu nguyên envelope của event để mọi lần thử gửi cùng một nội dung
+func newWebhookDelivery(endpointID uint, e events.Event, now time.Time) (*models.WebhookDelivery, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookDelivery{
+		EndpointID:    endpointID,
+		EventID:       e.ID,
+		EventType:     e.Type,
+		Payload:       string(body),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now,
+	}, nil
+}
+
+func newWebhookSecret() string {
+	var b [24]byte
+	_, _ = rand.Read(b[:])
+	return "whsec_" + hex.EncodeToString(b[:])
+}
+
+func toWebhookDetail(e *models.WebhookEndpoint) webhookResponse.WebhookDetail {
+	return webhookResponse.WebhookDetail{
+		ID:           e.ID,
+		URL:          e.URL,
+		EventTypes:   strings.Split(e.EventTypes, ","),
+		Description:  e.Description,
+		Active:       e.Active,
+		FailureCount: e.FailureCount,
+		DisabledAt:   e.DisabledAt,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func toWebhookDelivery(d *models.WebhookDelivery) webhookResponse.WebhookDelivery {
+	return webhookResponse.WebhookDelivery{
+		ID:            d.ID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		ResponseBody:  d.ResponseBody,
+		Error:         d.Error,
+		DurationMs:    d.DurationMs,
+		NextAttemptAt: d.NextAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+		CreatedAt:     d.CreatedAt,
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"go-demo-gin/events"
+	"go-demo-gin/models"
+	"go-demo-gin/pkg"
+	"go-demo-gin/repo"
+	webhookRequest "go-demo-gin/requests/webhook"
+	webhookResponse "go-demo-gin/responses/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupWebhookService(t *testing.T, cfg WebhookConfig) (*gorm.DB, *WebhookService) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite memory: %v", err)
+	}
+	if err := db.AutoMigrate(&models.WebhookEndpoint{}, &models.WebhookDelivery{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, NewWebhookService(db, repo.NewGormWebhookRepo(db), cfg)
+}
+
+// Receiver giả lập: trả về lần lượt các status code trong codes (hết thì trả 200)
+func newReceiver(t *testing.T, secret string, codes ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		if err := events.VerifySignature(secret, r.Header.Get(events.SignatureHeader), body, 5*time.Minute, time.Now()); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if n <= len(codes) {
+			w.WriteHeader(codes[n-1])
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func createEndpoint(t *testing.T, s *WebhookService, url string) *webhookResponse.WebhookDetail {
+	t.Helper()
+	detail, err := s.CreateEndpoint(context.Background(), &webhookRequest.WebhookCreate{
+		URL:        url,
+		EventTypes: []string{events.UserCreated},
+		Secret:     "0123456789abcdef0123",
+	})
+	if err != nil {
+		t.Fatalf("create endpoint: %v", err)
+	}
+	return detail
+}
+
+func publishUserCreated(t *testing.T, s *WebhookService) {
+	t.Helper()
+	e, _ := events.NewOutboxEvent(events.UserCreated, "user", 1, events.UserPayload{ID: 1, Username: "alice"})
+	if err := s.Publish(context.Background(), events.FromOutbox(*e)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+}
+
+func TestWebhook_SignedDeliverySucceeds(t *testing.T) {
+	db, s := setupWebhookService(t, DefaultWebhookConfig())
+	srv, calls := newReceiver(t, "0123456789abcdef0123")
+	createEndpoint(t, s, srv.URL)
+
+	publishUserCreated(t, s)
+	n, err := s.ProcessDue(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int32(1), calls.Load())
+
+	var d models.WebhookDelivery
+	db.First(&d)
+	assert.Equal(t, models.WebhookDeliverySucceeded, d.Status)
+	assert.Equal(t, http.StatusOK, d.ResponseCode)
+	assert.Equal(t, "ok", d.ResponseBody)
+}
+
+func TestWebhook_IgnoresUnsubscribedEvents(t *testing.T) {
+	db, s := setupWebhookService(t, DefaultWebhookConfig())
+	createEndpoint(t, s, "http://127.0.0.1:1")
+
+	e, _ := events.NewOutboxEvent(events.UserDeleted, "user", 1, events.UserPayload{ID: 1})
+	assert.NoError(t, s.Publish(context.Background(), events.FromOutbox(*e)))
+
+	var count int64
+	db.Model(&models.WebhookDelivery{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestWebhook_RetriesWithExponentialBackoff(t *testing.T) {
+	cfg := DefaultWebhookConfig()
+	cfg.BaseBackoff = time.Minute
+	db, s := setupWebhookService(t, cfg)
+	srv, calls := newReceiver(t, "0123456789abcdef0123", http.StatusInternalServerError, http.StatusBadGateway)
+	createEndpoint(t, s, srv.URL)
+
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	publishUserCreated(t, s)
+
+	var d models.WebhookDelivery
+	for i, wait := range []time.Duration{time.Minute, 2 * time.Minute} {
+		n, err := s.ProcessDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		db.First(&d)
+		assert.Equal(t, models.WebhookDeliveryPending, d.Status)
+		assert.Equal(t, i+1, d.Attempts)
+		assert.WithinDuration(t, now.Add(wait), d.NextAttemptAt, time.Second)
+		now = now.Add(wait)
+	}
+
+	n, err := s.ProcessDue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int32(3), calls.Load())
+
+	db.First(&d)
+	assert.Equal(t, models.WebhookDeliverySucceeded, d.Status)
+
+	// Gửi thành công => reset số lần lỗi liên tiếp
+	var endpoint models.WebhookEndpoint
+	db.First(&endpoint)
+	assert.Equal(t, 0, endpoint.FailureCount)
+}
+
+func TestWebhook_AutoDisableAfterRepeatedFailures(t *testing.T) {
+	cfg := DefaultWebhookConfig()
+	cfg.DisableAfter = 2
+	cfg.BaseBackoff = time.Second
+	db, s := setupWebhookService(t, cfg)
+	srv, _ := newReceiver(t, "0123456789abcdef0123", http.StatusGone, http.StatusGone, http.StatusGone)
+	createEndpoint(t, s, srv.URL)
+
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	publishUserCreated(t, s)
+	for i := 0; i < 3; i++ {
+		_, err := s.ProcessDue(context.Background())
+		assert.NoError(t, err)
+		now = now.Add(time.Hour)
+	}
+
+	var endpoint models.WebhookEndpoint
+	db.First(&endpoint)
+	assert.False(t, endpoint.Active)
+	assert.NotNil(t, endpoint.DisabledAt)
+
+	// Endpoint bị tắt => delivery bị huỷ, không gửi tiếp
+	var d models.WebhookDelivery
+	db.First(&d)
+	assert.Equal(t, models.WebhookDeliveryFailed, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+}
+
+func TestWebhook_SendTestEvent(t *testing.T) {
+	_, s := setupWebhookService(t, DefaultWebhookConfig())
+	srv, calls := newReceiver(t, "0123456789abcdef0123")
+	endpoint := createEndpoint(t, s, srv.URL)
+
+	delivery, err := s.SendTestEvent(context.Background(), strconv.Itoa(int(endpoint.ID)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, events.WebhookTest, delivery.EventType)
+	assert.Equal(t, string(models.WebhookDeliverySucceeded), delivery.Status)
+	assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+	assert.Equal(t, int32(1), calls.Load())
+
+	history, _ := s.GetDeliveryList(context.Background(), strconv.Itoa(int(endpoint.ID)), &pkg.Pagination{})
+	assert.Equal(t, int64(1), history.TotalRows)
+}
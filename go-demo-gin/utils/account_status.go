@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"go-demo-gin/models"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// AccountStatusError: mã lỗi khi tài khoản không được đăng nhập/dùng token theo trạng thái (nil => được phép).
+// Trạng thái rỗng (bản ghi cũ trước khi có cột status) được coi là active.
+func AccountStatusError(s models.UserStatus) *i18n.Message {
+	switch s {
+	case models.UserPending:
+		return ACCOUNT_PENDING
+	case models.UserDisabled:
+		return ACCOUNT_DISABLED
+	case models.UserLocked:
+		return ACCOUNT_LOCKED
+	}
+	return nil
+}
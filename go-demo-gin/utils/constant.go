@@ -34,7 +34,7 @@ var PASSWORD_ENCRYPTION_FAIL = &i18n.Message{
 
 var INVALID_PASSWORD = &i18n.Message{
 	ID:    "INVALID_PASSWORD",
-	Other: "Password must be 8–36 characters long and contain only lowercase letters, numbers, dots, or underscores",
+	Other: "Password does not meet the password policy (length or required character types)",
 }
 
 var PASSWORD_REQUIRE = &i18n.Message{
@@ -106,3 +106,363 @@ var DELETE_FAIL = &i18n.Message{
 	ID:    "DELETE_FAIL",
 	Other: "Delete failed",
 }
+
+var URL_REQUIRE = &i18n.Message{
+	ID:    "URL_REQUIRE",
+	Other: "URL is required",
+}
+
+var INVALID_URL = &i18n.Message{
+	ID:    "INVALID_URL",
+	Other: "URL must be a valid absolute URL",
+}
+
+var INVALID_EVENT_TYPE = &i18n.Message{
+	ID:    "INVALID_EVENT_TYPE",
+	Other: "Event types must contain at least one of: user.created, user.updated, user.deleted, user.role_changed",
+}
+
+var INVALID_SECRET = &i18n.Message{
+	ID:    "INVALID_SECRET",
+	Other: "Secret must be 16–128 characters long",
+}
+
+var INVALID_IDEMPOTENCY_KEY = &i18n.Message{
+	ID:    "INVALID_IDEMPOTENCY_KEY",
+	Other: "Idempotency-Key must not exceed 255 characters",
+}
+
+var IDEMPOTENCY_KEY_REUSED = &i18n.Message{
+	ID:    "IDEMPOTENCY_KEY_REUSED",
+	Other: "Idempotency-Key has already been used for a different request",
+}
+
+var IDEMPOTENCY_IN_PROGRESS = &i18n.Message{
+	ID:    "IDEMPOTENCY_IN_PROGRESS",
+	Other: "A request with the same Idempotency-Key is still being processed",
+}
+
+var TOO_MANY_REQUESTS = &i18n.Message{
+	ID:    "TOO_MANY_REQUESTS",
+	Other: "Too many requests, please try again later",
+}
+
+var NOT_CACHED = &i18n.Message{
+	ID:    "NOT_CACHED",
+	Other: "The requested response is not available in the cache",
+}
+
+var VALIDATION_FAILED = &i18n.Message{
+	ID:    "VALIDATION_FAILED",
+	Other: "One or more fields are invalid",
+}
+
+var INVALID_REQUEST_BODY = &i18n.Message{
+	ID:    "INVALID_REQUEST_BODY",
+	Other: "The request could not be parsed",
+}
+
+var INVALID_TOKEN = &i18n.Message{
+	ID:    "INVALID_TOKEN",
+	Other: "Token is invalid or has expired",
+}
+
+var CONFLICT = &i18n.Message{
+	ID:    "CONFLICT",
+	Other: "The request conflicts with the current state of the resource",
+}
+
+var QUERY_TOO_DEEP = &i18n.Message{
+	ID:    "QUERY_TOO_DEEP",
+	Other: "Query depth {{.Actual}} exceeds the limit of {{.Max}}",
+}
+
+var QUERY_TOO_COMPLEX = &i18n.Message{
+	ID:    "QUERY_TOO_COMPLEX",
+	Other: "Query complexity {{.Actual}} exceeds the limit of {{.Max}}",
+}
+
+var INVALID_IMPORT_FILE = &i18n.Message{
+	ID:    "INVALID_IMPORT_FILE",
+	Other: "File must be a CSV or XLSX file of at most 10MB",
+}
+
+var IMPORT_MISSING_COLUMNS = &i18n.Message{
+	ID:    "IMPORT_MISSING_COLUMNS",
+	Other: "Header row must contain the columns: username, password, role",
+}
+
+var IMPORT_TOO_MANY_ROWS = &i18n.Message{
+	ID:    "IMPORT_TOO_MANY_ROWS",
+	Other: "File must contain between 1 and 1000 data rows",
+}
+
+var IMPORT_SKIPPED = &i18n.Message{
+	ID:    "IMPORT_SKIPPED",
+	Other: "Row was not imported because other rows failed",
+}
+
+var EXPORT_COL_ID = &i18n.Message{
+	ID:    "EXPORT_COL_ID",
+	Other: "ID",
+}
+
+var EXPORT_COL_USERNAME = &i18n.Message{
+	ID:    "EXPORT_COL_USERNAME",
+	Other: "Username",
+}
+
+var EXPORT_COL_FULL_NAME = &i18n.Message{
+	ID:    "EXPORT_COL_FULL_NAME",
+	Other: "Full name",
+}
+
+var EXPORT_COL_ROLE = &i18n.Message{
+	ID:    "EXPORT_COL_ROLE",
+	Other: "Role",
+}
+
+var EXPORT_COL_BIRTHDAY = &i18n.Message{
+	ID:    "EXPORT_COL_BIRTHDAY",
+	Other: "Birthday",
+}
+
+var EXPORT_COL_CREATED_AT = &i18n.Message{
+	ID:    "EXPORT_COL_CREATED_AT",
+	Other: "Created at",
+}
+
+var EXPORT_COL_UPDATED_AT = &i18n.Message{
+	ID:    "EXPORT_COL_UPDATED_AT",
+	Other: "Updated at",
+}
+
+var BATCH_SIZE = &i18n.Message{
+	ID:    "BATCH_SIZE",
+	Other: "Operations must contain between 1 and 100 items",
+}
+
+var INVALID_BATCH_OP = &i18n.Message{
+	ID:    "INVALID_BATCH_OP",
+	Other: "Operation must be one of delete, restore, set_role, disable, activate, lock, unlock",
+}
+
+var BATCH_ROLLED_BACK = &i18n.Message{
+	ID:    "BATCH_ROLLED_BACK",
+	Other: "Operation was not applied because another operation in the batch failed",
+}
+
+var CANNOT_MODIFY_SELF = &i18n.Message{
+	ID:    "CANNOT_MODIFY_SELF",
+	Other: "You cannot delete, disable, lock or change the role of your own account",
+}
+
+var ACCOUNT_DISABLED = &i18n.Message{
+	ID:    "ACCOUNT_DISABLED",
+	Other: "Account is disabled",
+}
+
+var ACCOUNT_PENDING = &i18n.Message{
+	ID:    "ACCOUNT_PENDING",
+	Other: "Account is not activated yet",
+}
+
+var ACCOUNT_LOCKED = &i18n.Message{
+	ID:    "ACCOUNT_LOCKED",
+	Other: "Account is locked, please contact an administrator",
+}
+
+var INVALID_STATUS_TRANSITION = &i18n.Message{
+	ID:    "INVALID_STATUS_TRANSITION",
+	Other: "This status change is not allowed for the current account status",
+}
+
+var REASON_REQUIRE = &i18n.Message{
+	ID:    "REASON_REQUIRE",
+	Other: "Reason is required",
+}
+
+var EMAIL_REQUIRE = &i18n.Message{
+	ID:    "EMAIL_REQUIRE",
+	Other: "Email is required",
+}
+
+var INVALID_EMAIL = &i18n.Message{
+	ID:    "INVALID_EMAIL",
+	Other: "Invalid email address",
+}
+
+var DUPLICATE_EMAIL = &i18n.Message{
+	ID:    "DUPLICATE_EMAIL",
+	Other: "Email is already in use",
+}
+
+var INVALID_VERIFICATION_TOKEN = &i18n.Message{
+	ID:    "INVALID_VERIFICATION_TOKEN",
+	Other: "Invalid verification link",
+}
+
+var VERIFICATION_EXPIRED = &i18n.Message{
+	ID:    "VERIFICATION_EXPIRED",
+	Other: "Verification link has expired, please request a new one",
+}
+
+var VERIFICATION_THROTTLED = &i18n.Message{
+	ID:    "VERIFICATION_THROTTLED",
+	Other: "Verification email was sent recently, please try again later",
+}
+
+var VERIFY_EMAIL_SUBJECT = &i18n.Message{
+	ID:    "VERIFY_EMAIL_SUBJECT",
+	Other: "Verify your email address",
+}
+
+var VERIFY_EMAIL_BODY = &i18n.Message{
+	ID:    "VERIFY_EMAIL_BODY",
+	Other: "Hi {{.Username}},\n\nOpen the link below to activate your account (valid until {{.ExpiresAt}}):\n{{.Link}}\n\nIf you did not sign up, please ignore this email.",
+}
+
+var OIDC_PROVIDER_NOT_FOUND = &i18n.Message{
+	ID:    "OIDC_PROVIDER_NOT_FOUND",
+	Other: "Unknown identity provider",
+}
+
+var OIDC_PROVIDER_UNAVAILABLE = &i18n.Message{
+	ID:    "OIDC_PROVIDER_UNAVAILABLE",
+	Other: "Identity provider is unavailable, please try again later",
+}
+
+var OIDC_INVALID_STATE = &i18n.Message{
+	ID:    "OIDC_INVALID_STATE",
+	Other: "Login session is invalid or has expired, please start again",
+}
+
+var OIDC_LOGIN_FAILED = &i18n.Message{
+	ID:    "OIDC_LOGIN_FAILED",
+	Other: "Login with the identity provider failed",
+}
+
+var OIDC_NOT_PROVISIONED = &i18n.Message{
+	ID:    "OIDC_NOT_PROVISIONED",
+	Other: "No account is linked to this identity",
+}
+
+var INVALID_API_KEY = &i18n.Message{
+	ID:    "INVALID_API_KEY",
+	Other: "Invalid or revoked API key",
+}
+
+var API_KEY_EXPIRED = &i18n.Message{
+	ID:    "API_KEY_EXPIRED",
+	Other: "API key has expired",
+}
+
+var INSUFFICIENT_SCOPE = &i18n.Message{
+	ID:    "INSUFFICIENT_SCOPE",
+	Other: "API key does not have the required scope",
+}
+
+var NAME_REQUIRE = &i18n.Message{
+	ID:    "NAME_REQUIRE",
+	Other: "Name is required",
+}
+
+var INVALID_SCOPE = &i18n.Message{
+	ID:    "INVALID_SCOPE",
+	Other: "Invalid scope",
+}
+
+var SESSION_REVOKED = &i18n.Message{
+	ID:    "SESSION_REVOKED",
+	Other: "Session has been revoked or expired, please log in again",
+}
+
+var PASSWORD_BREACHED = &i18n.Message{
+	ID:    "PASSWORD_BREACHED",
+	Other: "This password has appeared in a data breach, please choose another one",
+}
+
+var PASSWORD_CONTAINS_USERNAME = &i18n.Message{
+	ID:    "PASSWORD_CONTAINS_USERNAME",
+	Other: "Password must not contain the username",
+}
+
+var PASSWORD_REUSED = &i18n.Message{
+	ID:    "PASSWORD_REUSED",
+	Other: "Password was used recently, please choose a new one",
+}
+
+var IMPERSONATION_FORBIDDEN = &i18n.Message{
+	ID:    "IMPERSONATION_FORBIDDEN",
+	Other: "This action is not allowed while impersonating another user",
+}
+
+var CANNOT_IMPERSONATE = &i18n.Message{
+	ID:    "CANNOT_IMPERSONATE",
+	Other: "This user cannot be impersonated",
+}
+
+var TENANT_MISMATCH = &i18n.Message{
+	ID:    "TENANT_MISMATCH",
+	Other: "Your account does not belong to the requested tenant",
+}
+
+var UNKNOWN_TENANT = &i18n.Message{
+	ID:    "UNKNOWN_TENANT",
+	Other: "Tenant does not exist or is disabled",
+}
+
+var INVALID_TENANT_SLUG = &i18n.Message{
+	ID:    "INVALID_TENANT_SLUG",
+	Other: "Slug must be 2-63 lowercase letters, digits or hyphens",
+}
+
+var DUPLICATE_TENANT_SLUG = &i18n.Message{
+	ID:    "DUPLICATE_TENANT_SLUG",
+	Other: "Slug is already in use",
+}
+
+var MEMBERSHIP_FORBIDDEN = &i18n.Message{
+	ID:    "MEMBERSHIP_FORBIDDEN",
+	Other: "You are not allowed to manage this organization or team",
+}
+
+var NOT_ORG_MEMBER = &i18n.Message{
+	ID:    "NOT_ORG_MEMBER",
+	Other: "User is not a member of the organization",
+}
+
+var ALREADY_MEMBER = &i18n.Message{
+	ID:    "ALREADY_MEMBER",
+	Other: "User is already a member",
+}
+
+var LAST_OWNER = &i18n.Message{
+	ID:    "LAST_OWNER",
+	Other: "An organization must keep at least one owner",
+}
+
+var INVALID_INVITATION = &i18n.Message{
+	ID:    "INVALID_INVITATION",
+	Other: "Invitation is invalid, expired or already used",
+}
+
+var INVITATION_EMAIL_MISMATCH = &i18n.Message{
+	ID:    "INVITATION_EMAIL_MISMATCH",
+	Other: "Invitation was sent to a different email address",
+}
+
+var INVITATION_SUBJECT = &i18n.Message{
+	ID:    "INVITATION_SUBJECT",
+	Other: "You have been invited to {{.Organization}}",
+}
+
+var INVITATION_BODY = &i18n.Message{
+	ID:    "INVITATION_BODY",
+	Other: "Hi,\n\n{{.Inviter}} invited you to join {{.Organization}} as {{.Role}}. Open the link below to accept (valid until {{.ExpiresAt}}):\n{{.Link}}\n\nIf you were not expecting this invitation, please ignore this email.",
+}
+
+var EMAIL_NOT_VERIFIED = &i18n.Message{
+	ID:    "EMAIL_NOT_VERIFIED",
+	Other: "Email address must be verified before accepting the invitation",
+}
@@ -19,3 +19,43 @@ func InformationFrom(ctx context.Context) *models.User {
 	}
 	return nil // hoặc trả về localizer mặc định nếu bạn muốn
 }
+
+type apiKeyKey struct{}
+
+// WithAPIKey: request được xác thực bằng personal API key (dùng để kiểm tra scope)
+func WithAPIKey(ctx context.Context, k *models.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, k)
+}
+
+// APIKeyFrom: nil nếu request xác thực bằng JWT
+func APIKeyFrom(ctx context.Context) *models.APIKey {
+	k, _ := ctx.Value(apiKeyKey{}).(*models.APIKey)
+	return k
+}
+
+type sessionKey struct{}
+
+// WithSessionID: session (claim "sid" của JWT) của request hiện tại
+func WithSessionID(ctx context.Context, sid uint) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sid)
+}
+
+// SessionIDFrom: 0 nếu request không gắn với session (API key, token cũ)
+func SessionIDFrom(ctx context.Context) uint {
+	sid, _ := ctx.Value(sessionKey{}).(uint)
+	return sid
+}
+
+type impersonatorKey struct{}
+
+// WithImpersonator: admin đang đăng nhập dưới danh nghĩa user của request (claim "act" của JWT);
+// InformationFrom vẫn trả về user bị impersonate
+func WithImpersonator(ctx context.Context, actor *models.User) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, actor)
+}
+
+// ImpersonatorFrom: nil nếu request không phải impersonation
+func ImpersonatorFrom(ctx context.Context) *models.User {
+	u, _ := ctx.Value(impersonatorKey{}).(*models.User)
+	return u
+}
@@ -0,0 +1,25 @@
+package utils
+
+import "context"
+
+type requestKey struct{}
+
+// RequestInfo: thông tin của request hiện tại (ID logging, IP, User-Agent)
+type RequestInfo struct {
+	ID        string
+	IP        string
+	UserAgent string
+}
+
+func WithRequestInfo(ctx context.Context, r *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+func RequestInfoFrom(ctx context.Context) *RequestInfo {
+	if v := ctx.Value(requestKey{}); v != nil {
+		if r, ok := v.(*RequestInfo); ok && r != nil {
+			return r
+		}
+	}
+	return nil
+}
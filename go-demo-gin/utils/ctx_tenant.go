@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"context"
+	"go-demo-gin/models"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// TenantScope: tenant mà dữ liệu của request bị giới hạn trong đó
+type TenantScope struct {
+	ID uint
+	// Client tự chọn tenant (header X-Tenant, subdomain) thay vì dùng tenant mặc định
+	Requested bool
+}
+
+type tenantKey struct{}
+type allTenantsKey struct{}
+
+// WithTenant: repo (repo.RegisterTenantScope) tự lọc/gán tenant_id theo tenant này
+func WithTenant(ctx context.Context, t TenantScope) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+func TenantFrom(ctx context.Context) (TenantScope, bool) {
+	t, ok := ctx.Value(tenantKey{}).(TenantScope)
+	return t, ok
+}
+
+// AllTenants bỏ lọc theo tenant; chỉ dùng có chủ đích (vd: tìm chủ của API key trước khi biết tenant)
+func AllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allTenantsKey{}, true)
+}
+
+// TenantIDFrom: tenant dùng để lọc dữ liệu; false => không lọc (job hệ thống, AllTenants)
+func TenantIDFrom(ctx context.Context) (uint, bool) {
+	if all, _ := ctx.Value(allTenantsKey{}).(bool); all {
+		return 0, false
+	}
+	t, ok := TenantFrom(ctx)
+	if !ok || t.ID == 0 {
+		return 0, false
+	}
+	return t.ID, true
+}
+
+// ScopeToUser giới hạn request đã xác thực trong tenant của user. Client chọn tenant khác
+// (header/subdomain) thì chỉ super admin được chuyển sang tenant đó, user khác => TENANT_MISMATCH.
+func ScopeToUser(ctx context.Context, u *models.User) (context.Context, *i18n.Message) {
+	if t, ok := TenantFrom(ctx); ok && t.Requested && t.ID != u.TenantID {
+		if u.Role != models.RoleSuperAdmin {
+			return ctx, TENANT_MISMATCH
+		}
+		return WithTenant(ctx, t), nil
+	}
+	return WithTenant(ctx, TenantScope{ID: u.TenantID}), nil
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	errorResponse "go-demo-gin/responses/error"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// NewProblem dịch HTTPError theo ngôn ngữ của request và tạo body problem+json
+func NewProblem(c *gin.Context, e *errorResponse.HTTPError) *errorResponse.Problem {
+	ctx := c.Request.Context()
+	localizer := LocalizerFrom(ctx)
+
+	code := e.Code
+	if code == nil {
+		code = INTERNAL_ERROR
+	}
+	p := &errorResponse.Problem{
+		Type:     "/problems/" + strings.ToLower(strings.ReplaceAll(code.ID, "_", "-")),
+		Title:    http.StatusText(e.StatusCode),
+		Status:   e.StatusCode,
+		Detail:   LoadI18nMessage(localizer, code, nil),
+		Instance: c.Request.URL.Path,
+		Code:     code.ID,
+	}
+	if info := RequestInfoFrom(ctx); info != nil {
+		p.RequestID = info.ID
+	}
+
+	// Sắp xếp theo tên field để body ổn định
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		p.Errors = append(p.Errors, errorResponse.FieldError{
+			Field:  field,
+			Code:   e.Fields[field].ID,
+			Detail: LoadI18nMessage(localizer, e.Fields[field], nil),
+		})
+	}
+	return p
+}
+
+// RenderProblem ghi HTTPError dưới dạng application/problem+json và dừng chain
+func RenderProblem(c *gin.Context, e *errorResponse.HTTPError) {
+	// gin chỉ set Content-Type khi header còn trống
+	c.Header("Content-Type", errorResponse.ProblemContentType)
+	c.AbortWithStatusJSON(e.StatusCode, NewProblem(c, e))
+}
+
+// AbortWithProblem dùng trong middleware: trả lỗi ngay với status và mã lỗi
+func AbortWithProblem(c *gin.Context, status int, code *i18n.Message) {
+	RenderProblem(c, &errorResponse.HTTPError{StatusCode: status, Code: code})
+}
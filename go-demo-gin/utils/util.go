@@ -2,12 +2,15 @@ package utils
 
 import (
 	"context"
+	"errors"
+	"go-demo-gin/apperror"
 	"go-demo-gin/pkg"
-	errorResponse "go-demo-gin/responses/error"
 	"math"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"gorm.io/gorm"
 )
@@ -23,10 +26,14 @@ func TxFrom(ctx context.Context) (*gorm.DB, bool) {
 }
 
 func LoadI18nMessage(localizer *i18n.Localizer, message *i18n.Message, data map[string]any) string {
+	if localizer == nil {
+		return message.Other
+	}
+	// Không đặt PluralCount: message chỉ có dạng "other". PluralCount -1 => tiếng Anh chọn dạng "one" (theo |n|),
+	// Localize trả lỗi và hàm này rơi về message.Other chưa render template/chưa dịch
 	msg, err := localizer.Localize(&i18n.LocalizeConfig{
 		DefaultMessage: message,
 		TemplateData:   data,
-		PluralCount:    -1,
 	})
 	if err != nil {
 		return message.Other
@@ -43,30 +50,60 @@ func Paginate(pagination *pkg.Pagination, db *gorm.DB) func(db *gorm.DB) *gorm.D
 	}
 }
 
+// Lỗi đọc body/query: lỗi binding tag => lỗi theo field, còn lại (JSON hỏng...) => INVALID_REQUEST_BODY
 func HandleBindError(c *gin.Context, err error) {
-	c.Error(&errorResponse.HTTPError{
-		StatusCode: http.StatusBadRequest,
-		Message: errorResponse.Error{
-			Error: map[string]string{
-				"message": err.Error(),
-			},
-		}})
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make(map[string]*i18n.Message, len(verrs))
+		for _, fe := range verrs {
+			fields[strings.ToLower(fe.Field())] = INVALID_VALUE
+		}
+		HandleValidationError(c, fields)
+		return
+	}
+	c.Error(apperror.New(apperror.KindValidation, INVALID_REQUEST_BODY, err))
+}
+
+func HandleValidationError(c *gin.Context, fields map[string]*i18n.Message) {
+	c.Error(apperror.Validation(VALIDATION_FAILED, fields))
+}
+
+// Lỗi từ service (apperror) được ErrorHandler chuyển thành status + problem+json
+func HandleServiceError(c *gin.Context, err error) {
+	c.Error(err)
+}
+
+// Mã lỗi mặc định theo loại lỗi nghiệp vụ, dùng chung cho HTTP (ErrorHandler) và gRPC
+var kindCode = map[apperror.Kind]*i18n.Message{
+	apperror.KindNotFound:        NOT_FOUND,
+	apperror.KindConflict:        CONFLICT,
+	apperror.KindValidation:      VALIDATION_FAILED,
+	apperror.KindUnauthorized:    AUTHEN_REQUIRE,
+	apperror.KindForbidden:       PERMISSION_REQUIRE,
+	apperror.KindTooManyRequests: TOO_MANY_REQUESTS,
+	apperror.KindInternal:        INTERNAL_ERROR,
 }
 
-func HandleValidationError(c *gin.Context, err map[string]string) {
-	c.Error(&errorResponse.HTTPError{
-		StatusCode: http.StatusBadRequest,
-		Message: errorResponse.Error{
-			Error: err,
-		}})
+// Điểm duy nhất chuyển loại lỗi nghiệp vụ sang HTTP status (ErrorHandler, kết quả từng phần tử của batch)
+var kindStatus = map[apperror.Kind]int{
+	apperror.KindNotFound:        http.StatusNotFound,
+	apperror.KindConflict:        http.StatusConflict,
+	apperror.KindValidation:      http.StatusBadRequest,
+	apperror.KindUnauthorized:    http.StatusUnauthorized,
+	apperror.KindForbidden:       http.StatusForbidden,
+	apperror.KindTooManyRequests: http.StatusTooManyRequests,
+	apperror.KindInternal:        http.StatusInternalServerError,
 }
 
-func HandleServiceError(c *gin.Context, status int, msg string) {
-	c.Error(&errorResponse.HTTPError{
-		StatusCode: status,
-		Message: errorResponse.Error{
-			Error: map[string]string{
-				"message": msg,
-			},
-		}})
+// HTTPStatus: HTTP status tương ứng với loại lỗi nghiệp vụ
+func HTTPStatus(e *apperror.Error) int {
+	return kindStatus[e.Kind]
+}
+
+// ErrorCode: mã lỗi của lỗi nghiệp vụ (Code do service chọn, nếu không có thì mã mặc định theo Kind)
+func ErrorCode(e *apperror.Error) *i18n.Message {
+	if e.Code != nil {
+		return e.Code
+	}
+	return kindCode[e.Kind]
 }
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestLoadI18nMessage_UsesBundleAndRendersTemplate(t *testing.T) {
+	b := i18n.NewBundle(language.English)
+	b.MustAddMessages(language.English,
+		&i18n.Message{ID: "SAVED", Other: "Saved"},
+		&i18n.Message{ID: "HELLO", Other: "Hi {{.Name}}"},
+	)
+	b.MustAddMessages(language.Vietnamese,
+		&i18n.Message{ID: "SAVED", Other: "Đã lưu"},
+		&i18n.Message{ID: "HELLO", Other: "Chào {{.Name}}"},
+	)
+	saved := &i18n.Message{ID: "SAVED", Other: "saved (default)"}
+	hello := &i18n.Message{ID: "HELLO", Other: "Hi {{.Name}}"}
+
+	en := i18n.NewLocalizer(b, "en")
+	vi := i18n.NewLocalizer(b, "vi")
+	// Bản dịch trong bundle được dùng cho mọi ngôn ngữ (kể cả tiếng Anh), không phải message mặc định
+	assert.Equal(t, "Saved", LoadI18nMessage(en, saved, nil))
+	assert.Equal(t, "Đã lưu", LoadI18nMessage(vi, saved, nil))
+	// Template được render
+	assert.Equal(t, "Hi An", LoadI18nMessage(en, hello, map[string]any{"Name": "An"}))
+	assert.Equal(t, "Chào An", LoadI18nMessage(vi, hello, map[string]any{"Name": "An"}))
+	// Không có localizer => message mặc định
+	assert.Equal(t, "saved (default)", LoadI18nMessage(nil, saved, nil))
+}
@@ -3,18 +3,27 @@ package utils
 import (
 	"context"
 	"go-demo-gin/models"
+	"go-demo-gin/pkg/password"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"gorm.io/gorm"
 )
 
 type ctxKeyUpdateID struct{}
 
+// WithUpdateID gắn ID của user đang sửa (dạng chuỗi từ URL) cho các rule cần loại trừ/tra cứu chính user đó
 func WithUpdateID(ctx context.Context, id string) context.Context {
-	return context.WithValue(ctx, ctxKeyUpdateID{}, id)
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKeyUpdateID{}, uint(n))
 }
 
 func UpdateIDFrom(ctx context.Context) (uint, bool) {
@@ -27,70 +36,137 @@ func UpdateIDFrom(ctx context.Context) (uint, bool) {
 }
 
 type Validator struct {
-	db *gorm.DB
-	v  *validator.Validate
+	db     *gorm.DB
+	v      *validator.Validate
+	policy password.Policy
 }
 
 func NewValidator(db *gorm.DB) *Validator {
 	v := validator.New()
-	val := &Validator{db: db, v: v}
+	val := &Validator{db: db, v: v, policy: password.DefaultPolicy()}
 
 	// các rule tĩnh của bạn
 	_ = v.RegisterValidation("role", val.roleValidator)
-	_ = v.RegisterValidation("hashed", val.hashedValidator)
 	_ = v.RegisterValidation("password", val.passwordValidator)
+	_ = v.RegisterValidation("notBreached", val.notBreachedValidator)
 	_ = v.RegisterValidation("username", val.usernameValidator)
 	_ = v.RegisterValidation("birthday", val.birthdayValidator)
+	_ = v.RegisterValidation("tenantSlug", val.tenantSlugValidator)
 
 	// ✅ rule trùng username có context (timeout/cancel, dùng chung TX)
 	_ = v.RegisterValidationCtx("duplicateUsername", val.duplicateUsernameCtx)
+	_ = v.RegisterValidationCtx("duplicateEmail", val.duplicateEmailCtx)
+	_ = v.RegisterValidationCtx("notUsername", val.notUsernameCtx)
+	_ = v.RegisterValidationCtx("notReused", val.notReusedCtx)
 
 	return val
 }
 
-func (val *Validator) ValidateStructCtx(ctx context.Context, s any) map[string]string {
+// SetPasswordPolicy thay chính sách mật khẩu mặc định (password.DefaultPolicy)
+func (val *Validator) SetPasswordPolicy(p password.Policy) {
+	val.policy = p
+}
+
+// ValidateStructCtx trả về lỗi theo field (message i18n, chỉ dịch khi render response)
+func (val *Validator) ValidateStructCtx(ctx context.Context, s any) map[string]*i18n.Message {
 	cctx, cancel := context.WithTimeout(ctx, 700*time.Millisecond)
 	defer cancel()
 
 	if err := val.v.StructCtx(cctx, s); err != nil {
 		if verrs, ok := err.(validator.ValidationErrors); ok {
-			// Lấy localizer cho i18n
-			localizer := LocalizerFrom(ctx)
-			errorsMap := make(map[string]string)
+			errorsMap := make(map[string]*i18n.Message)
 			for _, fe := range verrs {
 				field := fe.StructField()
 				tag := fe.Tag()
+				// Lỗi của phần tử trong slice (dive): "EventTypes[0]" => "EventTypes"
+				if i := strings.IndexByte(field, '['); i >= 0 {
+					field = field[:i]
+				}
 
 				switch field {
 				case "Username":
 					switch tag {
 					case "required":
-						errorsMap["username"] = LoadI18nMessage(localizer, USERNAME_REQUIRE, nil)
+						errorsMap["username"] = USERNAME_REQUIRE
 					case "username":
-						errorsMap["username"] = LoadI18nMessage(localizer, INVALID_USERNAME, nil)
+						errorsMap["username"] = INVALID_USERNAME
 					case "duplicateUsername":
-						errorsMap["username"] = LoadI18nMessage(localizer, DUPLICATE_USERNAME, nil)
+						errorsMap["username"] = DUPLICATE_USERNAME
+					}
+				case "Mail", "Email":
+					switch tag {
+					case "required":
+						errorsMap["email"] = EMAIL_REQUIRE
+					case "duplicateEmail":
+						errorsMap["email"] = DUPLICATE_EMAIL
+					default:
+						errorsMap["email"] = INVALID_EMAIL
 					}
 				case "Pass":
 					switch tag {
 					case "required":
-						errorsMap["password"] = LoadI18nMessage(localizer, PASSWORD_REQUIRE, nil)
+						errorsMap["password"] = PASSWORD_REQUIRE
 					case "password":
-						errorsMap["password"] = LoadI18nMessage(localizer, INVALID_PASSWORD, nil)
-					case "hashed":
-						errorsMap["password"] = LoadI18nMessage(localizer, PASSWORD_ENCRYPTION_FAIL, nil)
+						errorsMap["password"] = INVALID_PASSWORD
+					case "notBreached":
+						errorsMap["password"] = PASSWORD_BREACHED
+					case "notUsername":
+						errorsMap["password"] = PASSWORD_CONTAINS_USERNAME
+					case "notReused":
+						errorsMap["password"] = PASSWORD_REUSED
 					}
 				case "Role":
 					switch tag {
-					case "required":
-						errorsMap["role"] = LoadI18nMessage(localizer, ROLE_REQUIRE, nil)
-					case "role":
-						errorsMap["role"] = LoadI18nMessage(localizer, INVALID_ROLE, nil)
+					case "required", "required_if":
+						errorsMap["role"] = ROLE_REQUIRE
+					case "role", "oneof":
+						errorsMap["role"] = INVALID_ROLE
 					}
 				case "Date":
-					errorsMap["birthday"] = LoadI18nMessage(localizer, INVALID_BIRTHDAY, nil)
+					errorsMap["birthday"] = INVALID_BIRTHDAY
+				case "URL":
+					switch tag {
+					case "required":
+						errorsMap["url"] = URL_REQUIRE
+					default:
+						errorsMap["url"] = INVALID_URL
+					}
+				case "EventTypes":
+					errorsMap["event_types"] = INVALID_EVENT_TYPE
+				case "Secret":
+					errorsMap["secret"] = INVALID_SECRET
+				case "Reason":
+					switch tag {
+					case "required", "required_if":
+						errorsMap["reason"] = REASON_REQUIRE
+					default:
+						errorsMap["reason"] = INVALID_VALUE
+					}
+				case "Mode":
+					errorsMap["mode"] = INVALID_VALUE
+				case "Operations":
+					errorsMap["operations"] = BATCH_SIZE
+				case "Op":
+					errorsMap["op"] = INVALID_BATCH_OP
+				case "ID":
+					errorsMap["id"] = INVALID_VALUE
+				case "Name":
+					switch tag {
+					case "required":
+						errorsMap["name"] = NAME_REQUIRE
+					default:
+						errorsMap["name"] = INVALID_VALUE
+					}
+				case "Scopes":
+					errorsMap["scopes"] = INVALID_SCOPE
+				case "Slug":
+					errorsMap["slug"] = INVALID_TENANT_SLUG
+				case "Token":
+					errorsMap["token"] = INVALID_INVITATION
+				case "ExpiresInDays":
+					errorsMap["expires_in_days"] = INVALID_VALUE
 				default:
-					errorsMap[field] = LoadI18nMessage(localizer, INVALID_VALUE, nil)
+					errorsMap[field] = INVALID_VALUE
 				}
 			}
 
@@ -110,17 +186,54 @@ func (v *Validator) roleValidator(fl validator.FieldLevel) bool {
 	}
 }
 
-func (v *Validator) hashedValidator(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-	_, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return err == nil
+// Độ dài và loại ký tự theo chính sách
+func (v *Validator) passwordValidator(fl validator.FieldLevel) bool {
+	return v.policy.Check(fl.Field().String()) == nil
 }
 
-func (v *Validator) passwordValidator(fl validator.FieldLevel) bool {
-	password := fl.Field().String()
-	// Regex: chỉ cho phép chữ thường, số, dấu chấm, gạch dưới; 3–24 ký tự
-	re := regexp.MustCompile(`^[a-z0-9_.]{8,36}$`)
-	return re.MatchString(password)
+func (v *Validator) notBreachedValidator(fl validator.FieldLevel) bool {
+	return !v.policy.Breached(fl.Field().String())
+}
+
+// Không chứa username: lấy từ field Username cùng struct (tạo, đăng ký), hoặc từ user đang sửa (WithUpdateID)
+func (val *Validator) notUsernameCtx(ctx context.Context, fl validator.FieldLevel) bool {
+	if !val.policy.DisallowUsername {
+		return true
+	}
+	var username string
+	if f := reflect.Indirect(fl.Parent()).FieldByName("Username"); f.IsValid() && f.Kind() == reflect.String {
+		username = f.String()
+	} else if id, ok := UpdateIDFrom(ctx); ok {
+		_ = val.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Select("username").Scan(&username).Error
+	}
+	return !val.policy.ContainsUsername(fl.Field().String(), username)
+}
+
+// Không trùng mật khẩu hiện tại và N mật khẩu gần nhất của user đang sửa (WithUpdateID)
+func (val *Validator) notReusedCtx(ctx context.Context, fl validator.FieldLevel) bool {
+	depth := val.policy.HistoryDepth()
+	id, ok := UpdateIDFrom(ctx)
+	if depth == 0 || !ok {
+		return true
+	}
+
+	var hashes []string
+	if err := val.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Pluck("password", &hashes).Error; err != nil {
+		return false
+	}
+	var history []string
+	if err := val.db.WithContext(ctx).Model(&models.PasswordHistory{}).
+		Where("user_id = ?", id).Order("id desc").Limit(depth).Pluck("hash", &history).Error; err != nil {
+		return false
+	}
+
+	plain := fl.Field().String()
+	for _, h := range append(hashes, history...) {
+		if match, _, _ := password.Verify(h, plain); match {
+			return false
+		}
+	}
+	return true
 }
 
 func (v *Validator) usernameValidator(fl validator.FieldLevel) bool {
@@ -130,10 +243,21 @@ func (v *Validator) usernameValidator(fl validator.FieldLevel) bool {
 	return re.MatchString(username)
 }
 
+// Slug của tenant là 1 nhãn DNS (dùng làm subdomain): chữ thường, số, gạch ngang; 2–63 ký tự
+var tenantSlugRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,61}[a-z0-9]$`)
+
+func (v *Validator) tenantSlugValidator(fl validator.FieldLevel) bool {
+	return tenantSlugRe.MatchString(fl.Field().String())
+}
+
 func (val *Validator) duplicateUsernameCtx(ctx context.Context, fl validator.FieldLevel) bool {
 	username := fl.Field().String()
 
+	// Username chỉ cần duy nhất trong tenant của request
 	q := val.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username)
+	if tenantID, ok := TenantIDFrom(ctx); ok {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
 	if currID, ok := UpdateIDFrom(ctx); ok { // 👈 lấy ID đã gắn
 		q = q.Where("id <> ?", currID)
 	}
@@ -142,6 +266,21 @@ func (val *Validator) duplicateUsernameCtx(ctx context.Context, fl validator.Fie
 	return q.Count(&count).Error == nil && count == 0
 }
 
+func (val *Validator) duplicateEmailCtx(ctx context.Context, fl validator.FieldLevel) bool {
+	email := fl.Field().String()
+
+	q := val.db.WithContext(ctx).Model(&models.User{}).Where("LOWER(email) = LOWER(?)", email)
+	if tenantID, ok := TenantIDFrom(ctx); ok {
+		q = q.Where("tenant_id = ?", tenantID)
+	}
+	if currID, ok := UpdateIDFrom(ctx); ok {
+		q = q.Where("id <> ?", currID)
+	}
+
+	var count int64
+	return q.Count(&count).Error == nil && count == 0
+}
+
 func (v *Validator) birthdayValidator(fl validator.FieldLevel) bool {
 	birthdayStr := fl.Field().String()
 	birthday, err := time.Parse("2006-01-02", birthdayStr)